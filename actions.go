@@ -6,7 +6,6 @@ package charm
 import (
 	"fmt"
 	"io"
-	"io/ioutil"
 	"regexp"
 	"strings"
 
@@ -95,11 +94,14 @@ func (spec *ActionSpec) InsertDefaults(target map[string]interface{}) (map[strin
 }
 
 // ReadActionsYaml builds an Actions spec from a charm's actions.yaml.
-func ReadActionsYaml(r io.Reader) (*Actions, error) {
-	data, err := ioutil.ReadAll(r)
+func ReadActionsYaml(r io.Reader) (_ *Actions, err error) {
+	data, err := readYAMLInput(r)
 	if err != nil {
 		return nil, err
 	}
+	defer func() {
+		reportParseFailure(ActionsFile, data, err)
+	}()
 
 	result := &Actions{
 		ActionSpecs: map[string]ActionSpec{},
@@ -0,0 +1,113 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/juju/errors"
+)
+
+// FeatureInfo describes a controller capability that a charm may
+// require in an assumes block, either on its own, as in "secrets", or
+// constrained by version, as in "juju >= 2.9".
+type FeatureInfo struct {
+	// Name is the feature name.
+	Name string
+
+	// Description is a short human-readable summary of the feature.
+	Description string
+
+	// Versioned reports whether the feature may be constrained by
+	// version.
+	Versioned bool
+}
+
+// knownFeatures holds the features known to be provided by Juju
+// controllers, keyed by feature name.
+var knownFeatures = map[string]FeatureInfo{
+	"juju": {
+		Name:        "juju",
+		Description: "The version of the Juju controller.",
+		Versioned:   true,
+	},
+	"k8s-api": {
+		Name:        "k8s-api",
+		Description: "Access to the Kubernetes API of the cluster the charm runs in.",
+		Versioned:   true,
+	},
+	"secrets": {
+		Name:        "secrets",
+		Description: "Support for storing and sharing secrets.",
+	},
+}
+
+// KnownFeatures returns the features known to be provided by Juju
+// controllers, sorted by name.
+func KnownFeatures() []FeatureInfo {
+	result := make([]FeatureInfo, 0, len(knownFeatures))
+	for _, info := range knownFeatures {
+		result = append(result, info)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// LookupFeature returns the information for the named feature, and
+// whether it is known.
+func LookupFeature(name string) (FeatureInfo, bool) {
+	info, ok := knownFeatures[name]
+	return info, ok
+}
+
+var (
+	featureExpressionRE = regexp.MustCompile(`^([a-z][a-z0-9-]*)(?:\s*(>=|<)\s*(\S+))?$`)
+	featureVersionRE    = regexp.MustCompile(`^[0-9]+(\.[0-9]+){0,2}$`)
+)
+
+// maxFeatureSuggestionDistance is the largest edit distance at which
+// a known feature is suggested for an unknown one.
+const maxFeatureSuggestionDistance = 2
+
+// CheckFeatureExpression checks a single expression of an assumes
+// block, such as "juju >= 2.9" or "k8s-api". It returns an error if
+// the expression is malformed, and warnings if it names a feature that
+// is not known, so that typos may be caught.
+func CheckFeatureExpression(expr string) (warnings []string, err error) {
+	match := featureExpressionRE.FindStringSubmatch(expr)
+	if match == nil {
+		return nil, errors.NotValidf("feature expression %q", expr)
+	}
+	name, constraint, version := match[1], match[2], match[3]
+	if constraint != "" && !featureVersionRE.MatchString(version) {
+		return nil, errors.NotValidf("version %q in feature expression %q", version, expr)
+	}
+	info, ok := knownFeatures[name]
+	if !ok {
+		msg := fmt.Sprintf("unknown feature %q", name)
+		if suggestion, ok := suggestFeature(name); ok {
+			msg += fmt.Sprintf(" (did you mean %q?)", suggestion)
+		}
+		return []string{msg}, nil
+	}
+	if constraint != "" && !info.Versioned {
+		return nil, errors.NotValidf("version constraint on unversioned feature %q", name)
+	}
+	return nil, nil
+}
+
+func suggestFeature(name string) (string, bool) {
+	best, bestDistance := "", maxFeatureSuggestionDistance+1
+	for candidate := range knownFeatures {
+		d := editDistance(name, candidate)
+		if d < bestDistance || (d == bestDistance && candidate < best) {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best, best != ""
+}
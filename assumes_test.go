@@ -0,0 +1,71 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type AssumesSuite struct{}
+
+var _ = gc.Suite(&AssumesSuite{})
+
+func (s *AssumesSuite) TestKnownFeatures(c *gc.C) {
+	var names []string
+	for _, info := range charm.KnownFeatures() {
+		names = append(names, info.Name)
+	}
+	c.Assert(names, jc.DeepEquals, []string{"juju", "k8s-api", "secrets"})
+
+	info, ok := charm.LookupFeature("juju")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(info.Versioned, jc.IsTrue)
+	_, ok = charm.LookupFeature("jjuu")
+	c.Assert(ok, jc.IsFalse)
+}
+
+var checkFeatureExpressionTests = []struct {
+	expr     string
+	warnings []string
+	err      string
+}{{
+	expr: "juju >= 2.9",
+}, {
+	expr: "juju<3",
+}, {
+	expr: "k8s-api",
+}, {
+	expr: "secrets",
+}, {
+	expr:     "k8s-apy",
+	warnings: []string{`unknown feature "k8s-apy" (did you mean "k8s-api"?)`},
+}, {
+	expr:     "gpu",
+	warnings: []string{`unknown feature "gpu"`},
+}, {
+	expr: "juju >= two",
+	err:  `version "two" in feature expression "juju >= two" not valid`,
+}, {
+	expr: "secrets >= 1.0",
+	err:  `version constraint on unversioned feature "secrets" not valid`,
+}, {
+	expr: "juju == 2.9",
+	err:  `feature expression "juju == 2.9" not valid`,
+}}
+
+func (s *AssumesSuite) TestCheckFeatureExpression(c *gc.C) {
+	for i, test := range checkFeatureExpressionTests {
+		c.Logf("test %d: %q", i, test.expr)
+		warnings, err := charm.CheckFeatureExpression(test.expr)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(warnings, jc.DeepEquals, test.warnings)
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/systems"
+	"github.com/juju/systems/channel"
+)
+
+// Base identifies an operating system release that a charm can run on,
+// such as "ubuntu@22.04", optionally restricted to some architectures.
+// Bases replace series, which name releases of Ubuntu only.
+type Base struct {
+	// Name holds the name of the operating system, such as "ubuntu".
+	Name string `bson:"name" json:"name"`
+
+	// Channel holds the release of the operating system, such as
+	// "22.04/stable". The track holds the version of the release.
+	Channel channel.Channel `bson:"channel" json:"channel"`
+
+	// Architectures optionally holds the architectures that the base
+	// is restricted to. All architectures are allowed when it is
+	// empty.
+	Architectures []Architecture `bson:"architectures,omitempty" json:"architectures,omitempty"`
+}
+
+// ParseBase parses a base of the form "<os>@<channel>", such as
+// "ubuntu@22.04" or "ubuntu@22.04/stable", restricted to the given
+// architectures. The risk of the channel defaults to stable.
+func ParseBase(s string, archs ...Architecture) (Base, error) {
+	i := strings.Index(s, "@")
+	if i < 0 {
+		return Base{}, errors.NotValidf("base %q without @", s)
+	}
+	name, track := s[:i], s[i+1:]
+	if track == "" {
+		return Base{}, errors.NotValidf("base %q without channel", s)
+	}
+	ch, err := channel.Parse(track)
+	if err != nil {
+		return Base{}, errors.Annotatef(err, "invalid base %q", s)
+	}
+	base := Base{
+		Name:          name,
+		Channel:       ch,
+		Architectures: archs,
+	}
+	if err := base.Validate(); err != nil {
+		return Base{}, errors.Trace(err)
+	}
+	return base, nil
+}
+
+// MustParseBase is like ParseBase but panics on error.
+func MustParseBase(s string, archs ...Architecture) Base {
+	base, err := ParseBase(s, archs...)
+	if err != nil {
+		panic(err)
+	}
+	return base
+}
+
+// knownArchitectures holds the architectures a base may be restricted
+// to.
+var knownArchitectures = map[Architecture]bool{
+	AMD64:   true,
+	ARM64:   true,
+	PPC64EL: true,
+	S390X:   true,
+}
+
+// Validate returns an error if the base does not name a supported
+// operating system, lacks a version, or names an unknown architecture.
+func (b Base) Validate() error {
+	if !baseOSes[b.Name] {
+		return errors.NotValidf("base os %q", b.Name)
+	}
+	if b.Channel.Track == "" {
+		return errors.NotValidf("base %q without version", b.Name)
+	}
+	for _, arch := range b.Architectures {
+		if !knownArchitectures[arch] {
+			return errors.NotValidf("base architecture %q", arch)
+		}
+	}
+	return nil
+}
+
+// baseOSes holds the operating systems that bases may name. They are
+// those supported by systems.
+var baseOSes = map[string]bool{
+	systems.Ubuntu:       true,
+	systems.CentOS:       true,
+	systems.Windows:      true,
+	systems.OSX:          true,
+	systems.OpenSUSE:     true,
+	systems.GenericLinux: true,
+}
+
+// String returns the base in the form accepted by ParseBase. The risk
+// is omitted when it is stable. Architectures are not included.
+func (b Base) String() string {
+	return b.Name + "@" + strings.TrimSuffix(b.Channel.String(), "/"+string(channel.Stable))
+}
+
+// System returns the system describing the same release as the base.
+func (b Base) System() systems.System {
+	return systems.System{
+		OS:      b.Name,
+		Channel: b.Channel,
+	}
+}
+
+// BaseFromSystem returns the base describing the same release as the
+// system s, which must not refer to a resource.
+func BaseFromSystem(s systems.System) (Base, error) {
+	if s.Resource != "" {
+		return Base{}, errors.NotValidf("system with resource %q as a base", s.Resource)
+	}
+	base := Base{
+		Name:    s.OS,
+		Channel: s.Channel,
+	}
+	if err := base.Validate(); err != nil {
+		return Base{}, errors.Trace(err)
+	}
+	return base, nil
+}
+
+// SeriesToBase returns the base of the named series, as given by its
+// known lifecycle information. See LookupSeries.
+func SeriesToBase(series string) (Base, error) {
+	info, ok := LookupSeries(series)
+	if !ok || info.OS == "" || info.Version == "" {
+		return Base{}, errors.NotFoundf("base for series %q", series)
+	}
+	ch, err := channel.Parse(info.Version)
+	if err != nil {
+		return Base{}, errors.Annotatef(err, "invalid version %q of series %q", info.Version, series)
+	}
+	return Base{
+		Name:    info.OS,
+		Channel: ch,
+	}, nil
+}
+
+// BaseToSeries returns the name of the known series that the base
+// describes. The risk and architectures of the base are ignored.
+func BaseToSeries(b Base) (string, error) {
+	for _, info := range KnownSeries() {
+		if info.OS == b.Name && info.Version == b.Channel.Track {
+			return info.Name, nil
+		}
+	}
+	return "", errors.NotFoundf("series for base %q", b)
+}
+
+// baseSeries returns the name of the known series that the base
+// describes, as BaseToSeries does, or, if there is none, the string
+// form of the equivalent system.
+func baseSeries(b Base) string {
+	if series, err := BaseToSeries(b); err == nil {
+		return series
+	}
+	return b.System().String()
+}
+
+// ComputedBases returns the bases the charm supports, in the order they
+// are declared: its systems and bases for v2 charms, or its series for
+// v1 charms. Bases declared without architectures, and those derived
+// from systems and series, are restricted to the charm's architectures.
+// Systems referring to resources and series with no known base are
+// skipped.
+func (m Meta) ComputedBases() []Base {
+	var bases []Base
+	seen := make(map[string]bool)
+	add := func(base Base) {
+		key := base.Name + "@" + base.Channel.String()
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		if len(base.Architectures) == 0 && len(m.Architectures) > 0 {
+			base.Architectures = append([]Architecture(nil), m.Architectures...)
+		}
+		bases = append(bases, base)
+	}
+	if m.Format() == FormatV2 {
+		for _, system := range m.Systems {
+			if base, err := BaseFromSystem(system); err == nil {
+				add(base)
+			}
+		}
+		for _, base := range m.Bases {
+			add(base)
+		}
+		return bases
+	}
+	for _, series := range m.Series {
+		if base, err := SeriesToBase(series); err == nil {
+			add(base)
+		}
+	}
+	return bases
+}
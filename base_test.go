@@ -0,0 +1,127 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type BaseSuite struct{}
+
+var _ = gc.Suite(&BaseSuite{})
+
+var parseBaseTests = []struct {
+	base   string
+	archs  []charm.Architecture
+	expect string
+	err    string
+}{{
+	base:   "ubuntu@22.04",
+	expect: "ubuntu@22.04",
+}, {
+	base:   "ubuntu@22.04/stable",
+	archs:  []charm.Architecture{charm.AMD64, charm.ARM64},
+	expect: "ubuntu@22.04",
+}, {
+	base:   "ubuntu@24.04/edge",
+	expect: "ubuntu@24.04/edge",
+}, {
+	base:   "centos@7",
+	expect: "centos@7",
+}, {
+	base: "ubuntu",
+	err:  `base "ubuntu" without @ not valid`,
+}, {
+	base: "ubuntu@",
+	err:  `base "ubuntu@" without channel not valid`,
+}, {
+	base: "ubuntu@edge",
+	err:  `base "ubuntu" without version not valid`,
+}, {
+	base: "beos@5",
+	err:  `base os "beos" not valid`,
+}, {
+	base:  "ubuntu@22.04",
+	archs: []charm.Architecture{"m68k"},
+	err:   `base architecture "m68k" not valid`,
+}}
+
+func (s *BaseSuite) TestParseBase(c *gc.C) {
+	for i, test := range parseBaseTests {
+		c.Logf("test %d: %s %v", i, test.base, test.archs)
+		base, err := charm.ParseBase(test.base, test.archs...)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(base.String(), gc.Equals, test.expect)
+		c.Check(base.Architectures, jc.DeepEquals, test.archs)
+		reparsed, err := charm.ParseBase(base.String(), base.Architectures...)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(reparsed, jc.DeepEquals, base)
+	}
+}
+
+func (s *BaseSuite) TestSeriesToBase(c *gc.C) {
+	for _, series := range []string{"xenial", "focal", "jammy", "noble", "centos7"} {
+		base, err := charm.SeriesToBase(series)
+		c.Assert(err, jc.ErrorIsNil)
+		info, _ := charm.LookupSeries(series)
+		c.Check(base.String(), gc.Equals, info.OS+"@"+info.Version)
+		back, err := charm.BaseToSeries(base)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(back, gc.Equals, series)
+	}
+
+	_, err := charm.SeriesToBase("kubernetes")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	_, err = charm.BaseToSeries(charm.MustParseBase("ubuntu@99.04"))
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(err, gc.ErrorMatches, `series for base "ubuntu@99.04" not found`)
+}
+
+func (s *BaseSuite) TestBaseSystem(c *gc.C) {
+	base := charm.MustParseBase("ubuntu@20.04")
+	system := base.System()
+	c.Check(system.String(), gc.Equals, "focal")
+	back, err := charm.BaseFromSystem(system)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(back, jc.DeepEquals, base)
+}
+
+func (s *BaseSuite) TestComputedBases(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(dummyMetadata + "\nseries: [focal, jammy, kubernetes]\n"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(baseStrings(meta.ComputedBases()), jc.DeepEquals, []string{"ubuntu@20.04", "ubuntu@22.04"})
+
+	meta, err = charm.ReadMeta(strings.NewReader(dummyMetadata + `
+architectures: [amd64]
+systems:
+  - os: ubuntu
+    channel: 22.04/stable
+  - os: ubuntu
+    channel: 22.04/stable
+  - os: ubuntu
+    channel: 24.04/edge
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	bases := meta.ComputedBases()
+	c.Check(baseStrings(bases), jc.DeepEquals, []string{"ubuntu@22.04", "ubuntu@24.04/edge"})
+	c.Check(bases[0].Architectures, jc.DeepEquals, []charm.Architecture{charm.AMD64})
+}
+
+func baseStrings(bases []charm.Base) []string {
+	var result []string
+	for _, base := range bases {
+		result = append(result, base.String())
+	}
+	return result
+}
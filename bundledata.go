@@ -419,6 +419,10 @@ type bundleDataVerifier struct {
 
 	charms map[string]Charm
 
+	// relationCounts holds the number of relations that each
+	// application endpoint takes part in, when charms are known.
+	relationCounts map[endpoint]int
+
 	errors            []error
 	verifyConstraints func(c string) error
 	verifyStorage     func(s string) error
@@ -537,6 +541,7 @@ func (bd *BundleData) verifyBundle(
 		bd:                bd,
 		machineRefCounts:  make(map[string]int),
 		charms:            charms,
+		relationCounts:    make(map[endpoint]int),
 	}
 	if bd.Type != "" && bd.Type != kubernetes {
 		verifier.addErrorf("bundle has an invalid type %q", bd.Type)
@@ -560,6 +565,7 @@ func (bd *BundleData) verifyBundle(
 	verifier.verifyMachines()
 	verifier.verifyApplications()
 	verifier.verifyRelations()
+	verifier.verifyRelationLimits()
 	verifier.verifyOptions()
 	verifier.verifyEndpointBindings()
 
@@ -817,7 +823,7 @@ func (verifier *bundleDataVerifier) getCharmMetaForApplication(appName string) (
 }
 
 func (verifier *bundleDataVerifier) verifyRelations() {
-	seen := make(map[[2]endpoint]bool)
+	seen := make(map[string]bool)
 	for _, relPair := range verifier.bd.Relations {
 		if len(relPair) != 2 {
 			verifier.addErrorf("relation %q has %d endpoint(s), not 2", relPair, len(relPair))
@@ -869,20 +875,53 @@ func (verifier *bundleDataVerifier) verifyRelations() {
 			}
 		}
 
-		// Re-order pairs so that we diagnose duplicate relations
-		// whichever way they're specified.
+		// Re-order pairs so that we diagnose duplicate relations,
+		// and report errors in the same way, whichever way they're
+		// specified.
 		if epPair[1].less(epPair[0]) {
 			epPair[1], epPair[0] = epPair[0], epPair[1]
 		}
-		if _, ok := seen[epPair]; ok {
+		key := CanonicalRelationKey(epPair[0].String(), epPair[1].String())
+		if seen[key] {
 			verifier.addErrorf("relation %q is defined more than once", relPair)
 		}
 		if verifier.charms != nil && epPair[0].relation != "" && epPair[1].relation != "" {
 			// We have charms to verify against, and the
 			// endpoint has been fully specified or inferred.
 			verifier.verifyRelation(epPair[0], epPair[1])
+			if !seen[key] {
+				verifier.relationCounts[epPair[0]]++
+				verifier.relationCounts[epPair[1]]++
+			}
+		}
+		seen[key] = true
+	}
+}
+
+// verifyRelationLimits verifies that no endpoint takes part in more
+// relations than the limit declared by its charm allows.
+func (verifier *bundleDataVerifier) verifyRelationLimits() {
+	eps := make([]endpoint, 0, len(verifier.relationCounts))
+	for ep := range verifier.relationCounts {
+		eps = append(eps, ep)
+	}
+	sort.Slice(eps, func(i, j int) bool {
+		return eps[i].less(eps[j])
+	})
+	for _, ep := range eps {
+		meta, err := verifier.getCharmMetaForApplication(ep.application)
+		if err != nil {
+			// The endpoint belongs to a SAAS offer, or an error
+			// is produced by verifyApplications.
+			continue
+		}
+		rel, ok := meta.CombinedRelations()[ep.relation]
+		if !ok || rel.Limit <= 0 {
+			continue
+		}
+		if count := verifier.relationCounts[ep]; count > rel.Limit {
+			verifier.addErrorf("relation endpoint %q takes part in %d relations, exceeding its limit of %d", ep, count, rel.Limit)
 		}
-		seen[epPair] = true
 	}
 }
 
@@ -986,7 +1025,7 @@ func (verifier *bundleDataVerifier) verifyRelation(ep0, ep1 endpoint) {
 		// Errors were added above.
 		return
 	}
-	if relProv.Interface != relReq.Interface {
+	if !relProv.sharesInterface(relReq) {
 		verifier.addErrorf("mismatched interface between %q and %q (%q vs %q)", epProv, epReq, relProv.Interface, relReq.Interface)
 	}
 }
@@ -1039,6 +1078,33 @@ func (ep1 endpoint) less(ep2 endpoint) bool {
 	return ep1.application < ep2.application
 }
 
+// CanonicalRelationKey returns a key identifying the relation between
+// the endpoints ep1 and ep2, each of the form "application:relation" or
+// "application" as used in BundleData.Relations. The key is the same
+// whichever order the endpoints are given in, so it may be used to find
+// duplicate relations or to compare the relations of two bundles.
+func CanonicalRelationKey(ep1, ep2 string) string {
+	e1, e2 := splitEndpoint(ep1), splitEndpoint(ep2)
+	if e2.less(e1) {
+		e1, e2 = e2, e1
+	}
+	return e1.String() + " " + e2.String()
+}
+
+// splitEndpoint returns the application and relation of the endpoint
+// ep, without validating them.
+func splitEndpoint(ep string) endpoint {
+	if i := strings.Index(ep, ":"); i >= 0 {
+		return endpoint{
+			application: ep[:i],
+			relation:    ep[i+1:],
+		}
+	}
+	return endpoint{
+		application: ep,
+	}
+}
+
 func parseEndpoint(ep string) (endpoint, error) {
 	m := validApplicationRelation.FindStringSubmatch(ep)
 	if m != nil {
@@ -1070,9 +1136,7 @@ func (ep endpointInfo) String() string {
 // and other.
 func (ep endpointInfo) canRelateTo(other endpointInfo) bool {
 	return ep.applicationName != other.applicationName &&
-		ep.Interface == other.Interface &&
-		ep.Role != RolePeer &&
-		counterpartRole(ep.Role) == other.Role
+		ep.Relation.CanRelateTo(other.Relation)
 }
 
 // endpoint returns the endpoint specifier for ep.
@@ -1083,20 +1147,6 @@ func (ep endpointInfo) endpoint() endpoint {
 	}
 }
 
-// counterpartRole returns the RelationRole that the given RelationRole
-// can relate to.
-func counterpartRole(r RelationRole) RelationRole {
-	switch r {
-	case RoleProvider:
-		return RoleRequirer
-	case RoleRequirer:
-		return RoleProvider
-	case RolePeer:
-		return RolePeer
-	}
-	panic(fmt.Errorf("unknown relation role %q", r))
-}
-
 type UnitPlacement struct {
 	// ContainerType holds the container type of the new
 	// new unit, or empty if unspecified.
@@ -1222,15 +1272,10 @@ outer:
 	return filtered
 }
 
-// relationKey returns a string describing the relation defined by
-// endpoints, for use in various contexts (including error messages).
+// relationKey returns a string describing the relation defined by the
+// pair of endpoints, for use in various contexts (including error messages).
 func relationKey(endpoints []endpointInfo) string {
-	var names []string
-	for _, ep := range endpoints {
-		names = append(names, ep.String())
-	}
-	sort.Strings(names)
-	return strings.Join(names, " ")
+	return CanonicalRelationKey(endpoints[0].String(), endpoints[1].String())
 }
 
 // possibleEndpoints returns all the endpoints that the given endpoint spec
@@ -1257,12 +1302,8 @@ func possibleEndpoints(epSpec endpoint, get func(svc string) (*Meta, error)) ([]
 	for _, r := range meta.Requires {
 		add(r)
 	}
-	// Every application implicitly provides a juju-info relation.
-	add(Relation{
-		Name:      "juju-info",
-		Role:      RoleProvider,
-		Interface: "juju-info",
-		Scope:     ScopeGlobal,
-	})
+	for _, r := range meta.ImplicitRelations() {
+		add(r)
+	}
 	return eps, nil
 }
@@ -778,6 +778,57 @@ func (s *bundleDataSuite) TestVerifyBundleWithRelationNameBindingSuccess(c *gc.C
 	c.Assert(err, gc.IsNil)
 }
 
+func (*bundleDataSuite) TestVerifyWithCharmsInterfaceAliases(c *gc.C) {
+	data := `
+applications:
+    application1:
+        charm: "test1"
+    application2:
+        charm: "test2"
+relations:
+    - ["application1:reqa", "application2:provb"]
+`
+	provider := testCharm("test2", "provb:b")
+	provb := provider.Meta().Provides["provb"]
+	provb.InterfaceAliases = []string{"a"}
+	provider.Meta().Provides["provb"] = provb
+	charms := map[string]charm.Charm{
+		"test1": testCharm("test1", "| reqa:a"),
+		"test2": provider,
+	}
+	assertVerifyErrors(c, data, charms, nil)
+}
+
+func (*bundleDataSuite) TestVerifyWithCharmsRelationLimit(c *gc.C) {
+	data := `
+applications:
+    application1:
+        charm: "test1"
+    application2:
+        charm: "test2"
+    application3:
+        charm: "test2"
+relations:
+    - ["application1:reqa", "application2:prova"]
+    - ["application1:reqa", "application3:prova"]
+`
+	requirer := testCharm("test1", "| reqa:a")
+	reqa := requirer.Meta().Requires["reqa"]
+	reqa.Limit = 1
+	requirer.Meta().Requires["reqa"] = reqa
+	charms := map[string]charm.Charm{
+		"test1": requirer,
+		"test2": testCharm("test2", "prova:a"),
+	}
+	assertVerifyErrors(c, data, charms, []string{
+		`relation endpoint "application1:reqa" takes part in 2 relations, exceeding its limit of 1`,
+	})
+
+	reqa.Limit = 2
+	requirer.Meta().Requires["reqa"] = reqa
+	assertVerifyErrors(c, data, charms, nil)
+}
+
 func (s *bundleDataSuite) TestParseKubernetesBundleType(c *gc.C) {
 	data := `
 bundle: kubernetes
@@ -1545,3 +1596,21 @@ relations:
 	})
 
 }
+
+func (*bundleDataSuite) TestCanonicalRelationKey(c *gc.C) {
+	for i, test := range []struct {
+		ep1, ep2 string
+		expect   string
+	}{
+		{"wordpress:db", "mysql:server", "mysql:server wordpress:db"},
+		{"mysql:server", "wordpress:db", "mysql:server wordpress:db"},
+		{"wordpress", "mysql", "mysql wordpress"},
+		{"mysql:", "wordpress", "mysql wordpress"},
+		{"a:y", "a-b:x", "a:y a-b:x"},
+		{"app:b", "app:a", "app:a app:b"},
+	} {
+		c.Logf("test %d: %s %s", i, test.ep1, test.ep2)
+		c.Check(charm.CanonicalRelationKey(test.ep1, test.ep2), gc.Equals, test.expect)
+		c.Check(charm.CanonicalRelationKey(test.ep2, test.ep1), gc.Equals, test.expect)
+	}
+}
@@ -182,17 +182,20 @@ func isNotExistsError(err error) bool {
 func StreamBundleDataSource(r io.Reader, basePath string) (BundleDataSource, error) {
 	parts, err := parseBundleParts(r)
 	if err != nil {
-		return nil, errors.NotValidf("cannot unmarshal bundle contents: %v", err)
+		return nil, errors.Annotate(err, "cannot unmarshal bundle contents")
 	}
 
 	return &resolvedBundleDataSource{parts: parts, basePath: basePath}, nil
 }
 
-func parseBundleParts(r io.Reader) ([]*BundleDataPart, error) {
-	b, err := ioutil.ReadAll(r)
+func parseBundleParts(r io.Reader) (_ []*BundleDataPart, err error) {
+	b, err := readYAMLInput(r)
 	if err != nil {
 		return nil, err
 	}
+	defer func() {
+		reportParseFailure("bundle.yaml", b, err)
+	}()
 
 	var (
 		// Ideally, we would be using a single reader and we would
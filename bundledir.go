@@ -56,7 +56,16 @@ func (dir *BundleDir) ContainsOverlays() bool {
 }
 
 func (dir *BundleDir) ArchiveTo(w io.Writer) error {
-	return writeArchive(w, dir.Path, -1, "", nil, nil)
+	return dir.ArchiveToWithOptions(w, ArchiveOptions{})
+}
+
+// ArchiveToWithOptions is like ArchiveTo, but compresses the files of
+// the bundle as configured by opts.
+func (dir *BundleDir) ArchiveToWithOptions(w io.Writer, opts ArchiveOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	return writeArchive(w, dir.Path, -1, "", nil, nil, opts)
 }
 
 // join builds a path rooted at the bundle's expanded directory
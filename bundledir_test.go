@@ -4,6 +4,8 @@
 package charm_test
 
 import (
+	"archive/zip"
+	"bytes"
 	"os"
 	"path/filepath"
 
@@ -56,3 +58,19 @@ func (s *BundleDirSuite) assertArchiveTo(c *gc.C, baseDir, bundleDir string) {
 	c.Assert(archive.ReadMe(), gc.Equals, dir.ReadMe())
 	c.Assert(archive.Data(), gc.DeepEquals, dir.Data())
 }
+
+func (s *BundleDirSuite) TestArchiveToWithOptions(c *gc.C) {
+	dir, err := charm.ReadBundleDir(bundleDirPath(c, "wordpress-simple"))
+	c.Assert(err, gc.IsNil)
+	var buf bytes.Buffer
+	err = dir.ArchiveToWithOptions(&buf, charm.ArchiveOptions{Store: true})
+	c.Assert(err, gc.IsNil)
+	zipr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	c.Assert(err, gc.IsNil)
+	for _, f := range zipr.File {
+		c.Check(f.Method, gc.Equals, zip.Store, gc.Commentf("%s", f.Name))
+	}
+	archive, err := charm.ReadBundleArchiveBytes(buf.Bytes())
+	c.Assert(err, gc.IsNil)
+	c.Assert(archive.Data(), gc.DeepEquals, dir.Data())
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Changelogger is implemented by charms that can provide the change
+// history held in their changelog.yaml file.
+type Changelogger interface {
+	// Changelog returns the Changelog found in changelog.yaml of the charm.
+	Changelog() *Changelog
+}
+
+// ChangelogEntry describes the changes made in a single charm revision.
+type ChangelogEntry struct {
+	// Revision is the charm revision the entry applies to.
+	Revision int `yaml:"revision"`
+
+	// Date is the release date of the revision, if known.
+	Date time.Time `yaml:"date,omitempty"`
+
+	// Notes holds the release notes for the revision.
+	Notes string `yaml:"notes,omitempty"`
+}
+
+// Changelog holds the release history of a charm, as found in its
+// optional changelog.yaml file. The file holds a YAML list of entries,
+// each with a revision and optional date and notes fields, ordered from
+// the oldest revision to the newest.
+type Changelog struct {
+	Entries []ChangelogEntry
+}
+
+// NewChangelog returns a new Changelog without any entries.
+func NewChangelog() *Changelog {
+	return &Changelog{}
+}
+
+// ReadChangelog reads a Changelog from a charm's changelog.yaml and
+// validates it.
+func ReadChangelog(r io.Reader) (_ *Changelog, err error) {
+	data, err := readYAMLInput(r)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		reportParseFailure(ChangelogFile, data, err)
+	}()
+	var entries []ChangelogEntry
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, errors.Annotate(err, "failed to unmarshal changelog.yaml")
+	}
+	changelog := &Changelog{Entries: entries}
+	if err := changelog.Validate(); err != nil {
+		return nil, errors.Annotate(err, "invalid changelog.yaml")
+	}
+	return changelog, nil
+}
+
+// readCharmChangelog reads the changelog.yaml file of a charm. As the
+// file is optional, and may predate the list format, a changelog that
+// cannot be parsed is logged and replaced by an empty one rather than
+// making the whole charm unreadable.
+func readCharmChangelog(r io.Reader) *Changelog {
+	changelog, err := ReadChangelog(r)
+	if err != nil {
+		logger.Warningf("ignoring %q file: %v", ChangelogFile, err)
+		return NewChangelog()
+	}
+	return changelog
+}
+
+// Validate checks that the changelog revisions are non-negative and strictly
+// increasing, and that dates, where given, do not go backwards.
+func (c *Changelog) Validate() error {
+	var last ChangelogEntry
+	var lastDate time.Time
+	for i, entry := range c.Entries {
+		if entry.Revision < 0 {
+			return fmt.Errorf("entry %d has negative revision %d", i, entry.Revision)
+		}
+		if i > 0 && entry.Revision <= last.Revision {
+			return fmt.Errorf("revision %d follows revision %d; revisions must increase", entry.Revision, last.Revision)
+		}
+		if !entry.Date.IsZero() {
+			if entry.Date.Before(lastDate) {
+				return fmt.Errorf("revision %d is dated before an earlier revision", entry.Revision)
+			}
+			lastDate = entry.Date
+		}
+		last = entry
+	}
+	return nil
+}
+
+// Entry returns the changelog entry for the given revision, and whether
+// one was found.
+func (c *Changelog) Entry(revision int) (ChangelogEntry, bool) {
+	for _, entry := range c.Entries {
+		if entry.Revision == revision {
+			return entry, true
+		}
+	}
+	return ChangelogEntry{}, false
+}
+
+// Latest returns the entry for the newest revision in the changelog, and
+// false if the changelog is empty.
+func (c *Changelog) Latest() (ChangelogEntry, bool) {
+	if len(c.Entries) == 0 {
+		return ChangelogEntry{}, false
+	}
+	return c.Entries[len(c.Entries)-1], true
+}
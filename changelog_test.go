@@ -0,0 +1,140 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type ChangelogSuite struct{}
+
+var _ = gc.Suite(&ChangelogSuite{})
+
+const changelogYAML = `
+- revision: 1
+  date: 2020-06-01T00:00:00Z
+  notes: Initial release.
+- revision: 3
+  date: 2020-07-15T00:00:00Z
+  notes: Add support for focal.
+`
+
+func (s *ChangelogSuite) TestReadChangelog(c *gc.C) {
+	changelog, err := charm.ReadChangelog(strings.NewReader(changelogYAML))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changelog.Entries, jc.DeepEquals, []charm.ChangelogEntry{{
+		Revision: 1,
+		Date:     time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC),
+		Notes:    "Initial release.",
+	}, {
+		Revision: 3,
+		Date:     time.Date(2020, 7, 15, 0, 0, 0, 0, time.UTC),
+		Notes:    "Add support for focal.",
+	}})
+
+	latest, ok := changelog.Latest()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(latest.Revision, gc.Equals, 3)
+
+	entry, ok := changelog.Entry(1)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(entry.Notes, gc.Equals, "Initial release.")
+
+	_, ok = changelog.Entry(2)
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *ChangelogSuite) TestReadChangelogEmpty(c *gc.C) {
+	changelog, err := charm.ReadChangelog(strings.NewReader(""))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changelog.Entries, gc.HasLen, 0)
+	_, ok := changelog.Latest()
+	c.Assert(ok, jc.IsFalse)
+}
+
+var changelogErrorTests = []struct {
+	about string
+	yaml  string
+	err   string
+}{{
+	about: "revisions not increasing",
+	yaml:  "- revision: 2\n- revision: 2\n",
+	err:   `invalid changelog.yaml: revision 2 follows revision 2; revisions must increase`,
+}, {
+	about: "revisions out of order",
+	yaml:  "- revision: 3\n- revision: 1\n",
+	err:   `invalid changelog.yaml: revision 1 follows revision 3; revisions must increase`,
+}, {
+	about: "negative revision",
+	yaml:  "- revision: -1\n",
+	err:   `invalid changelog.yaml: entry 0 has negative revision -1`,
+}, {
+	about: "dates going backwards",
+	yaml:  "- revision: 1\n  date: 2020-07-01T00:00:00Z\n- revision: 2\n  date: 2020-06-01T00:00:00Z\n",
+	err:   `invalid changelog.yaml: revision 2 is dated before an earlier revision`,
+}, {
+	about: "not a list",
+	yaml:  "revision: 1\n",
+	err:   `(?s)failed to unmarshal changelog.yaml: .*`,
+}}
+
+func (s *ChangelogSuite) TestReadChangelogErrors(c *gc.C) {
+	for i, test := range changelogErrorTests {
+		c.Logf("test %d: %s", i, test.about)
+		_, err := charm.ReadChangelog(strings.NewReader(test.yaml))
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ChangelogSuite) TestCharmDirChangelog(c *gc.C) {
+	path := cloneDir(c, charmDirPath(c, "dummy"))
+	dir, err := charm.ReadCharmDir(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(dir.Changelog().Entries, gc.HasLen, 0)
+
+	err = ioutil.WriteFile(filepath.Join(path, "changelog.yaml"), []byte(changelogYAML), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	dir, err = charm.ReadCharmDir(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(dir.Changelog().Entries, gc.HasLen, 2)
+
+	var buf bytes.Buffer
+	err = dir.ArchiveTo(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+	archive, err := charm.ReadCharmArchiveBytes(buf.Bytes())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archive.Changelog(), jc.DeepEquals, dir.Changelog())
+}
+
+func (s *ChangelogSuite) TestCharmInvalidChangelogIgnored(c *gc.C) {
+	for i, data := range []string{
+		"- revision: 2\n- revision: 1\n",
+		"revision: 3\nnotes: an older format\n",
+		"`",
+	} {
+		c.Logf("test %d", i)
+		path := cloneDir(c, charmDirPath(c, "dummy"))
+		err := ioutil.WriteFile(filepath.Join(path, "changelog.yaml"), []byte(data), 0644)
+		c.Assert(err, jc.ErrorIsNil)
+		dir, err := charm.ReadCharmDir(path)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(dir.Changelog().Entries, gc.HasLen, 0)
+
+		var buf bytes.Buffer
+		err = dir.ArchiveTo(&buf)
+		c.Assert(err, jc.ErrorIsNil)
+		archive, err := charm.ReadCharmArchiveBytes(buf.Bytes())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(archive.Changelog().Entries, gc.HasLen, 0)
+	}
+}
@@ -29,6 +29,7 @@ type CharmArchive struct {
 	metrics    *Metrics
 	actions    *Actions
 	lxdProfile *LXDProfile
+	changelog  *Changelog
 	revision   int
 	version    string
 }
@@ -72,7 +73,7 @@ func readCharmArchive(zopen zipOpener) (archive *CharmArchive, err error) {
 		return nil, err
 	}
 	defer zipr.Close()
-	reader, err := zipOpenFile(zipr, "metadata.yaml")
+	reader, err := zipOpenFile(zipr, MetadataFile)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +83,7 @@ func readCharmArchive(zopen zipOpener) (archive *CharmArchive, err error) {
 		return nil, err
 	}
 
-	reader, err = zipOpenFile(zipr, "config.yaml")
+	reader, err = zipOpenFile(zipr, ConfigFile)
 	if _, ok := err.(*noCharmArchiveFile); ok {
 		b.config = NewConfig()
 	} else if err != nil {
@@ -95,7 +96,7 @@ func readCharmArchive(zopen zipOpener) (archive *CharmArchive, err error) {
 		}
 	}
 
-	reader, err = zipOpenFile(zipr, "metrics.yaml")
+	reader, err = zipOpenFile(zipr, MetricsFile)
 	if err == nil {
 		b.metrics, err = ReadMetrics(reader)
 		reader.Close()
@@ -118,7 +119,7 @@ func readCharmArchive(zopen zipOpener) (archive *CharmArchive, err error) {
 		return nil, err
 	}
 
-	reader, err = zipOpenFile(zipr, "revision")
+	reader, err = zipOpenFile(zipr, RevisionFile)
 	if err != nil {
 		if _, ok := err.(*noCharmArchiveFile); !ok {
 			return nil, err
@@ -130,7 +131,7 @@ func readCharmArchive(zopen zipOpener) (archive *CharmArchive, err error) {
 		}
 	}
 
-	reader, err = zipOpenFile(zipr, "lxd-profile.yaml")
+	reader, err = zipOpenFile(zipr, LXDProfileFile)
 	if _, ok := err.(*noCharmArchiveFile); ok {
 		b.lxdProfile = NewLXDProfile()
 	} else if err != nil {
@@ -143,7 +144,17 @@ func readCharmArchive(zopen zipOpener) (archive *CharmArchive, err error) {
 		}
 	}
 
-	reader, err = zipOpenFile(zipr, "version")
+	reader, err = zipOpenFile(zipr, ChangelogFile)
+	if _, ok := err.(*noCharmArchiveFile); ok {
+		b.changelog = NewChangelog()
+	} else if err != nil {
+		return nil, err
+	} else {
+		b.changelog = readCharmChangelog(reader)
+		reader.Close()
+	}
+
+	reader, err = zipOpenFile(zipr, VersionFile)
 	if err != nil {
 		if _, ok := err.(*noCharmArchiveFile); !ok {
 			return nil, err
@@ -159,10 +170,51 @@ func readCharmArchive(zopen zipOpener) (archive *CharmArchive, err error) {
 	return b, nil
 }
 
+// ScanArchiveMetadata reads and parses only the metadata.yaml and
+// config.yaml files from the charm archive held in r, which must hold
+// size bytes. The zip central directory is used to locate the two files
+// so that only the directory and their compressed contents are read
+// from r; this makes it suitable for indexing charms held in object
+// stores that support ranged reads, without fetching whole archives.
+//
+// If the archive holds no config.yaml, an empty Config is returned.
+func ScanArchiveMetadata(r io.ReaderAt, size int64) (*Meta, *Config, error) {
+	zipr, err := newZipOpenerFromReader(r, size).openZip()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer zipr.Close()
+
+	reader, err := zipOpenFile(zipr, MetadataFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	meta, err := ReadMeta(reader)
+	reader.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var config *Config
+	reader, err = zipOpenFile(zipr, ConfigFile)
+	if _, ok := err.(*noCharmArchiveFile); ok {
+		config = NewConfig()
+	} else if err != nil {
+		return nil, nil, err
+	} else {
+		config, err = ReadConfig(reader)
+		reader.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return meta, config, nil
+}
+
 type fileOpener func(string) (io.ReadCloser, error)
 
 func getActions(open fileOpener, isNotFound func(error) bool) (actions *Actions, err error) {
-	reader, err := open("actions.yaml")
+	reader, err := open(ActionsFile)
 	if err == nil {
 		defer reader.Close()
 		return ReadActionsYaml(reader)
@@ -236,6 +288,12 @@ func (a *CharmArchive) LXDProfile() *LXDProfile {
 	return a.lxdProfile
 }
 
+// Changelog returns the Changelog representing the changelog.yaml file
+// for the charm archive.
+func (a *CharmArchive) Changelog() *Changelog {
+	return a.changelog
+}
+
 type zipReadCloser struct {
 	io.Closer
 	*zip.Reader
@@ -312,7 +370,7 @@ func (a *CharmArchive) Manifest() (set.Strings, error) {
 	manifest := set.NewStrings(paths...)
 	// We always write out a revision file, even if there isn't one in the
 	// archive; and we always strip ".", because that's sometimes not present.
-	manifest.Add("revision")
+	manifest.Add(RevisionFile)
 	manifest.Remove(".")
 	return manifest, nil
 }
@@ -329,14 +387,14 @@ func (a *CharmArchive) ExpandTo(dir string) error {
 	if err := ziputil.ExtractAll(zipr.Reader, dir); err != nil {
 		return err
 	}
-	hooksDir := filepath.Join(dir, "hooks")
+	hooksDir := filepath.Join(dir, HooksDir)
 	fixHook := fixHookFunc(hooksDir, a.meta.Hooks())
 	if err := filepath.Walk(hooksDir, fixHook); err != nil {
 		if !os.IsNotExist(err) {
 			return err
 		}
 	}
-	revFile, err := os.Create(filepath.Join(dir, "revision"))
+	revFile, err := os.Create(filepath.Join(dir, RevisionFile))
 	if err != nil {
 		return err
 	}
@@ -7,6 +7,7 @@ import (
 	"archive/zip"
 	"bytes"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -140,6 +141,75 @@ func (s *CharmArchiveSuite) TestReadCharmArchiveFromReader(c *gc.C) {
 	checkDummy(c, archive, "")
 }
 
+func (s *CharmArchiveSuite) TestScanArchiveMetadata(c *gc.C) {
+	buf := new(bytes.Buffer)
+	zipw := zip.NewWriter(buf)
+	w, err := zipw.Create("metadata.yaml")
+	c.Assert(err, gc.IsNil)
+	_, err = w.Write([]byte("name: big\nsummary: s\ndescription: d\n"))
+	c.Assert(err, gc.IsNil)
+	w, err = zipw.Create("config.yaml")
+	c.Assert(err, gc.IsNil)
+	_, err = w.Write([]byte("options:\n  title: {type: string, default: My Title}\n"))
+	c.Assert(err, gc.IsNil)
+	w, err = zipw.CreateHeader(&zip.FileHeader{Name: "payload.bin", Method: zip.Store})
+	c.Assert(err, gc.IsNil)
+	_, err = w.Write(make([]byte, 1024*1024))
+	c.Assert(err, gc.IsNil)
+	c.Assert(zipw.Close(), gc.IsNil)
+
+	r := &countingReaderAt{r: bytes.NewReader(buf.Bytes())}
+	meta, config, err := charm.ScanArchiveMetadata(r, int64(buf.Len()))
+	c.Assert(err, gc.IsNil)
+	c.Assert(meta.Name, gc.Equals, "big")
+	c.Assert(config.Options["title"].Default, gc.Equals, "My Title")
+	// The payload must not have been read.
+	c.Assert(r.read < 64*1024, jc.IsTrue, gc.Commentf("read %d of %d bytes", r.read, buf.Len()))
+}
+
+func (s *CharmArchiveSuite) TestScanArchiveMetadataDummy(c *gc.C) {
+	data, err := ioutil.ReadFile(s.archivePath)
+	c.Assert(err, gc.IsNil)
+
+	meta, config, err := charm.ScanArchiveMetadata(bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, gc.IsNil)
+	c.Assert(meta, jc.DeepEquals, readCharmDir(c, "dummy").Meta())
+	c.Assert(config, jc.DeepEquals, readCharmDir(c, "dummy").Config())
+}
+
+func (s *CharmArchiveSuite) TestScanArchiveMetadataWithoutConfig(c *gc.C) {
+	data, err := ioutil.ReadFile(archivePath(c, readCharmDir(c, "varnish")))
+	c.Assert(err, gc.IsNil)
+
+	meta, config, err := charm.ScanArchiveMetadata(bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, gc.IsNil)
+	c.Assert(meta.Name, gc.Equals, "varnish")
+	c.Assert(config.Options, gc.HasLen, 0)
+}
+
+func (s *CharmArchiveSuite) TestScanArchiveMetadataNoMetadata(c *gc.C) {
+	buf := new(bytes.Buffer)
+	zipw := zip.NewWriter(buf)
+	_, err := zipw.Create("README.md")
+	c.Assert(err, gc.IsNil)
+	c.Assert(zipw.Close(), gc.IsNil)
+
+	_, _, err = charm.ScanArchiveMetadata(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	c.Assert(err, gc.ErrorMatches, `archive file "metadata.yaml" not found`)
+}
+
+// countingReaderAt records the number of bytes read through it.
+type countingReaderAt struct {
+	r    io.ReaderAt
+	read int64
+}
+
+func (r *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := r.r.ReadAt(p, off)
+	r.read += int64(n)
+	return n, err
+}
+
 func (s *CharmArchiveSuite) TestManifest(c *gc.C) {
 	archive, err := charm.ReadCharmArchive(s.archivePath)
 	c.Assert(err, gc.IsNil)
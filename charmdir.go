@@ -5,6 +5,7 @@ package charm
 
 import (
 	"archive/zip"
+	"compress/flate"
 	"context"
 	"fmt"
 	"io"
@@ -50,6 +51,7 @@ type CharmDir struct {
 	metrics    *Metrics
 	actions    *Actions
 	lxdProfile *LXDProfile
+	changelog  *Changelog
 	revision   int
 	version    string
 }
@@ -61,45 +63,45 @@ var _ Charm = (*CharmDir)(nil)
 // a charm, even it may be incomplete.
 func IsCharmDir(path string) bool {
 	dir := &CharmDir{Path: path}
-	_, err := os.Stat(dir.join("metadata.yaml"))
+	_, err := os.Stat(dir.join(MetadataFile))
 	return err == nil
 }
 
 // ReadCharmDir returns a CharmDir representing an expanded charm directory.
 func ReadCharmDir(path string) (dir *CharmDir, err error) {
 	dir = &CharmDir{Path: path}
-	file, err := os.Open(dir.join("metadata.yaml"))
+	file, err := os.Open(dir.join(MetadataFile))
 	if err != nil {
-		return nil, errors.Annotatef(err, `issue reading "metadata.yaml" file`)
+		return nil, errors.Annotatef(err, "issue reading %q file", MetadataFile)
 	}
 	dir.meta, err = ReadMeta(file)
 	file.Close()
 	if err != nil {
-		return nil, errors.Annotatef(err, `issue parsing "metadata.yaml" file`)
+		return nil, errors.Annotatef(err, "issue parsing %q file", MetadataFile)
 	}
 
-	file, err = os.Open(dir.join("config.yaml"))
+	file, err = os.Open(dir.join(ConfigFile))
 	if _, ok := err.(*os.PathError); ok {
 		dir.config = NewConfig()
 	} else if err != nil {
-		return nil, errors.Annotatef(err, `issue reading "config.yaml" file`)
+		return nil, errors.Annotatef(err, "issue reading %q file", ConfigFile)
 	} else {
 		dir.config, err = ReadConfig(file)
 		file.Close()
 		if err != nil {
-			return nil, errors.Annotatef(err, `issue parsing "config.yaml" file`)
+			return nil, errors.Annotatef(err, "issue parsing %q file", ConfigFile)
 		}
 	}
 
-	file, err = os.Open(dir.join("metrics.yaml"))
+	file, err = os.Open(dir.join(MetricsFile))
 	if err == nil {
 		dir.metrics, err = ReadMetrics(file)
 		file.Close()
 		if err != nil {
-			return nil, errors.Annotatef(err, `issue parsing "metrics.yaml" file`)
+			return nil, errors.Annotatef(err, "issue parsing %q file", MetricsFile)
 		}
 	} else if !os.IsNotExist(err) {
-		return nil, errors.Annotatef(err, `issue reading "metrics.yaml" file`)
+		return nil, errors.Annotatef(err, "issue reading %q file", MetricsFile)
 	}
 
 	if dir.actions, err = getActions(
@@ -114,7 +116,7 @@ func ReadCharmDir(path string) (dir *CharmDir, err error) {
 		return nil, err
 	}
 
-	if file, err = os.Open(dir.join("revision")); err == nil {
+	if file, err = os.Open(dir.join(RevisionFile)); err == nil {
 		_, err = fmt.Fscan(file, &dir.revision)
 		file.Close()
 		if err != nil {
@@ -122,29 +124,39 @@ func ReadCharmDir(path string) (dir *CharmDir, err error) {
 		}
 	}
 
-	file, err = os.Open(dir.join("lxd-profile.yaml"))
+	file, err = os.Open(dir.join(LXDProfileFile))
 	if _, ok := err.(*os.PathError); ok {
 		dir.lxdProfile = NewLXDProfile()
 	} else if err != nil {
-		return nil, errors.Annotatef(err, `issue reading "lxd-profile.yaml" file`)
+		return nil, errors.Annotatef(err, "issue reading %q file", LXDProfileFile)
 	} else {
 		dir.lxdProfile, err = ReadLXDProfile(file)
 		file.Close()
 		if err != nil {
-			return nil, errors.Annotatef(err, `issue parsing "lxd-profile.yaml" file`)
+			return nil, errors.Annotatef(err, "issue parsing %q file", LXDProfileFile)
 		}
 	}
 
-	file, err = os.Open(dir.join("version"))
+	file, err = os.Open(dir.join(ChangelogFile))
+	if _, ok := err.(*os.PathError); ok {
+		dir.changelog = NewChangelog()
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "issue reading %q file", ChangelogFile)
+	} else {
+		dir.changelog = readCharmChangelog(file)
+		file.Close()
+	}
+
+	file, err = os.Open(dir.join(VersionFile))
 	if err != nil {
 		if _, ok := err.(*os.PathError); !ok {
-			return nil, errors.Annotatef(err, `issue reading "version" file`)
+			return nil, errors.Annotatef(err, "issue reading %q file", VersionFile)
 		}
 	} else {
 		dir.version, err = ReadVersion(file)
 		file.Close()
 		if err != nil {
-			return nil, errors.Annotatef(err, `issue parsing "version" file`)
+			return nil, errors.Annotatef(err, "issue parsing %q file", VersionFile)
 		}
 	}
 
@@ -162,17 +174,17 @@ func (dir *CharmDir) buildIgnoreRules() (ignoreRuleset, error) {
 		return nil, err
 	}
 
-	pathToJujuignore := dir.join(".jujuignore")
+	pathToJujuignore := dir.join(JujuIgnoreFile)
 	if _, err := os.Stat(pathToJujuignore); err == nil {
-		file, err := os.Open(dir.join(".jujuignore"))
+		file, err := os.Open(dir.join(JujuIgnoreFile))
 		if err != nil {
-			return nil, errors.Annotatef(err, `issue reading ".jujuignore" file`)
+			return nil, errors.Annotatef(err, "issue reading %q file", JujuIgnoreFile)
 		}
 		defer func() { _ = file.Close() }()
 
 		jujuignoreRules, err := newIgnoreRuleset(file)
 		if err != nil {
-			return nil, errors.Annotate(err, `issue parsing ".jujuignore" file`)
+			return nil, errors.Annotatef(err, "issue parsing %q file", JujuIgnoreFile)
 		}
 
 		rules = append(rules, jujuignoreRules...)
@@ -229,6 +241,12 @@ func (dir *CharmDir) LXDProfile() *LXDProfile {
 	return dir.lxdProfile
 }
 
+// Changelog returns the Changelog representing the changelog.yaml file
+// for the charm expanded in dir.
+func (dir *CharmDir) Changelog() *Changelog {
+	return dir.changelog
+}
+
 // SetRevision changes the charm revision number. This affects
 // the revision reported by Revision and the revision of the
 // charm archived by ArchiveTo.
@@ -241,7 +259,7 @@ func (dir *CharmDir) SetRevision(revision int) {
 // the revision file in the charm directory.
 func (dir *CharmDir) SetDiskRevision(revision int) error {
 	dir.SetRevision(revision)
-	file, err := os.OpenFile(dir.join("revision"), os.O_WRONLY|os.O_CREATE, 0644)
+	file, err := os.OpenFile(dir.join(RevisionFile), os.O_WRONLY|os.O_CREATE, 0644)
 	if err != nil {
 		return err
 	}
@@ -263,9 +281,51 @@ func resolveSymlinkedRoot(rootPath string) (string, error) {
 	return rootPath, nil
 }
 
+// ArchiveOptions holds options for CharmDir.ArchiveToWithOptions and
+// BundleDir.ArchiveToWithOptions. The zero value gives the archives
+// written by ArchiveTo.
+type ArchiveOptions struct {
+	// Store causes files to be stored without compression, which is
+	// much quicker than compressing them, at the cost of a larger
+	// archive.
+	Store bool
+
+	// Level holds the level files are compressed with, from
+	// flate.BestSpeed to flate.BestCompression, or flate.HuffmanOnly.
+	// If it is zero, flate.DefaultCompression is used. It is ignored
+	// if Store is true.
+	Level int
+
+	// Policy, if not nil, holds the content policy that the archived
+	// files must satisfy. If any file is denied, a
+	// *ContentPolicyError listing all the denied files is returned,
+	// and the archive written is incomplete.
+	Policy *ContentPolicy
+}
+
+// Validate returns an error if the options are not valid.
+func (opts ArchiveOptions) Validate() error {
+	if opts.Level != 0 && (opts.Level < flate.HuffmanOnly || opts.Level > flate.BestCompression) {
+		return errors.NotValidf("compression level %d", opts.Level)
+	}
+	if opts.Policy != nil {
+		return errors.Trace(opts.Policy.Validate())
+	}
+	return nil
+}
+
 // ArchiveTo creates a charm file from the charm expanded in dir.
 // By convention a charm archive should have a ".charm" suffix.
 func (dir *CharmDir) ArchiveTo(w io.Writer) error {
+	return dir.ArchiveToWithOptions(w, ArchiveOptions{})
+}
+
+// ArchiveToWithOptions is like ArchiveTo, but compresses the files of
+// the charm as configured by opts.
+func (dir *CharmDir) ArchiveToWithOptions(w io.Writer, opts ArchiveOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
 	ignoreRules, err := dir.buildIgnoreRules()
 	if err != nil {
 		return err
@@ -277,12 +337,110 @@ func (dir *CharmDir) ArchiveTo(w io.Writer) error {
 		logger.Warningf("trying to generate version string: %v", err)
 	}
 
-	return writeArchive(w, dir.Path, dir.revision, dir.version, dir.Meta().Hooks(), ignoreRules)
+	return writeArchive(w, dir.Path, dir.revision, dir.version, dir.Meta().Hooks(), ignoreRules, opts)
+}
+
+// ArchivePlanEntry describes a file considered for inclusion in a charm
+// archive by CharmDir.ArchivePlan.
+type ArchivePlanEntry struct {
+	// Path holds the slash-separated path of the file relative to
+	// the charm root. Directory paths end with a slash.
+	Path string
+
+	// Mode holds the mode the file is archived with. It is only set
+	// for packed files.
+	Mode os.FileMode
+
+	// Reason explains why the file is skipped or rejected, or notes
+	// a change made to a packed file.
+	Reason string
+}
+
+// ArchivePlan describes what CharmDir.ArchiveTo would do with the files
+// of a charm directory.
+type ArchivePlan struct {
+	// Packed holds the files that would be written to the archive,
+	// including the generated revision and version files.
+	Packed []ArchivePlanEntry
+
+	// Skipped holds the files excluded by the ignore rules.
+	Skipped []ArchivePlanEntry
+
+	// Rejected holds the files that would cause ArchiveTo to fail.
+	Rejected []ArchivePlanEntry
+}
+
+// ArchivePlan reports which files of the charm directory ArchiveTo would
+// pack, skip or reject, without writing anything. Unlike ArchiveTo, it
+// does not regenerate the version from the version control system, so
+// the version file is only listed if the charm directory has a version.
+func (dir *CharmDir) ArchivePlan() (*ArchivePlan, error) {
+	ignoreRules, err := dir.buildIgnoreRules()
+	if err != nil {
+		return nil, err
+	}
+	rootPath, err := resolveSymlinkedRoot(dir.Path)
+	if err != nil {
+		return nil, err
+	}
+	zp := zipPacker{root: rootPath, hooks: dir.Meta().Hooks(), ignoreRules: ignoreRules}
+
+	plan := &ArchivePlan{}
+	if dir.revision != -1 {
+		plan.Packed = append(plan.Packed, ArchivePlanEntry{Path: RevisionFile, Mode: 0644, Reason: "generated"})
+	}
+	if dir.version != "" {
+		plan.Packed = append(plan.Packed, ArchivePlanEntry{Path: VersionFile, Mode: 0644, Reason: "generated"})
+	}
+	err = filepath.Walk(rootPath, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		entry, err := zp.entry(path, fi)
+		switch {
+		case err != nil && entry != nil:
+			plan.Rejected = append(plan.Rejected, ArchivePlanEntry{Path: entry.relpath, Reason: err.Error()})
+			return nil
+		case err != nil:
+			return err
+		case entry.ignored:
+			plan.Skipped = append(plan.Skipped, ArchivePlanEntry{Path: entry.relpath, Reason: "ignored"})
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		case entry.relpath == "./":
+			return nil
+		}
+		if fi.Mode().IsRegular() {
+			file, err := os.Open(path)
+			if err != nil {
+				plan.Rejected = append(plan.Rejected, ArchivePlanEntry{Path: entry.relpath, Reason: err.Error()})
+				return nil
+			}
+			file.Close()
+		}
+		packed := ArchivePlanEntry{Path: entry.relpath, Mode: entry.header.Mode()}
+		if entry.madeExecutable {
+			packed.Reason = "made executable"
+		}
+		plan.Packed = append(plan.Packed, packed)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
 }
 
-func writeArchive(w io.Writer, path string, revision int, versionString string, hooks map[string]bool, ignoreRules ignoreRuleset) error {
+func writeArchive(w io.Writer, path string, revision int, versionString string, hooks map[string]bool, ignoreRules ignoreRuleset, opts ArchiveOptions) error {
 	zipw := zip.NewWriter(w)
 	defer zipw.Close()
+	if opts.Level != 0 {
+		zipw.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+			return flate.NewWriter(out, opts.Level)
+		})
+	}
 
 	// The root directory may be symlinked elsewhere so
 	// resolve that before creating the zip.
@@ -290,14 +448,28 @@ func writeArchive(w io.Writer, path string, revision int, versionString string,
 	if err != nil {
 		return err
 	}
-	zp := zipPacker{zipw, rootPath, hooks, ignoreRules}
+	zp := zipPacker{
+		Writer:      zipw,
+		root:        rootPath,
+		hooks:       hooks,
+		ignoreRules: ignoreRules,
+		store:       opts.Store,
+		policy:      opts.Policy,
+	}
 	if revision != -1 {
-		zp.AddFile("revision", strconv.Itoa(revision))
+		zp.AddFile(RevisionFile, strconv.Itoa(revision))
 	}
 	if versionString != "" {
-		zp.AddFile("version", versionString)
+		zp.AddFile(VersionFile, versionString)
+	}
+	if err := filepath.Walk(rootPath, zp.WalkFunc()); err != nil {
+		return err
 	}
-	return filepath.Walk(rootPath, zp.WalkFunc())
+	if len(zp.violations) > 0 {
+		sortContentViolations(zp.violations)
+		return &ContentPolicyError{Violations: zp.violations}
+	}
+	return nil
 }
 
 type zipPacker struct {
@@ -305,6 +477,15 @@ type zipPacker struct {
 	root        string
 	hooks       map[string]bool
 	ignoreRules ignoreRuleset
+
+	// store causes files to be stored without compression.
+	store bool
+
+	// policy, if not nil, holds the content policy checked for each
+	// file, and violations holds the files it denies, which are not
+	// written.
+	policy     *ContentPolicy
+	violations []ContentViolation
 }
 
 func (zp *zipPacker) WalkFunc() filepath.WalkFunc {
@@ -328,10 +509,85 @@ func (zp *zipPacker) visit(path string, fi os.FileInfo, err error) error {
 		return err
 	}
 
-	relpath, err := filepath.Rel(zp.root, path)
+	entry, err := zp.entry(path, fi)
 	if err != nil {
 		return err
 	}
+	if entry.ignored {
+		if fi.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	}
+	if entry.madeExecutable {
+		logger.Warningf("making %q executable in charm", path)
+	}
+
+	var content io.Reader
+	if fi.Mode().IsRegular() {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		content = file
+	}
+	if zp.policy != nil && !fi.IsDir() {
+		var reason string
+		var denied bool
+		if content != nil {
+			reason, denied, content, err = zp.policy.check(entry.relpath, content)
+			if err != nil {
+				return err
+			}
+		} else {
+			reason, denied = zp.policy.deniedName(entry.relpath)
+		}
+		if denied {
+			zp.violations = append(zp.violations, ContentViolation{Path: entry.relpath, Reason: reason})
+			return nil
+		}
+	}
+
+	w, err := zp.CreateHeader(entry.header)
+	if err != nil || fi.IsDir() {
+		return err
+	}
+	if fi.Mode()&os.ModeSymlink != 0 {
+		_, err = w.Write([]byte(entry.target))
+	} else {
+		_, err = io.Copy(w, content)
+	}
+	return err
+}
+
+// packEntry describes how a file in a charm directory is archived.
+type packEntry struct {
+	// relpath holds the slash-separated path of the file relative
+	// to the charm root.
+	relpath string
+
+	// ignored is true if the file is excluded by the ignore rules.
+	ignored bool
+
+	// header holds the zip header the file is archived with.
+	header *zip.FileHeader
+
+	// target holds the target of a symlink.
+	target string
+
+	// madeExecutable is true if the file is a hook that is made
+	// executable in the archive.
+	madeExecutable bool
+}
+
+// entry returns how the file at path, described by fi, is archived.
+// It returns an error if the file cannot be archived.
+func (zp *zipPacker) entry(path string, fi os.FileInfo) (*packEntry, error) {
+	relpath, err := filepath.Rel(zp.root, path)
+	if err != nil {
+		return nil, err
+	}
 
 	// Replace any Windows path separators with "/".
 	// zip file spec 4.4.17.1 says that separators are always "/" even on Windows.
@@ -339,25 +595,33 @@ func (zp *zipPacker) visit(path string, fi os.FileInfo, err error) error {
 
 	// Check if this file or dir needs to be ignored
 	if zp.ignoreRules.Match(relpath, fi.IsDir()) {
-		if fi.IsDir() {
-			return filepath.SkipDir
-		}
-
-		return nil
+		return &packEntry{relpath: relpath, ignored: true}, nil
 	}
 
 	method := zip.Deflate
+	if zp.store {
+		method = zip.Store
+	}
 	if fi.IsDir() {
 		relpath += "/"
 		method = zip.Store
 	}
 
+	e := &packEntry{relpath: relpath}
 	mode := fi.Mode()
 	if err := checkFileType(relpath, mode); err != nil {
-		return err
+		return e, err
 	}
 	if mode&os.ModeSymlink != 0 {
 		method = zip.Store
+		target, err := os.Readlink(path)
+		if err != nil {
+			return e, err
+		}
+		if err := checkSymlinkTarget(zp.root, relpath, target); err != nil {
+			return e, err
+		}
+		e.target = target
 	}
 	h := &zip.FileHeader{
 		Name:   relpath,
@@ -370,39 +634,16 @@ func (zp *zipPacker) visit(path string, fi os.FileInfo, err error) error {
 	} else if mode&0100 != 0 {
 		perm = 0755
 	}
-	if filepath.Dir(relpath) == "hooks" {
+	if filepath.Dir(relpath) == HooksDir {
 		hookName := filepath.Base(relpath)
 		if _, ok := zp.hooks[hookName]; ok && !fi.IsDir() && mode&0100 == 0 {
-			logger.Warningf("making %q executable in charm", path)
+			e.madeExecutable = true
 			perm = perm | 0100
 		}
 	}
 	h.SetMode(mode&^0777 | perm)
-
-	w, err := zp.CreateHeader(h)
-	if err != nil || fi.IsDir() {
-		return err
-	}
-	var data []byte
-	if mode&os.ModeSymlink != 0 {
-		target, err := os.Readlink(path)
-		if err != nil {
-			return err
-		}
-		if err := checkSymlinkTarget(zp.root, relpath, target); err != nil {
-			return err
-		}
-		data = []byte(target)
-		_, err = w.Write(data)
-	} else {
-		file, err := os.Open(path)
-		if err != nil {
-			return err
-		}
-		defer file.Close()
-		_, err = io.Copy(w, file)
-	}
-	return err
+	e.header = h
+	return e, nil
 }
 
 func checkSymlinkTarget(basedir, symlink, target string) error {
@@ -562,7 +803,7 @@ func (dir *CharmDir) MaybeGenerateVersionString(logger Logger) (string, string,
 	}
 
 	// If all strategies fail we fallback to check the version below
-	if file, err := os.Open(dir.join("version")); err == nil {
+	if file, err := os.Open(dir.join(VersionFile)); err == nil {
 		logger.Debugf("charm is not in version control, but uses a version file, charm path %q", absPath)
 		ver, err := ReadVersion(file)
 		file.Close()
@@ -6,6 +6,7 @@ package charm_test
 import (
 	"archive/zip"
 	"bytes"
+	"compress/flate"
 	"context"
 	"fmt"
 	"io/ioutil"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/juju/charm/v8"
 	"github.com/juju/collections/set"
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -111,6 +113,44 @@ func (s *CharmDirSuite) TestArchiveTo(c *gc.C) {
 	s.assertArchiveTo(c, baseDir, charmDir)
 }
 
+func (s *CharmDirSuite) TestArchiveToWithOptions(c *gc.C) {
+	charmDir := cloneDir(c, charmDirPath(c, "dummy"))
+	// Add a file that compresses well, so that the levels differ.
+	err := ioutil.WriteFile(filepath.Join(charmDir, "data.txt"), bytes.Repeat([]byte("compressible "), 10000), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	dir, err := charm.ReadCharmDir(charmDir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	archive := func(opts charm.ArchiveOptions) []byte {
+		var buf bytes.Buffer
+		err := dir.ArchiveToWithOptions(&buf, opts)
+		c.Assert(err, jc.ErrorIsNil)
+		zipr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		c.Assert(err, jc.ErrorIsNil)
+		for _, f := range zipr.File {
+			if f.Name == "data.txt" {
+				if opts.Store {
+					c.Check(f.Method, gc.Equals, zip.Store)
+				} else {
+					c.Check(f.Method, gc.Equals, zip.Deflate)
+				}
+			}
+		}
+		_, err = charm.ReadCharmArchiveBytes(buf.Bytes())
+		c.Assert(err, jc.ErrorIsNil)
+		return buf.Bytes()
+	}
+	stored := archive(charm.ArchiveOptions{Store: true})
+	fast := archive(charm.ArchiveOptions{Level: flate.HuffmanOnly})
+	best := archive(charm.ArchiveOptions{Level: flate.BestCompression})
+	c.Assert(len(stored), jc.GreaterThan, len(fast))
+	c.Assert(len(fast), jc.GreaterThan, len(best))
+
+	err = dir.ArchiveToWithOptions(ioutil.Discard, charm.ArchiveOptions{Level: 10})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `compression level 10 not valid`)
+}
+
 func (s *CharmDirSuite) TestArchiveToWithIgnoredFiles(c *gc.C) {
 	charmDir := cloneDir(c, charmDirPath(c, "dummy"))
 	dir, err := charm.ReadCharmDir(charmDir)
@@ -507,6 +547,69 @@ func (s *CharmDirSuite) TestArchiveToWithBadType(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `file is a named pipe: "hooks/badfile"`)
 }
 
+func (s *CharmDirSuite) TestArchivePlan(c *gc.C) {
+	charmDir := cloneDir(c, charmDirPath(c, "dummy"))
+
+	// An ignored directory, a hook that must be made executable and
+	// a symlink that cannot be archived.
+	err := os.MkdirAll(filepath.Join(charmDir, ".git", "objects"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(charmDir, "hooks", "start"), []byte("#!/bin/sh\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.Symlink("/target", filepath.Join(charmDir, "hooks", "badlink"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	dir, err := charm.ReadCharmDir(charmDir)
+	c.Assert(err, jc.ErrorIsNil)
+	plan, err := dir.ArchivePlan()
+	c.Assert(err, jc.ErrorIsNil)
+
+	packed := make(map[string]charm.ArchivePlanEntry)
+	for _, entry := range plan.Packed {
+		packed[entry.Path] = entry
+	}
+	c.Check(packed["revision"].Reason, gc.Equals, "generated")
+	c.Check(packed["metadata.yaml"].Mode, gc.Equals, os.FileMode(0644))
+	c.Check(packed["hooks/"].Mode, gc.Equals, os.ModeDir|0755)
+	c.Check(packed["hooks/install"].Mode, gc.Equals, os.FileMode(0755))
+	c.Check(packed["hooks/start"], jc.DeepEquals, charm.ArchivePlanEntry{
+		Path:   "hooks/start",
+		Mode:   0744,
+		Reason: "made executable",
+	})
+	_, ok := packed[".git/"]
+	c.Check(ok, jc.IsFalse)
+
+	c.Check(plan.Skipped, jc.DeepEquals, []charm.ArchivePlanEntry{
+		{Path: ".git", Reason: "ignored"},
+		{Path: "build", Reason: "ignored"},
+		{Path: "revision", Reason: "ignored"},
+	})
+	c.Check(plan.Rejected, jc.DeepEquals, []charm.ArchivePlanEntry{
+		{Path: "hooks/badlink", Reason: `symlink "hooks/badlink" is absolute: "/target"`},
+	})
+
+	// ArchivePlan writes nothing, and ArchiveTo agrees that the
+	// rejected file cannot be archived.
+	err = dir.ArchiveTo(&bytes.Buffer{})
+	c.Assert(err, gc.ErrorMatches, `symlink "hooks/badlink" is absolute: "/target"`)
+}
+
+func (s *CharmDirSuite) TestArchivePlanMatchesArchive(c *gc.C) {
+	charmDir := cloneDir(c, charmDirPath(c, "dummy"))
+	dir, err := charm.ReadCharmDir(charmDir)
+	c.Assert(err, jc.ErrorIsNil)
+	plan, err := dir.ArchivePlan()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plan.Rejected, gc.HasLen, 0)
+
+	var planned []string
+	for _, entry := range plan.Packed {
+		planned = append(planned, strings.TrimSuffix(entry.Path, "/"))
+	}
+	c.Assert(planned, jc.SameContents, dummyManifest)
+}
+
 func (s *CharmDirSuite) TestDirRevisionFile(c *gc.C) {
 	charmDir := cloneDir(c, charmDirPath(c, "dummy"))
 	revPath := filepath.Join(charmDir, "revision")
@@ -0,0 +1,115 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+	"github.com/juju/charm/v8/resource"
+)
+
+type ExportSuite struct{}
+
+var _ = gc.Suite(&ExportSuite{})
+
+func (s *ExportSuite) generateKey(c *gc.C) (ed25519.PublicKey, ed25519.PrivateKey) {
+	public, private, err := ed25519.GenerateKey(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	return public, private
+}
+
+func (s *ExportSuite) TestExportImport(c *gc.C) {
+	public, private := s.generateKey(c)
+	dir := readCharmDir(c, "dummy")
+	archive, err := charm.ReadCharmArchive(archivePath(c, readCharmDir(c, "mysql")))
+	c.Assert(err, jc.ErrorIsNil)
+
+	var buf bytes.Buffer
+	err = charm.Export([]charm.Charm{dir, archive}, &buf, private)
+	c.Assert(err, jc.ErrorIsNil)
+
+	imported, err := charm.Import(&buf, public)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(imported, gc.HasLen, 2)
+	c.Check(imported[0].URL, jc.DeepEquals, &charm.URL{
+		Schema:   "local",
+		Name:     "dummy",
+		Revision: dir.Revision(),
+	})
+	c.Check(imported[0].Charm.Meta().Name, gc.Equals, "dummy")
+	c.Check(imported[1].URL.String(), gc.Equals, "local:mysql-1")
+	c.Check(imported[1].Charm.Meta().Name, gc.Equals, "mysql")
+}
+
+func (s *ExportSuite) TestExportWithHashAlgorithm(c *gc.C) {
+	public, private := s.generateKey(c)
+	var buf bytes.Buffer
+	err := charm.ExportWithOptions([]charm.Charm{readCharmDir(c, "dummy")}, &buf, private, charm.ExportOptions{
+		HashAlgorithm: resource.SHA256,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	data := buf.Bytes()
+
+	pack, err := charm.ReadPack(bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pack.Members(), gc.HasLen, 1)
+	c.Check(pack.Members()[0].Digest, gc.Matches, "sha256:[0-9a-f]{64}")
+
+	imported, err := charm.Import(bytes.NewReader(data), public)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(imported, gc.HasLen, 1)
+	c.Check(imported[0].URL.String(), gc.Equals, "local:dummy-1")
+
+	err = charm.ExportWithOptions(nil, &buf, private, charm.ExportOptions{
+		HashAlgorithm: "md5",
+	})
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ExportSuite) TestImportWithWrongKey(c *gc.C) {
+	_, private := s.generateKey(c)
+	other, _ := s.generateKey(c)
+	var buf bytes.Buffer
+	err := charm.Export([]charm.Charm{readCharmDir(c, "dummy")}, &buf, private)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = charm.Import(&buf, other)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, "pack signature not valid")
+}
+
+func (s *ExportSuite) TestImportUnsignedPack(c *gc.C) {
+	public, _ := s.generateKey(c)
+	var archive bytes.Buffer
+	err := readCharmDir(c, "dummy").ArchiveTo(&archive)
+	c.Assert(err, jc.ErrorIsNil)
+	var buf bytes.Buffer
+	pw := charm.NewPackWriter(&buf)
+	c.Assert(pw.Add("dummy", charm.PackCharm, &archive), jc.ErrorIsNil)
+	c.Assert(pw.Close(), jc.ErrorIsNil)
+
+	_, err = charm.Import(&buf, public)
+	c.Assert(err, gc.ErrorMatches, "pack without pack.yaml.sig not valid")
+}
+
+func (s *ExportSuite) TestExportDuplicateCharm(c *gc.C) {
+	_, private := s.generateKey(c)
+	dir := readCharmDir(c, "dummy")
+	var buf bytes.Buffer
+	err := charm.Export([]charm.Charm{dir, dir}, &buf, private)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(err, gc.ErrorMatches, `exporting charm "local:dummy-1": pack member "dummy" already exists`)
+}
+
+func (s *ExportSuite) TestExportInvalidKey(c *gc.C) {
+	var buf bytes.Buffer
+	err := charm.Export(nil, &buf, nil)
+	c.Assert(err, gc.ErrorMatches, "signing key not valid")
+}
@@ -0,0 +1,43 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"os"
+)
+
+// ManifestFile is the file, relative to the root of a charm, in which
+// charms built by charmcraft declare the bases they run on. Its presence
+// marks a format 2 charm even when metadata.yaml declares no bases.
+const ManifestFile = "manifest.yaml"
+
+// FormatOf returns the format of the charm: FormatV2 if its metadata
+// declares bases or systems, or if it holds a manifest.yaml file, and
+// FormatV1 otherwise. Only charm directories and archives are examined
+// for manifest.yaml; other charms are classified by their metadata
+// alone. Note that a dispatch executable does not make a charm format 2,
+// as format 1 charms written with the operator framework also have one.
+func FormatOf(ch Charm) Format {
+	if ch.Meta().Format() == FormatV2 {
+		return FormatV2
+	}
+	if hasCharmFile(ch, ManifestFile) {
+		return FormatV2
+	}
+	return FormatV1
+}
+
+// hasCharmFile reports whether the charm, if it is a directory or an
+// archive, holds the file with the given path relative to its root.
+func hasCharmFile(ch Charm, name string) bool {
+	switch ch := ch.(type) {
+	case *CharmDir:
+		_, err := os.Stat(ch.join(name))
+		return err == nil
+	case *CharmArchive:
+		manifest, err := ch.Manifest()
+		return err == nil && manifest.Contains(name)
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type CharmFormatSuite struct{}
+
+var _ = gc.Suite(&CharmFormatSuite{})
+
+func (s *CharmFormatSuite) TestFormatOf(c *gc.C) {
+	dir := readCharmDir(c, "dummy")
+	c.Check(charm.FormatOf(dir), gc.Equals, charm.Format(charm.FormatV1))
+
+	archive, err := charm.ReadCharmArchive(archivePath(c, dir))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(charm.FormatOf(archive), gc.Equals, charm.Format(charm.FormatV1))
+
+	// A manifest makes a charm format 2.
+	path := cloneDir(c, charmDirPath(c, "dummy"))
+	err = ioutil.WriteFile(filepath.Join(path, charm.ManifestFile), []byte("bases: []\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	dir, err = charm.ReadCharmDir(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(charm.FormatOf(dir), gc.Equals, charm.Format(charm.FormatV2))
+
+	archive, err = charm.ReadCharmArchive(archivePath(c, dir))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(charm.FormatOf(archive), gc.Equals, charm.Format(charm.FormatV2))
+}
+
+func (s *CharmFormatSuite) TestFormatOfMetadata(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(dummyMetadata + "\nbases: [{name: ubuntu, channel: '22.04'}]\n"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(charm.FormatOf(&formatTestCharm{meta: meta}), gc.Equals, charm.Format(charm.FormatV2))
+
+	meta, err = charm.ReadMeta(strings.NewReader(dummyMetadata))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(charm.FormatOf(&formatTestCharm{meta: meta}), gc.Equals, charm.Format(charm.FormatV1))
+}
+
+type formatTestCharm struct {
+	charm.Charm
+	meta *charm.Meta
+}
+
+func (ch *formatTestCharm) Meta() *charm.Meta {
+	return ch.meta
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// ReadCharmOptions holds options for ReadCharmWithOptions,
+// ReadCharmDirWithOptions and ReadCharmArchiveWithOptions.
+type ReadCharmOptions struct {
+	// CheckName causes the name in the charm's metadata to be checked
+	// against the base name of its path, as by CheckNameMatchesPath.
+	CheckName bool
+}
+
+// ReadCharmWithOptions is like ReadCharm, but reads the charm as
+// configured by opts.
+func ReadCharmWithOptions(path string, opts ReadCharmOptions) (Charm, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return ReadCharmDirWithOptions(path, opts)
+	}
+	return ReadCharmArchiveWithOptions(path, opts)
+}
+
+// ReadCharmDirWithOptions is like ReadCharmDir, but reads the charm
+// directory as configured by opts.
+func ReadCharmDirWithOptions(path string, opts ReadCharmOptions) (*CharmDir, error) {
+	dir, err := ReadCharmDir(path)
+	if err != nil {
+		return nil, err
+	}
+	if opts.CheckName {
+		if err := CheckNameMatchesPath(dir.Meta().Name, path); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return dir, nil
+}
+
+// ReadCharmArchiveWithOptions is like ReadCharmArchive, but reads the
+// charm archive as configured by opts.
+func ReadCharmArchiveWithOptions(path string, opts ReadCharmOptions) (*CharmArchive, error) {
+	archive, err := ReadCharmArchive(path)
+	if err != nil {
+		return nil, err
+	}
+	if opts.CheckName {
+		if err := CheckNameMatchesPath(archive.Meta().Name, path); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return archive, nil
+}
+
+// charmPathRevision matches the revision that may follow the name of a
+// charm in the base name of its directory or archive, as in "mysql-42".
+var charmPathRevision = regexp.MustCompile(`-[0-9]+$`)
+
+// CheckNameMatchesPath returns an error satisfying errors.IsNotValid if
+// the charm name does not match the base name of path, the charm's
+// directory or archive. The base name may have a ".charm" or ".zip"
+// extension, a revision suffix, as in "mysql-42", and the bases
+// appended to archives by charmcraft, as in
+// "mysql_ubuntu-22.04-amd64.charm". Mismatches are a frequent source of
+// confusing deployment errors.
+func CheckNameMatchesPath(name, path string) error {
+	base := filepath.Base(filepath.Clean(path))
+	for _, ext := range []string{".charm", ".zip"} {
+		base = strings.TrimSuffix(base, ext)
+	}
+	if base == name {
+		return nil
+	}
+	// Charm names never hold underscores, which charmcraft uses to
+	// separate the name from the bases.
+	if i := strings.IndexByte(base, '_'); i >= 0 {
+		base = base[:i]
+	}
+	if base == name || charmPathRevision.ReplaceAllString(base, "") == name {
+		return nil
+	}
+	return errors.NewNotValid(nil, fmt.Sprintf("charm name %q does not match path %q", name, path))
+}
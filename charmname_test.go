@@ -0,0 +1,75 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type CharmNameSuite struct{}
+
+var _ = gc.Suite(&CharmNameSuite{})
+
+func (s *CharmNameSuite) TestCheckNameMatchesPath(c *gc.C) {
+	for i, test := range []struct {
+		name  string
+		path  string
+		match bool
+	}{
+		{"mysql", "/charms/mysql", true},
+		{"mysql", "/charms/mysql/", true},
+		{"mysql", "mysql-42", true},
+		{"mysql", "/charms/mysql.charm", true},
+		{"mysql", "/charms/mysql-7.zip", true},
+		{"mysql", "/charms/mysql_ubuntu-22.04-amd64.charm", true},
+		{"mysql-8", "/charms/mysql-8", true},
+		{"mysql-8", "/charms/mysql-8-3.charm", true},
+		{"mysql", "/charms/wordpress", false},
+		{"mysql", "/charms/mysql-server", false},
+		{"mysql", "/charms/mysql-x1", false},
+		{"mysql", "/charms/archive", false},
+	} {
+		c.Logf("test %d: %s %s", i, test.name, test.path)
+		err := charm.CheckNameMatchesPath(test.name, test.path)
+		if test.match {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, jc.Satisfies, errors.IsNotValid)
+			c.Check(err, gc.ErrorMatches, `charm name ".*" does not match path ".*"`)
+		}
+	}
+}
+
+func (s *CharmNameSuite) TestReadCharmWithOptions(c *gc.C) {
+	path := cloneDir(c, charmDirPath(c, "dummy"))
+	opts := charm.ReadCharmOptions{CheckName: true}
+	ch, err := charm.ReadCharmWithOptions(path, opts)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ch.Meta().Name, gc.Equals, "dummy")
+
+	renamed := filepath.Join(filepath.Dir(path), "mysql")
+	err = os.Rename(path, renamed)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = charm.ReadCharmDir(renamed)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = charm.ReadCharmDirWithOptions(renamed, opts)
+	c.Assert(err, gc.ErrorMatches, `charm name "dummy" does not match path ".*/mysql"`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	archive := archivePath(c, readCharmDir(c, "dummy"))
+	_, err = charm.ReadCharmWithOptions(archive, opts)
+	c.Assert(err, gc.ErrorMatches, `charm name "dummy" does not match path ".*/archive"`)
+	named := filepath.Join(filepath.Dir(archive), "dummy-3.charm")
+	err = os.Rename(archive, named)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = charm.ReadCharmArchiveWithOptions(named, opts)
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -6,9 +6,16 @@ package charm
 import (
 	"fmt"
 	"io"
-	"io/ioutil"
+	"math"
+	"reflect"
+	"regexp"
+	"runtime"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/schema"
 	"gopkg.in/yaml.v2"
 )
@@ -23,6 +30,41 @@ type Option struct {
 	Type        string      `yaml:"type"`
 	Description string      `yaml:"description,omitempty"`
 	Default     interface{} `yaml:"default,omitempty"`
+
+	// Deprecated holds whether the option is deprecated. Setting a
+	// deprecated option is reported by
+	// Config.ValidateSettingsWithWarnings.
+	Deprecated bool `yaml:"deprecated,omitempty"`
+
+	// ReplacedBy holds the name of the option that replaces a
+	// deprecated one, if any.
+	ReplacedBy string `yaml:"replaced-by,omitempty"`
+
+	// Allowed holds the values that a string or int option may take.
+	// If it is empty, any value of the option's type is allowed.
+	Allowed []interface{} `yaml:"allowed,omitempty"`
+
+	// Minimum and Maximum hold the bounds, inclusive, of the values
+	// that an int or float option may take. A nil bound is not
+	// checked.
+	Minimum *float64 `yaml:"minimum,omitempty"`
+	Maximum *float64 `yaml:"maximum,omitempty"`
+
+	// Pattern holds a regular expression, in the syntax of the regexp
+	// package, that the whole of the value of a string option must
+	// match. If it is empty, any string is allowed.
+	Pattern string `yaml:"pattern,omitempty"`
+
+	// Sensitive holds whether the values of the option must not be
+	// shown, as they hold credentials or other secrets. Options of
+	// type secret are always sensitive.
+	Sensitive bool `yaml:"sensitive,omitempty"`
+}
+
+// IsSensitive reports whether the values of the option must not be
+// shown, because it is of type secret or is marked sensitive.
+func (option Option) IsSensitive() bool {
+	return option.Sensitive || option.Type == "secret"
 }
 
 // error replaces any supplied non-nil error with a new error describing a
@@ -34,9 +76,109 @@ func (option Option) error(err *error, name string, value interface{}) {
 }
 
 // validate returns an appropriately-typed value for the supplied value, or
+// returns an error if it cannot be converted to the correct type or is not
+// one of the allowed values. Nil values are always considered valid.
+func (option Option) validate(name string, value interface{}) (interface{}, error) {
+	value, err := option.coerce(name, value)
+	if err != nil {
+		return nil, err
+	}
+	return value, option.checkConstraints(name, value)
+}
+
+// checkConstraints returns an error if the supplied value, which must
+// already have the correct type, is not one of the option's allowed
+// values, is out of its range or does not match its pattern. Nil values
+// are always considered valid.
+func (option Option) checkConstraints(name string, value interface{}) error {
+	if err := option.checkAllowed(name, value); err != nil {
+		return err
+	}
+	if err := option.checkRange(name, value); err != nil {
+		return err
+	}
+	return option.checkPattern(name, value)
+}
+
+// checkAllowed returns an error if the option has allowed values and
+// the supplied value is not one of them. The allowed values are coerced
+// to the option's type first, as they need not have been read by
+// ReadConfig. Values that cannot be compared, such as lists, are
+// ignored.
+func (option Option) checkAllowed(name string, value interface{}) error {
+	if value == nil || len(option.Allowed) == 0 || !reflect.TypeOf(value).Comparable() {
+		return nil
+	}
+	allowed := make([]string, len(option.Allowed))
+	for i, v := range option.Allowed {
+		if coerced, err := option.coerce(name, v); err == nil && coerced != nil {
+			v = coerced
+		}
+		if v != nil && reflect.TypeOf(v).Comparable() && v == value {
+			return nil
+		}
+		allowed[i] = fmt.Sprintf("%#v", v)
+	}
+	return fmt.Errorf("option %q expected one of %s, got %#v", name, strings.Join(allowed, ", "), value)
+}
+
+// checkRange returns an error if the supplied int or float value is
+// outside the option's bounds.
+func (option Option) checkRange(name string, value interface{}) error {
+	var f float64
+	switch value := value.(type) {
+	case int64:
+		f = float64(value)
+	case float64:
+		f = value
+	default:
+		return nil
+	}
+	min, max := option.Minimum, option.Maximum
+	switch {
+	case min != nil && max != nil && (f < *min || f > *max):
+		return fmt.Errorf("option %q expected a value between %v and %v, got %v", name, *min, *max, value)
+	case min != nil && f < *min:
+		return fmt.Errorf("option %q expected a value of at least %v, got %v", name, *min, value)
+	case max != nil && f > *max:
+		return fmt.Errorf("option %q expected a value of at most %v, got %v", name, *max, value)
+	}
+	return nil
+}
+
+// checkPattern returns an error if the supplied string value does not
+// match the option's pattern.
+func (option Option) checkPattern(name string, value interface{}) error {
+	str, ok := value.(string)
+	if !ok || option.Pattern == "" {
+		return nil
+	}
+	re, err := compileOptionPattern(option.Pattern)
+	if err != nil {
+		return fmt.Errorf("option %q has invalid pattern: %v", name, err)
+	}
+	if !re.MatchString(str) {
+		return fmt.Errorf("option %q expected a value matching %q, got %q", name, option.Pattern, str)
+	}
+	return nil
+}
+
+// compileOptionPattern compiles the pattern of an option so that it
+// matches whole values only.
+func compileOptionPattern(pattern string) (*regexp.Regexp, error) {
+	// Compile the pattern alone first, so that errors refer to it as
+	// written and patterns such as "a)|(b" are not made valid by
+	// the anchoring.
+	if _, err := regexp.Compile(pattern); err != nil {
+		return nil, err
+	}
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// coerce returns an appropriately-typed value for the supplied value, or
 // returns an error if it cannot be converted to the correct type. Nil values
 // are always considered valid.
-func (option Option) validate(name string, value interface{}) (_ interface{}, err error) {
+func (option Option) coerce(name string, value interface{}) (_ interface{}, err error) {
 	if value == nil {
 		return nil, nil
 	}
@@ -51,13 +193,163 @@ func (option Option) validate(name string, value interface{}) (_ interface{}, er
 }
 
 var optionTypeCheckers = map[string]schema.Checker{
-	"string":  schema.String(),
-	"int":     schema.Int(),
-	"float":   schema.Float(),
-	"boolean": schema.Bool(),
+	"string":   schema.String(),
+	"int":      schema.Int(),
+	"float":    floatC{},
+	"boolean":  schema.Bool(),
+	"duration": durationC{},
+	"size":     sizeC{},
+	"list":     listC{},
+	"secret":   secretC{},
+}
+
+// floatC coerces numbers to float64. Unlike schema.Float, it accepts
+// integers, so that a float option may be written as "default: 1".
+type floatC struct{}
+
+func (floatC) Coerce(v interface{}, path []string) (interface{}, error) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	}
+	return schema.Float().Coerce(v, path)
+}
+
+// durationC coerces Go duration strings, such as "1h30m", to an int64
+// number of nanoseconds. Integers are taken to be nanoseconds already.
+type durationC struct{}
+
+func (durationC) Coerce(v interface{}, path []string) (interface{}, error) {
+	if str, ok := v.(string); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(str))
+		if err != nil {
+			return nil, errors.Errorf("%sexpected duration, got %q", schemaPathPrefix(path), str)
+		}
+		return int64(d), nil
+	}
+	return schema.Int().Coerce(v, path)
+}
+
+// sizeUnits maps the unit suffixes accepted by sizeC to the number of
+// bytes they stand for. Units are binary, as for memory and disk sizes
+// elsewhere in Juju.
+var sizeUnits = map[string]float64{
+	"":  1,
+	"K": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+	"T": 1 << 40,
+	"P": 1 << 50,
+}
+
+var validSize = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([KMGTP]?)(?:I?B)?$`)
+
+// sizeC coerces sizes such as "512M" or "2G" to an int64 number of
+// bytes. Integers are taken to be bytes already.
+type sizeC struct{}
+
+func (sizeC) Coerce(v interface{}, path []string) (interface{}, error) {
+	str, ok := v.(string)
+	if !ok {
+		return schema.Int().Coerce(v, path)
+	}
+	m := validSize.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(str)))
+	if m == nil {
+		return nil, errors.Errorf("%sexpected size, got %q", schemaPathPrefix(path), str)
+	}
+	n, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return nil, errors.Errorf("%sexpected size, got %q", schemaPathPrefix(path), str)
+	}
+	size := n * sizeUnits[m[2]]
+	if size > math.MaxInt64 {
+		return nil, errors.Errorf("%ssize %q too large", schemaPathPrefix(path), str)
+	}
+	return int64(size), nil
+}
+
+// listC coerces lists of strings to []string.
+type listC struct{}
+
+func (listC) Coerce(v interface{}, path []string) (interface{}, error) {
+	if list, ok := v.([]string); ok {
+		return list, nil
+	}
+	items, err := schema.List(schema.String()).Coerce(v, path)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]string, len(items.([]interface{})))
+	for i, item := range items.([]interface{}) {
+		list[i] = item.(string)
+	}
+	return list, nil
+}
+
+// parseList returns the list held in str, either as a YAML flow
+// sequence, such as "[a, b]", or as comma-separated items, such as
+// "a, b". Empty items are dropped.
+func parseList(str string) ([]string, error) {
+	str = strings.TrimSpace(str)
+	if strings.HasPrefix(str, "[") {
+		var list []string
+		if err := yaml.Unmarshal([]byte(str), &list); err != nil {
+			return nil, err
+		}
+		return list, nil
+	}
+	list := []string{}
+	for _, item := range strings.Split(str, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list, nil
+}
+
+// validSecretURI matches the URIs of Juju secrets, such as
+// "secret:9m4e2mr0ui3e8a215n4g" or
+// "secret://e6b2e2a4-1a31-4d3a-8a1e-bd0a9a4b5e4d/9m4e2mr0ui3e8a215n4g".
+var validSecretURI = regexp.MustCompile(`^secret:(//[^/\s]+/)?[^/\s]+$`)
+
+// secretC checks that values are secret URIs. The option holds a
+// reference to the secret, so that its content never appears in the
+// application config.
+type secretC struct{}
+
+func (secretC) Coerce(v interface{}, path []string) (interface{}, error) {
+	str, err := schema.String().Coerce(v, path)
+	if err != nil {
+		return nil, err
+	}
+	if !validSecretURI.MatchString(str.(string)) {
+		return nil, errors.Errorf("%sexpected secret URI, got %q", schemaPathPrefix(path), str)
+	}
+	return str, nil
 }
 
-func (option Option) parse(name, str string) (val interface{}, err error) {
+// schemaPathPrefix returns path as a prefix for error messages, in the
+// form used by the schema package.
+func schemaPathPrefix(path []string) string {
+	if len(path) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(strings.Join(path, ""), ".") + ": "
+}
+
+func (option Option) parse(name, str string) (interface{}, error) {
+	val, err := option.parseValue(name, str)
+	if err != nil {
+		return nil, err
+	}
+	return val, option.checkConstraints(name, val)
+}
+
+// parseValue is like parse, but does not check the option's allowed
+// values, range or pattern.
+func (option Option) parseValue(name, str string) (val interface{}, err error) {
 	switch option.Type {
 	case "string":
 		return str, nil
@@ -67,6 +359,10 @@ func (option Option) parse(name, str string) (val interface{}, err error) {
 		val, err = strconv.ParseFloat(str, 64)
 	case "boolean":
 		val, err = strconv.ParseBool(str)
+	case "duration", "size", "secret":
+		val, err = optionTypeCheckers[option.Type].Coerce(str, nil)
+	case "list":
+		val, err = parseList(str)
 	default:
 		return nil, fmt.Errorf("option %q has unknown type %q", name, option.Type)
 	}
@@ -86,12 +382,84 @@ func NewConfig() *Config {
 	return &Config{map[string]Option{}}
 }
 
+// optionOrders holds the names of the options of each config read by
+// ReadConfig, in the order in which they were read, keyed by the
+// address of the config. It is kept outside Config so that configs
+// remain comparable and constructible as plain values. Entries are
+// removed when their config is garbage collected.
+var (
+	optionOrdersMutex sync.Mutex
+	optionOrders      = make(map[uintptr][]string)
+)
+
+// setOptionOrder records the order of the options of c, which must have
+// been newly allocated.
+func setOptionOrder(c *Config, names []string) {
+	optionOrdersMutex.Lock()
+	defer optionOrdersMutex.Unlock()
+	optionOrders[reflect.ValueOf(c).Pointer()] = names
+	runtime.SetFinalizer(c, func(c *Config) {
+		optionOrdersMutex.Lock()
+		defer optionOrdersMutex.Unlock()
+		delete(optionOrders, reflect.ValueOf(c).Pointer())
+	})
+}
+
+// optionOrderOf returns the order of the options of c recorded by
+// ReadConfig, if any.
+func optionOrderOf(c *Config) []string {
+	optionOrdersMutex.Lock()
+	defer optionOrdersMutex.Unlock()
+	return optionOrders[reflect.ValueOf(c).Pointer()]
+}
+
+// MarshalYAML implements yaml.Marshaler, so that a Config can be
+// written back to a config.yaml file. The options of a config read by
+// ReadConfig are written in the order in which they were read, followed
+// by any added since; other options are sorted by name. Options without
+// a default are written without one.
+func (c *Config) MarshalYAML() (interface{}, error) {
+	options := make(yaml.MapSlice, 0, len(c.Options))
+	written := make(map[string]bool)
+	names := append(append([]string(nil), optionOrderOf(c)...), sortedNames(c.Options)...)
+	for _, name := range names {
+		option, ok := c.Options[name]
+		if !ok || written[name] {
+			continue
+		}
+		written[name] = true
+		options = append(options, yaml.MapItem{Key: name, Value: option})
+	}
+	return yaml.MapSlice{{Key: "options", Value: options}}, nil
+}
+
+// optionOrder returns the names of the options in the config.yaml data,
+// in the order in which they appear.
+func optionOrder(data []byte) []string {
+	var raw struct {
+		Options yaml.MapSlice `yaml:"options"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+	var names []string
+	for _, item := range raw.Options {
+		if name, ok := item.Key.(string); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // ReadConfig reads a Config in YAML format.
-func ReadConfig(r io.Reader) (*Config, error) {
-	data, err := ioutil.ReadAll(r)
+func ReadConfig(r io.Reader) (_ *Config, err error) {
+	data, err := readYAMLInput(r)
 	if err != nil {
 		return nil, err
 	}
+	defer func() {
+		reportParseFailure(ConfigFile, data, err)
+	}()
 	var config *Config
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, err
@@ -113,24 +481,82 @@ func ReadConfig(r io.Reader) (*Config, error) {
 			return nil, fmt.Errorf("invalid config: empty configuration")
 		}
 	}
-	for name, option := range config.Options {
+	for _, name := range sortedNames(config.Options) {
+		option := config.Options[name]
 		switch option.Type {
-		case "string", "int", "float", "boolean":
+		case "string", "int", "float", "boolean", "duration", "size", "list", "secret":
 		case "":
 			// Missing type is valid in python.
 			option.Type = "string"
 		default:
 			return nil, fmt.Errorf("invalid config: option %q has unknown type %q", name, option.Type)
 		}
+		if option.ReplacedBy != "" {
+			if !option.Deprecated {
+				return nil, locateMetaError(data, fieldErrorf("options."+name+".replaced-by", ErrInvalidReplacedBy,
+					"invalid config: option %q is replaced by %q but is not deprecated", name, option.ReplacedBy))
+			}
+			if option.ReplacedBy == name {
+				return nil, locateMetaError(data, fieldErrorf("options."+name+".replaced-by", ErrInvalidReplacedBy,
+					"invalid config: option %q is replaced by itself", name))
+			}
+			if _, ok := config.Options[option.ReplacedBy]; !ok {
+				return nil, locateMetaError(data, fieldErrorf("options."+name+".replaced-by", ErrInvalidReplacedBy,
+					"invalid config: option %q is replaced by unknown option %q", name, option.ReplacedBy))
+			}
+		}
+		if len(option.Allowed) > 0 {
+			if option.Type != "string" && option.Type != "int" {
+				return nil, locateMetaError(data, fieldErrorf("options."+name+".allowed", ErrInvalidAllowedValues,
+					"invalid config: option %q of type %s cannot have allowed values", name, option.Type))
+			}
+			for i, v := range option.Allowed {
+				if option.Allowed[i], err = option.coerce(name, v); err != nil || v == nil {
+					return nil, locateMetaError(data, fieldErrorf(fmt.Sprintf("options.%s.allowed[%d]", name, i), ErrInvalidAllowedValues,
+						"invalid config: option %q allowed value %#v is not a valid %s", name, v, option.Type))
+				}
+			}
+		}
+		if option.Minimum != nil || option.Maximum != nil {
+			if option.Type != "int" && option.Type != "float" {
+				path := "options." + name + ".minimum"
+				if option.Minimum == nil {
+					path = "options." + name + ".maximum"
+				}
+				return nil, locateMetaError(data, fieldErrorf(path, ErrInvalidRange,
+					"invalid config: option %q of type %s cannot have a minimum or maximum", name, option.Type))
+			}
+			if option.Minimum != nil && option.Maximum != nil && *option.Minimum > *option.Maximum {
+				return nil, locateMetaError(data, fieldErrorf("options."+name+".maximum", ErrInvalidRange,
+					"invalid config: option %q maximum %v is less than its minimum %v", name, *option.Maximum, *option.Minimum))
+			}
+		}
+		if option.Pattern != "" {
+			if option.Type != "string" {
+				return nil, locateMetaError(data, fieldErrorf("options."+name+".pattern", ErrInvalidPattern,
+					"invalid config: option %q of type %s cannot have a pattern", name, option.Type))
+			}
+			if _, err := compileOptionPattern(option.Pattern); err != nil {
+				return nil, locateMetaError(data, fieldErrorf("options."+name+".pattern", ErrInvalidPattern,
+					"invalid config: option %q has invalid pattern: %v", name, err))
+			}
+		}
 		def := option.Default
+		if def != nil && option.Type == "secret" {
+			// A default would have every application share the
+			// same secret.
+			return nil, locateMetaError(data, fieldErrorf("options."+name+".default", ErrInvalidConfigDefault,
+				"invalid config default: option %q of type secret cannot have a default", name))
+		}
 		if def == "" && option.Type == "string" {
 			// Skip normal validation for compatibility with pyjuju.
 		} else if option.Default, err = option.validate(name, def); err != nil {
-			option.error(&err, name, def)
-			return nil, fmt.Errorf("invalid config default: %v", err)
+			return nil, locateMetaError(data, fieldErrorf("options."+name+".default", ErrInvalidConfigDefault,
+				"invalid config default: %v", err))
 		}
 		config.Options[name] = option
 	}
+	setOptionOrder(config, optionOrder(data))
 	return config, nil
 }
 
@@ -144,11 +570,20 @@ func (c *Config) option(name string) (Option, error) {
 }
 
 // DefaultSettings returns settings containing the default value of every
-// option in the config. Default values may be nil.
+// option in the config. Default values may be nil. Values have the Go
+// type of their option, as for ValidateSettings: int64 for int,
+// duration and size options, float64 for float options, bool for
+// boolean options, []string for list options and string otherwise. This
+// holds for configs built in code as well as those read by ReadConfig;
+// a default that does not suit its option is returned unchanged.
 func (c *Config) DefaultSettings() Settings {
 	out := make(Settings)
 	for name, option := range c.Options {
-		out[name] = option.Default
+		def := option.Default
+		if value, err := option.coerce(name, def); err == nil {
+			def = value
+		}
+		out[name] = def
 	}
 	return out
 }
@@ -157,18 +592,130 @@ func (c *Config) DefaultSettings() Settings {
 // for each value. It returns an error if the settings contain unknown keys
 // or invalid values.
 func (c *Config) ValidateSettings(settings Settings) (Settings, error) {
+	out, _, err := c.ValidateSettingsWithWarnings(settings)
+	return out, err
+}
+
+// OptionDeprecation describes a setting of a deprecated config option,
+// as reported by Config.ValidateSettingsWithWarnings.
+type OptionDeprecation struct {
+	// Option holds the name of the deprecated option.
+	Option string
+
+	// ReplacedBy holds the name of the option that replaces it, if
+	// any.
+	ReplacedBy string
+}
+
+// String returns a warning about the deprecation suitable for showing to
+// operators.
+func (d OptionDeprecation) String() string {
+	if d.ReplacedBy == "" {
+		return fmt.Sprintf("option %q is deprecated", d.Option)
+	}
+	return fmt.Sprintf("option %q is deprecated, use %q instead", d.Option, d.ReplacedBy)
+}
+
+// ValidateSettingsWithWarnings is like ValidateSettings, but also returns
+// a deprecation for each deprecated option given a value, sorted by
+// option name. Resetting a deprecated option, by giving it a nil value,
+// is not reported.
+func (c *Config) ValidateSettingsWithWarnings(settings Settings) (Settings, []OptionDeprecation, error) {
 	out := make(Settings)
-	for name, value := range settings {
-		if option, err := c.option(name); err != nil {
-			return nil, err
-		} else if value, err = option.validate(name, value); err != nil {
-			return nil, err
+	var deprecations []OptionDeprecation
+	for _, name := range sortedNames(settings) {
+		value := settings[name]
+		option, err := c.option(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if value, err = option.validate(name, value); err != nil {
+			return nil, nil, err
+		}
+		if option.Deprecated && value != nil {
+			deprecations = append(deprecations, OptionDeprecation{
+				Option:     name,
+				ReplacedBy: option.ReplacedBy,
+			})
 		}
 		out[name] = value
 	}
+	return out, deprecations, nil
+}
+
+// ResolveSettings is like ValidateSettings, but also replaces nil
+// values, which reset options, with the default value of the option,
+// coerced as by DefaultSettings, so that callers applying user-supplied
+// settings get the values that the charm will see.
+func (c *Config) ResolveSettings(settings Settings) (Settings, error) {
+	out, err := c.ValidateSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+	defaults := c.DefaultSettings()
+	for name, value := range out {
+		if value == nil {
+			out[name] = defaults[name]
+		}
+	}
 	return out, nil
 }
 
+// DefaultSource is the source reported by Config.MergeSettings for
+// settings that hold the default value of their option.
+const DefaultSource = -1
+
+// MergeSettings merges layers of settings, such as model defaults and
+// user overrides, over the defaults of the config. Each layer takes
+// precedence over the defaults and the layers before it; a nil value
+// in a layer leaves the value from lower layers in place. Every layer
+// is validated as by ValidateSettings. The returned settings hold the
+// effective value of every option, and the returned sources hold, for
+// every option, the index of the layer its value came from, or
+// DefaultSource.
+func (c *Config) MergeSettings(layers ...Settings) (Settings, map[string]int, error) {
+	out := c.DefaultSettings()
+	sources := make(map[string]int, len(out))
+	for name := range out {
+		sources[name] = DefaultSource
+	}
+	for i, layer := range layers {
+		settings, err := c.ValidateSettings(layer)
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "settings layer %d", i)
+		}
+		for name, value := range settings {
+			if value != nil {
+				out[name] = value
+				sources[name] = i
+			}
+		}
+	}
+	return out, sources, nil
+}
+
+// RedactedValue replaces the values of sensitive options in settings
+// returned by Config.RedactSettings.
+const RedactedValue = "<redacted>"
+
+// RedactSettings returns a copy of the supplied settings, suitable for
+// logs, status output and diagnostics, in which the value of every
+// sensitive option is replaced by RedactedValue. Nil values, which
+// reveal nothing, and settings of unknown options are copied unchanged.
+func (c *Config) RedactSettings(settings Settings) Settings {
+	if settings == nil {
+		return nil
+	}
+	out := make(Settings, len(settings))
+	for name, value := range settings {
+		if value != nil && c.Options[name].IsSensitive() {
+			value = RedactedValue
+		}
+		out[name] = value
+	}
+	return out
+}
+
 // FilterSettings returns the subset of the supplied settings that are valid.
 func (c *Config) FilterSettings(settings Settings) Settings {
 	out := make(Settings)
@@ -206,9 +753,14 @@ func (c *Config) ParseSettingsStrings(values map[string]string) (Settings, error
 // must be present in the map, and must point to a map in which every value
 // must have, or be a string parseable to, the correct type for the associated
 // config option. Empty strings and nil values are both interpreted as nil.
+//
+// As in the output of "juju config", a value may instead be given as a
+// map holding it under "value", alongside other keys such as "default"
+// and "description", which are ignored. A null "value" resets the option
+// to its default, and so is interpreted as nil.
 func (c *Config) ParseSettingsYAML(yamlData []byte, key string) (Settings, error) {
 	var allSettings map[string]Settings
-	if err := yaml.Unmarshal(yamlData, &allSettings); err != nil {
+	if err := yaml.Unmarshal(normalizeYAMLInput(yamlData), &allSettings); err != nil {
 		return nil, fmt.Errorf("cannot parse settings data: %v", err)
 	}
 	settings, ok := allSettings[key]
@@ -221,6 +773,11 @@ func (c *Config) ParseSettingsYAML(yamlData []byte, key string) (Settings, error
 		if err != nil {
 			return nil, err
 		}
+		if m, ok := value.(map[interface{}]interface{}); ok {
+			if v, ok := m["value"]; ok {
+				value = v
+			}
+		}
 		// Accept string values for compatibility with python.
 		if str, ok := value.(string); ok {
 			if value, err = option.parse(name, str); err != nil {
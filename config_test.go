@@ -5,8 +5,10 @@ package charm_test
 
 import (
 	"bytes"
+	stderrors "errors"
 	"fmt"
 	"strings"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -102,6 +104,29 @@ func (s *ConfigSuite) TestDefaultSettings(c *gc.C) {
 	})
 }
 
+func (s *ConfigSuite) TestDefaultSettingsCoerced(c *gc.C) {
+	cfg := &charm.Config{Options: map[string]charm.Option{
+		"workers": {Type: "int", Default: 4},
+		"ratio":   {Type: "float", Default: 1},
+		"timeout": {Type: "duration", Default: "1m"},
+		"debug":   {Type: "boolean", Default: "true"},
+		"domains": {Type: "list", Default: []interface{}{"a.com", "b.com"}},
+		"name":    {Type: "string", Default: "x"},
+		"unset":   {Type: "int"},
+		"invalid": {Type: "int", Default: "many"},
+	}}
+	c.Assert(cfg.DefaultSettings(), jc.DeepEquals, charm.Settings{
+		"workers": int64(4),
+		"ratio":   float64(1),
+		"timeout": int64(time.Minute),
+		"debug":   true,
+		"domains": []string{"a.com", "b.com"},
+		"name":    "x",
+		"unset":   nil,
+		"invalid": "many",
+	})
+}
+
 func (s *ConfigSuite) TestFilterSettings(c *gc.C) {
 	settings := s.config.FilterSettings(charm.Settings{
 		"title":              "something valid",
@@ -197,6 +222,36 @@ func (s *ConfigSuite) TestValidateSettings(c *gc.C) {
 	}
 }
 
+func (s *ConfigSuite) TestResolveSettings(c *gc.C) {
+	settings, err := s.config.ResolveSettings(charm.Settings{
+		"title":       nil,
+		"outlook":     nil,
+		"skill-level": "42",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{
+		"title":       "My Title",
+		"outlook":     nil,
+		"skill-level": int64(42),
+	})
+
+	_, err = s.config.ResolveSettings(charm.Settings{"ping": nil})
+	c.Assert(err, gc.ErrorMatches, `unknown option "ping"`)
+
+	// Defaults set in code are coerced like values read from YAML.
+	config := &charm.Config{Options: map[string]charm.Option{
+		"workers": {Type: "int", Default: 4},
+		"timeout": {Type: "duration", Default: "1m"},
+	}}
+	settings, err = config.ResolveSettings(charm.Settings{
+		"workers": nil,
+		"timeout": nil,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, config.DefaultSettings())
+	c.Assert(settings["workers"], gc.Equals, int64(4))
+}
+
 var settingsWithNils = charm.Settings{
 	"outlook":            nil,
 	"skill-level":        nil,
@@ -326,6 +381,40 @@ func (s *ConfigSuite) TestParseSettingsYAML(c *gc.C) {
             reticulate-splines: y`,
 		key:    "blah",
 		expect: settingsWithValues,
+	}, {
+		info: "juju config style values are valid",
+		yaml: `blah:
+            outlook:
+              default: null
+              description: No default outlook.
+              source: user
+              type: string
+              value: whatever
+            skill-level:
+              value: 123
+            agility-ratio:
+              value: "2.22"
+            reticulate-splines:
+              value: y`,
+		key:    "blah",
+		expect: settingsWithValues,
+	}, {
+		info: "juju config style null values are reset",
+		yaml: `blah:
+            outlook:
+              value: null
+            skill-level:
+              source: default
+              value: null
+            agility-ratio: {value: ~}
+            reticulate-splines: {value: null}`,
+		key:    "blah",
+		expect: settingsWithNils,
+	}, {
+		info: "juju config style values are checked",
+		yaml: "blah:\n  skill-level:\n    value: cheese",
+		key:  "blah",
+		err:  `option "skill-level" expected int, got "cheese"`,
 	}} {
 		c.Logf("test %d: %s", i, test.info)
 		result, err := s.config.ParseSettingsYAML([]byte(test.yaml), test.key)
@@ -411,6 +500,14 @@ func (s *ConfigSuite) TestConfigWithNoOptions(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 }
 
+func (s *ConfigSuite) TestReadConfigWindowsLineEndings(c *gc.C) {
+	config, err := charm.ReadConfig(strings.NewReader(
+		"\xef\xbb\xbfoptions:\r\n  title:\r\n    type: string\r\n    default: |\r\n      My\r\n      Title\r\n",
+	))
+	c.Assert(err, gc.IsNil)
+	c.Assert(config.Options["title"].Default, gc.Equals, "My\nTitle\n")
+}
+
 func (s *ConfigSuite) TestDefaultType(c *gc.C) {
 	assertDefault := func(type_ string, value string, expected interface{}) {
 		config := fmt.Sprintf(`options: {x: {type: %s, default: %s}}`, type_, value)
@@ -423,6 +520,7 @@ func (s *ConfigSuite) TestDefaultType(c *gc.C) {
 	assertDefault("string", "golden grahams", "golden grahams")
 	assertDefault("string", `""`, "")
 	assertDefault("float", "2.211", 2.211)
+	assertDefault("float", "2", 2.0)
 	assertDefault("int", "99", int64(99))
 
 	assertTypeError := func(type_, str, value string) {
@@ -438,6 +536,24 @@ func (s *ConfigSuite) TestDefaultType(c *gc.C) {
 	assertTypeError("int", "true", "true")
 }
 
+func (s *ConfigSuite) TestDefaultFieldError(c *gc.C) {
+	_, err := charm.ReadConfig(strings.NewReader(`
+options:
+  title:
+    type: string
+    default: My Title
+  skill-level:
+    type: int
+    default: high
+`))
+	c.Assert(err, gc.ErrorMatches, `invalid config default: option "skill-level" expected int, got "high"`)
+	c.Assert(stderrors.Is(err, charm.ErrInvalidConfigDefault), jc.IsTrue)
+	var fieldErr *charm.FieldError
+	c.Assert(stderrors.As(err, &fieldErr), jc.IsTrue)
+	c.Assert(fieldErr.Path, gc.Equals, "options.skill-level.default")
+	c.Assert(fieldErr.Position, gc.Equals, charm.Position{Line: 8, Column: 5})
+}
+
 // When an empty config is supplied an error should be returned
 func (s *ConfigSuite) TestEmptyConfigReturnsError(c *gc.C) {
 	config := ""
@@ -470,6 +586,428 @@ options:
 	c.Assert(newCfg, jc.DeepEquals, cfg)
 }
 
+func (s *ConfigSuite) TestYAMLMarshalOrder(c *gc.C) {
+	cfg, err := charm.ReadConfig(strings.NewReader(`
+options:
+  zebra:
+    type: int
+    default: 0
+  apple:
+    type: string
+    description: An apple.
+    default: ""
+  removed:
+    type: boolean
+  mango:
+    description: No type or default.
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	delete(cfg.Options, "removed")
+	cfg.Options["banana"] = charm.Option{Type: "float", Default: 1.5}
+	cfg.Options["avocado"] = charm.Option{Type: "boolean", Default: false}
+
+	data, err := yaml.Marshal(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, `
+options:
+  zebra:
+    type: int
+    default: 0
+  apple:
+    type: string
+    description: An apple.
+    default: ""
+  mango:
+    type: string
+    description: No type or default.
+  avocado:
+    type: boolean
+    default: false
+  banana:
+    type: float
+    default: 1.5
+`[1:])
+
+	cfg1, err := charm.ReadConfig(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg1, jc.DeepEquals, cfg)
+	data1, err := yaml.Marshal(cfg1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data1), gc.Equals, string(data))
+
+	data, err = yaml.Marshal(charm.NewConfig())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "options: {}\n")
+
+	// The order is not part of the config, which still compares
+	// equal to one built in code.
+	built := &charm.Config{Options: map[string]charm.Option{}}
+	for name, option := range cfg.Options {
+		built.Options[name] = option
+	}
+	c.Assert(cfg, jc.DeepEquals, built)
+	data, err = yaml.Marshal(built)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Matches, "options:\n  apple:\n(.|\n)*  zebra:\n(.|\n)*")
+}
+
+func (s *ConfigSuite) TestDeprecatedOptions(c *gc.C) {
+	cfg, err := charm.ReadConfig(strings.NewReader(`
+options:
+  port:
+    type: int
+    default: 80
+  http-port:
+    type: int
+    deprecated: true
+    replaced-by: port
+  legacy-mode:
+    type: boolean
+    deprecated: true
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Options["http-port"], jc.DeepEquals, charm.Option{
+		Type:       "int",
+		Deprecated: true,
+		ReplacedBy: "port",
+	})
+
+	settings, deprecations, err := cfg.ValidateSettingsWithWarnings(charm.Settings{
+		"port":        8080,
+		"http-port":   "8080",
+		"legacy-mode": nil,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{
+		"port":        int64(8080),
+		"http-port":   int64(8080),
+		"legacy-mode": nil,
+	})
+	c.Assert(deprecations, jc.DeepEquals, []charm.OptionDeprecation{{
+		Option:     "http-port",
+		ReplacedBy: "port",
+	}})
+	c.Assert(deprecations[0].String(), gc.Equals, `option "http-port" is deprecated, use "port" instead`)
+
+	_, deprecations, err = cfg.ValidateSettingsWithWarnings(charm.Settings{"legacy-mode": true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(deprecations, gc.HasLen, 1)
+	c.Assert(deprecations[0].String(), gc.Equals, `option "legacy-mode" is deprecated`)
+
+	data, err := yaml.Marshal(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg1, err := charm.ReadConfig(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg1, jc.DeepEquals, cfg)
+}
+
+func (s *ConfigSuite) TestInvalidReplacedBy(c *gc.C) {
+	for i, test := range []struct {
+		yaml string
+		err  string
+	}{{
+		yaml: "options:\n  a:\n    replaced-by: b\n  b: {}\n",
+		err:  `invalid config: option "a" is replaced by "b" but is not deprecated`,
+	}, {
+		yaml: "options:\n  a:\n    deprecated: true\n    replaced-by: b\n",
+		err:  `invalid config: option "a" is replaced by unknown option "b"`,
+	}, {
+		yaml: "options:\n  a:\n    deprecated: true\n    replaced-by: a\n",
+		err:  `invalid config: option "a" is replaced by itself`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := charm.ReadConfig(strings.NewReader(test.yaml))
+		c.Check(err, gc.ErrorMatches, test.err)
+		fieldErr, ok := err.(*charm.FieldError)
+		c.Assert(ok, jc.IsTrue)
+		c.Check(fieldErr.Path, gc.Equals, "options.a.replaced-by")
+		c.Check(stderrors.Is(err, charm.ErrInvalidReplacedBy), jc.IsTrue)
+	}
+}
+
+func (s *ConfigSuite) TestAllowedValues(c *gc.C) {
+	cfg, err := charm.ReadConfig(strings.NewReader(`
+options:
+  isolation-level:
+    type: string
+    default: read-committed
+    allowed: [read-committed, repeatable-read, serializable]
+  workers:
+    type: int
+    allowed: [1, 2, 4, "8"]
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Options["workers"].Allowed, jc.DeepEquals, []interface{}{int64(1), int64(2), int64(4), int64(8)})
+
+	settings, err := cfg.ValidateSettings(charm.Settings{
+		"isolation-level": "serializable",
+		"workers":         8,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{
+		"isolation-level": "serializable",
+		"workers":         int64(8),
+	})
+	_, err = cfg.ValidateSettings(charm.Settings{"isolation-level": "dirty"})
+	c.Assert(err, gc.ErrorMatches, `option "isolation-level" expected one of "read-committed", "repeatable-read", "serializable", got "dirty"`)
+	_, err = cfg.ValidateSettings(charm.Settings{"workers": 3})
+	c.Assert(err, gc.ErrorMatches, `option "workers" expected one of 1, 2, 4, 8, got 3`)
+	_, err = cfg.ParseSettingsStrings(map[string]string{"workers": "3"})
+	c.Assert(err, gc.ErrorMatches, `option "workers" expected one of 1, 2, 4, 8, got 3`)
+	_, err = cfg.ParseSettingsYAML([]byte("app:\n  isolation-level: dirty\n"), "app")
+	c.Assert(err, gc.ErrorMatches, `option "isolation-level" expected one of .*, got "dirty"`)
+
+	data, err := yaml.Marshal(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg1, err := charm.ReadConfig(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg1, jc.DeepEquals, cfg)
+}
+
+func (s *ConfigSuite) TestAllowedValuesSetInCode(c *gc.C) {
+	cfg := &charm.Config{Options: map[string]charm.Option{
+		"workers": {Type: "int", Allowed: []interface{}{1, 2}},
+		"tags":    {Type: "list", Allowed: []interface{}{[]string{"a"}, "a,b"}},
+	}}
+	settings, err := cfg.ValidateSettings(charm.Settings{"workers": 2})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{"workers": int64(2)})
+	_, err = cfg.ValidateSettings(charm.Settings{"workers": 3})
+	c.Assert(err, gc.ErrorMatches, `option "workers" expected one of 1, 2, got 3`)
+
+	settings, err = cfg.ValidateSettings(charm.Settings{"tags": []interface{}{"a"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{"tags": []string{"a"}})
+}
+
+func (s *ConfigSuite) TestInvalidAllowedValues(c *gc.C) {
+	for i, test := range []struct {
+		yaml   string
+		path   string
+		reason error
+		err    string
+	}{{
+		yaml:   "options:\n  a:\n    type: boolean\n    allowed: [true]\n",
+		path:   "options.a.allowed",
+		reason: charm.ErrInvalidAllowedValues,
+		err:    `invalid config: option "a" of type boolean cannot have allowed values`,
+	}, {
+		yaml:   "options:\n  a:\n    type: int\n    allowed: [1, two]\n",
+		path:   "options.a.allowed[1]",
+		reason: charm.ErrInvalidAllowedValues,
+		err:    `invalid config: option "a" allowed value "two" is not a valid int`,
+	}, {
+		yaml:   "options:\n  a:\n    allowed: [x, null]\n",
+		path:   "options.a.allowed[1]",
+		reason: charm.ErrInvalidAllowedValues,
+		err:    `invalid config: option "a" allowed value <nil> is not a valid string`,
+	}, {
+		yaml:   "options:\n  a:\n    default: z\n    allowed: [v, w]\n",
+		path:   "options.a.default",
+		reason: charm.ErrInvalidConfigDefault,
+		err:    `invalid config default: option "a" expected one of "v", "w", got "z"`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := charm.ReadConfig(strings.NewReader(test.yaml))
+		c.Check(err, gc.ErrorMatches, test.err)
+		fieldErr, ok := err.(*charm.FieldError)
+		c.Assert(ok, jc.IsTrue)
+		c.Check(fieldErr.Path, gc.Equals, test.path)
+		c.Check(stderrors.Is(err, test.reason), jc.IsTrue)
+	}
+}
+
+func (s *ConfigSuite) TestRangeConstraints(c *gc.C) {
+	cfg, err := charm.ReadConfig(strings.NewReader(`
+options:
+  workers:
+    type: int
+    default: 4
+    minimum: 1
+    maximum: 64
+  ratio:
+    type: float
+    maximum: 0.5
+  port:
+    type: int
+    minimum: 1024
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*cfg.Options["workers"].Minimum, gc.Equals, 1.0)
+	c.Assert(*cfg.Options["workers"].Maximum, gc.Equals, 64.0)
+
+	_, err = cfg.ValidateSettings(charm.Settings{"workers": 1, "ratio": 0.5, "port": 8080})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = cfg.ValidateSettings(charm.Settings{"workers": 65})
+	c.Assert(err, gc.ErrorMatches, `option "workers" expected a value between 1 and 64, got 65`)
+	_, err = cfg.ValidateSettings(charm.Settings{"ratio": 0.75})
+	c.Assert(err, gc.ErrorMatches, `option "ratio" expected a value of at most 0.5, got 0.75`)
+	_, err = cfg.ParseSettingsStrings(map[string]string{"port": "80"})
+	c.Assert(err, gc.ErrorMatches, `option "port" expected a value of at least 1024, got 80`)
+
+	data, err := yaml.Marshal(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg1, err := charm.ReadConfig(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg1, jc.DeepEquals, cfg)
+}
+
+func (s *ConfigSuite) TestInvalidRangeConstraints(c *gc.C) {
+	for i, test := range []struct {
+		yaml   string
+		path   string
+		reason error
+		err    string
+	}{{
+		yaml:   "options:\n  a:\n    type: string\n    maximum: 3\n",
+		path:   "options.a.maximum",
+		reason: charm.ErrInvalidRange,
+		err:    `invalid config: option "a" of type string cannot have a minimum or maximum`,
+	}, {
+		yaml:   "options:\n  a:\n    type: float\n    minimum: 2\n    maximum: 1.5\n",
+		path:   "options.a.maximum",
+		reason: charm.ErrInvalidRange,
+		err:    `invalid config: option "a" maximum 1.5 is less than its minimum 2`,
+	}, {
+		yaml:   "options:\n  a:\n    type: int\n    default: 0\n    minimum: 1\n",
+		path:   "options.a.default",
+		reason: charm.ErrInvalidConfigDefault,
+		err:    `invalid config default: option "a" expected a value of at least 1, got 0`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := charm.ReadConfig(strings.NewReader(test.yaml))
+		c.Check(err, gc.ErrorMatches, test.err)
+		fieldErr, ok := err.(*charm.FieldError)
+		c.Assert(ok, jc.IsTrue)
+		c.Check(fieldErr.Path, gc.Equals, test.path)
+		c.Check(stderrors.Is(err, test.reason), jc.IsTrue)
+	}
+}
+
+func (s *ConfigSuite) TestPatternConstraint(c *gc.C) {
+	cfg, err := charm.ReadConfig(strings.NewReader(`
+options:
+  hostname:
+    type: string
+    default: db0
+    pattern: '[a-z][a-z0-9-]*'
+  alternation:
+    pattern: 'ab|cd'
+`))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = cfg.ValidateSettings(charm.Settings{"hostname": "db-1", "alternation": "cd"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = cfg.ValidateSettings(charm.Settings{"hostname": "1db"})
+	c.Assert(err, gc.ErrorMatches, `option "hostname" expected a value matching "\[a-z\]\[a-z0-9-\]\*", got "1db"`)
+	_, err = cfg.ParseSettingsStrings(map[string]string{"hostname": "db_1"})
+	c.Assert(err, gc.ErrorMatches, `option "hostname" expected a value matching .*, got "db_1"`)
+	_, err = cfg.ValidateSettings(charm.Settings{"alternation": "abcd"})
+	c.Assert(err, gc.ErrorMatches, `option "alternation" expected a value matching "ab\|cd", got "abcd"`)
+
+	_, err = charm.ReadConfig(strings.NewReader("options:\n  a:\n    default: UPPER\n    pattern: '[a-z]+'\n"))
+	c.Assert(err, gc.ErrorMatches, `invalid config default: option "a" expected a value matching "\[a-z\]\+", got "UPPER"`)
+	c.Assert(stderrors.Is(err, charm.ErrInvalidConfigDefault), jc.IsTrue)
+
+	for i, test := range []struct {
+		yaml string
+		err  string
+	}{{
+		yaml: "options:\n  a:\n    type: int\n    pattern: '[0-9]+'\n",
+		err:  `invalid config: option "a" of type int cannot have a pattern`,
+	}, {
+		yaml: "options:\n  a:\n    pattern: '[a-z'\n",
+		err:  `invalid config: option "a" has invalid pattern: error parsing regexp: .*`,
+	}, {
+		yaml: "options:\n  a:\n    pattern: 'a)|(b'\n",
+		err:  `invalid config: option "a" has invalid pattern: error parsing regexp: .*`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := charm.ReadConfig(strings.NewReader(test.yaml))
+		c.Check(err, gc.ErrorMatches, test.err)
+		fieldErr, ok := err.(*charm.FieldError)
+		c.Assert(ok, jc.IsTrue)
+		c.Check(fieldErr.Path, gc.Equals, "options.a.pattern")
+		c.Check(stderrors.Is(err, charm.ErrInvalidPattern), jc.IsTrue)
+	}
+}
+
+func (s *ConfigSuite) TestRedactSettings(c *gc.C) {
+	cfg, err := charm.ReadConfig(strings.NewReader(`
+options:
+  username:
+    type: string
+  password:
+    type: string
+    sensitive: true
+  api-key:
+    type: secret
+  tls-key:
+    type: string
+    sensitive: true
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Options["password"].IsSensitive(), jc.IsTrue)
+	c.Assert(cfg.Options["api-key"].IsSensitive(), jc.IsTrue)
+	c.Assert(cfg.Options["username"].IsSensitive(), jc.IsFalse)
+
+	settings := charm.Settings{
+		"username": "admin",
+		"password": "hunter2",
+		"api-key":  "secret:9m4e2mr0ui3e8a215n4g",
+		"tls-key":  nil,
+		"unknown":  "value",
+	}
+	c.Assert(cfg.RedactSettings(settings), jc.DeepEquals, charm.Settings{
+		"username": "admin",
+		"password": charm.RedactedValue,
+		"api-key":  charm.RedactedValue,
+		"tls-key":  nil,
+		"unknown":  "value",
+	})
+	c.Assert(settings["password"], gc.Equals, "hunter2")
+	c.Assert(cfg.RedactSettings(nil), gc.IsNil)
+}
+
+func (s *ConfigSuite) TestMergeSettings(c *gc.C) {
+	modelDefaults := map[string]interface{}{
+		"title":       "Model Title",
+		"skill-level": "3",
+	}
+	userSettings := charm.Settings{
+		"title":    nil,
+		"outlook":  "sunny",
+		"username": "root",
+	}
+	settings, sources, err := s.config.MergeSettings(modelDefaults, userSettings)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{
+		"title":              "Model Title",
+		"subtitle":           "",
+		"username":           "root",
+		"outlook":            "sunny",
+		"skill-level":        int64(3),
+		"agility-ratio":      nil,
+		"reticulate-splines": nil,
+	})
+	c.Assert(sources, jc.DeepEquals, map[string]int{
+		"title":              0,
+		"subtitle":           charm.DefaultSource,
+		"username":           1,
+		"outlook":            1,
+		"skill-level":        0,
+		"agility-ratio":      charm.DefaultSource,
+		"reticulate-splines": charm.DefaultSource,
+	})
+
+	settings, _, err = s.config.MergeSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, s.config.DefaultSettings())
+
+	_, _, err = s.config.MergeSettings(modelDefaults, charm.Settings{"skill-level": "lots"})
+	c.Assert(err, gc.ErrorMatches, `settings layer 1: option "skill-level" expected int, got "lots"`)
+}
+
 func (s *ConfigSuite) TestErrorOnInvalidOptionTypes(c *gc.C) {
 	cfg := charm.Config{
 		Options: map[string]charm.Option{"testOption": charm.Option{Type: "invalid type"}},
@@ -480,3 +1018,120 @@ func (s *ConfigSuite) TestErrorOnInvalidOptionTypes(c *gc.C) {
 	_, err = cfg.ParseSettingsYAML([]byte("testKey:\n  testOption: \"some string value\""), "testKey")
 	c.Assert(err, gc.ErrorMatches, "option \"testOption\" has unknown type \"invalid type\"")
 }
+
+func (s *ConfigSuite) TestDurationAndSizeOptions(c *gc.C) {
+	cfg, err := charm.ReadConfig(strings.NewReader(`
+options:
+  timeout:
+    type: duration
+    default: 1m30s
+  cache-size:
+    type: size
+    default: 512M
+  max-upload:
+    type: size
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Options["timeout"].Default, gc.Equals, int64(90*time.Second))
+	c.Assert(cfg.Options["cache-size"].Default, gc.Equals, int64(512<<20))
+
+	settings, err := cfg.ValidateSettings(charm.Settings{
+		"timeout":    "250ms",
+		"cache-size": "2G",
+		"max-upload": 1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{
+		"timeout":    int64(250 * time.Millisecond),
+		"cache-size": int64(2 << 30),
+		"max-upload": int64(1024),
+	})
+
+	settings, err = cfg.ParseSettingsStrings(map[string]string{
+		"timeout":    "2h",
+		"cache-size": "1.5KiB",
+		"max-upload": "10mb",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{
+		"timeout":    int64(2 * time.Hour),
+		"cache-size": int64(1536),
+		"max-upload": int64(10 << 20),
+	})
+
+	_, err = cfg.ValidateSettings(charm.Settings{"timeout": "soon"})
+	c.Assert(err, gc.ErrorMatches, `option "timeout" expected duration, got "soon"`)
+	_, err = cfg.ParseSettingsStrings(map[string]string{"cache-size": "12X"})
+	c.Assert(err, gc.ErrorMatches, `option "cache-size" expected size, got "12X"`)
+}
+
+func (s *ConfigSuite) TestListAndSecretOptions(c *gc.C) {
+	cfg, err := charm.ReadConfig(strings.NewReader(`
+options:
+  domains:
+    type: list
+    default: [example.com, example.org]
+  ciphers:
+    type: list
+  password:
+    type: secret
+    description: The administrator password.
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Options["domains"].Default, jc.DeepEquals, []string{"example.com", "example.org"})
+	c.Assert(cfg.Options["ciphers"].Default, gc.IsNil)
+	c.Assert(cfg.Options["password"].Default, gc.IsNil)
+
+	settings, err := cfg.ValidateSettings(charm.Settings{
+		"domains":  []interface{}{"example.net"},
+		"password": "secret:9m4e2mr0ui3e8a215n4g",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{
+		"domains":  []string{"example.net"},
+		"password": "secret:9m4e2mr0ui3e8a215n4g",
+	})
+
+	settings, err = cfg.ParseSettingsStrings(map[string]string{
+		"domains":  "a.com, b.com,",
+		"ciphers":  "[AES128, 'AES256,GCM']",
+		"password": "secret://e6b2e2a4-1a31-4d3a-8a1e-bd0a9a4b5e4d/9m4e2mr0ui3e8a215n4g",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{
+		"domains":  []string{"a.com", "b.com"},
+		"ciphers":  []string{"AES128", "AES256,GCM"},
+		"password": "secret://e6b2e2a4-1a31-4d3a-8a1e-bd0a9a4b5e4d/9m4e2mr0ui3e8a215n4g",
+	})
+
+	_, err = cfg.ValidateSettings(charm.Settings{"domains": []interface{}{1}})
+	c.Assert(err, gc.ErrorMatches, `option "domains" expected list, got .*`)
+	_, err = cfg.ValidateSettings(charm.Settings{"password": "hunter2"})
+	c.Assert(err, gc.ErrorMatches, `option "password" expected secret, got "hunter2"`)
+
+	data, err := yaml.Marshal(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg1, err := charm.ReadConfig(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg1, jc.DeepEquals, cfg)
+}
+
+func (s *ConfigSuite) TestSecretDefault(c *gc.C) {
+	_, err := charm.ReadConfig(strings.NewReader(`
+options:
+  password:
+    type: secret
+    default: secret:9m4e2mr0ui3e8a215n4g
+`))
+	c.Assert(err, gc.ErrorMatches, `invalid config default: option "password" of type secret cannot have a default`)
+}
+
+func (s *ConfigSuite) TestInvalidDurationDefault(c *gc.C) {
+	_, err := charm.ReadConfig(strings.NewReader(`
+options:
+  timeout:
+    type: duration
+    default: forever
+`))
+	c.Assert(err, gc.ErrorMatches, `invalid config default: option "timeout" expected duration, got "forever"`)
+}
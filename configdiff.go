@@ -0,0 +1,139 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/charm/v8/resource"
+)
+
+// OptionChange describes a config option declared by two revisions of
+// a charm.
+type OptionChange struct {
+	// Name holds the name of the option.
+	Name string
+
+	// Old holds the option as declared by the old revision.
+	Old Option
+
+	// New holds the option as declared by the new revision.
+	New Option
+}
+
+// ConfigDiff describes the differences between the config options of
+// two revisions of a charm, as returned by DiffConfig. All lists are
+// sorted by option name.
+type ConfigDiff struct {
+	// Added holds the names of the options only the new revision
+	// declares.
+	Added []string
+
+	// Removed holds the names of the options only the old revision
+	// declares. Settings of these options are dropped on upgrade.
+	Removed []string
+
+	// TypeChanged holds the options whose type differs. Settings of
+	// these options are reinterpreted, or rejected, on upgrade.
+	TypeChanged []OptionChange
+
+	// DefaultChanged holds the options whose default value differs.
+	// Applications that do not set these options change behaviour on
+	// upgrade.
+	DefaultChanged []OptionChange
+}
+
+// IsEmpty reports whether the diff holds no differences.
+func (d ConfigDiff) IsEmpty() bool {
+	return len(d.Added) == 0 &&
+		len(d.Removed) == 0 &&
+		len(d.TypeChanged) == 0 &&
+		len(d.DefaultChanged) == 0
+}
+
+// DiffConfig returns the differences between the config options of an
+// old and a new revision of a charm, so that upgrades can warn about
+// settings that would be dropped or reinterpreted. A nil config is
+// taken to have no options. Changes to descriptions, deprecations and
+// constraints on values are not reported.
+func DiffConfig(old, new *Config) ConfigDiff {
+	oldOptions := configOptions(old)
+	newOptions := configOptions(new)
+	var diff ConfigDiff
+	for _, name := range sortedNames(oldOptions) {
+		if _, ok := newOptions[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+	for _, name := range sortedNames(newOptions) {
+		newOption := newOptions[name]
+		oldOption, ok := oldOptions[name]
+		if !ok {
+			diff.Added = append(diff.Added, name)
+			continue
+		}
+		change := OptionChange{
+			Name: name,
+			Old:  oldOption,
+			New:  newOption,
+		}
+		if oldOption.Type != newOption.Type {
+			diff.TypeChanged = append(diff.TypeChanged, change)
+		}
+		if !reflect.DeepEqual(oldOption.Default, newOption.Default) {
+			diff.DefaultChanged = append(diff.DefaultChanged, change)
+		}
+	}
+	return diff
+}
+
+func configOptions(config *Config) map[string]Option {
+	if config == nil {
+		return nil
+	}
+	return config.Options
+}
+
+// Hash returns a hex-encoded SHA-256 digest of the names, types and
+// default values of the options of the config, so that agents can
+// detect whether an upgrade changes the config schema without keeping
+// the old config around. Configs that DiffConfig finds no differences
+// between have the same hash; descriptions, deprecations and
+// constraints on values do not affect it. Defaults are hashed after
+// coercion to their option's type, so a default of 1 for an int option
+// hashes the same whether it was read by ReadConfig or set in code.
+func (c *Config) Hash() string {
+	h := sha256.New()
+	c.writeHash(h)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HashUsing is like Hash but computes the digest with the given
+// algorithm, which must be registered with the resource package, and
+// returns it along with the algorithm.
+func (c *Config) HashUsing(algorithm resource.HashAlgorithm) (resource.QualifiedFingerprint, error) {
+	h, err := resource.NewFingerprintHashUsing(algorithm)
+	if err != nil {
+		return resource.QualifiedFingerprint{}, errors.Trace(err)
+	}
+	c.writeHash(h)
+	return resource.QualifiedFingerprint{
+		Algorithm:   algorithm,
+		Fingerprint: h.Fingerprint(),
+	}, nil
+}
+
+// writeHash writes the parts of the config that Hash covers to w.
+func (c *Config) writeHash(w io.Writer) {
+	defaults := c.DefaultSettings()
+	for _, name := range sortedNames(c.Options) {
+		fmt.Fprintf(w, "%q %q %#v\n", name, c.Options[name].Type, defaults[name])
+	}
+}
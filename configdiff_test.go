@@ -0,0 +1,128 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+	"github.com/juju/charm/v8/resource"
+)
+
+type ConfigDiffSuite struct{}
+
+var _ = gc.Suite(&ConfigDiffSuite{})
+
+func (s *ConfigDiffSuite) TestDiffConfig(c *gc.C) {
+	old, err := charm.ReadConfig(strings.NewReader(`
+options:
+  port:
+    type: int
+    default: 80
+  workers:
+    type: string
+    default: "4"
+  debug:
+    type: boolean
+    default: false
+  motd:
+    type: string
+    description: Old description.
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	new, err := charm.ReadConfig(strings.NewReader(`
+options:
+  port:
+    type: int
+    default: 8080
+  workers:
+    type: int
+    default: 4
+  motd:
+    type: string
+    description: New description.
+  tls:
+    type: boolean
+`))
+	c.Assert(err, jc.ErrorIsNil)
+
+	diff := charm.DiffConfig(old, new)
+	c.Assert(diff.IsEmpty(), jc.IsFalse)
+	c.Assert(diff.Added, jc.DeepEquals, []string{"tls"})
+	c.Assert(diff.Removed, jc.DeepEquals, []string{"debug"})
+	c.Assert(diff.TypeChanged, jc.DeepEquals, []charm.OptionChange{{
+		Name: "workers",
+		Old:  old.Options["workers"],
+		New:  new.Options["workers"],
+	}})
+	c.Assert(diff.DefaultChanged, jc.DeepEquals, []charm.OptionChange{{
+		Name: "port",
+		Old:  old.Options["port"],
+		New:  new.Options["port"],
+	}, {
+		Name: "workers",
+		Old:  old.Options["workers"],
+		New:  new.Options["workers"],
+	}})
+
+	c.Assert(charm.DiffConfig(old, old).IsEmpty(), jc.IsTrue)
+	c.Assert(charm.DiffConfig(nil, nil).IsEmpty(), jc.IsTrue)
+	c.Assert(charm.DiffConfig(nil, new).Added, jc.DeepEquals, []string{"motd", "port", "tls", "workers"})
+	c.Assert(charm.DiffConfig(old, nil).Removed, jc.DeepEquals, []string{"debug", "motd", "port", "workers"})
+}
+
+func (s *ConfigDiffSuite) TestHash(c *gc.C) {
+	read := func(yaml string) *charm.Config {
+		cfg, err := charm.ReadConfig(strings.NewReader(yaml))
+		c.Assert(err, jc.ErrorIsNil)
+		return cfg
+	}
+	cfg := read(`
+options:
+  port:
+    type: int
+    default: 80
+    description: The port.
+  name:
+    type: string
+`)
+	hash := cfg.Hash()
+	c.Assert(hash, gc.HasLen, 64)
+	c.Assert(read("options:\n  name: {}\n  port: {type: int, default: 80}\n").Hash(), gc.Equals, hash)
+
+	built := &charm.Config{Options: map[string]charm.Option{
+		"port": {Type: "int", Default: 80},
+		"name": {Type: "string"},
+	}}
+	c.Assert(built.Hash(), gc.Equals, hash)
+
+	c.Assert(read("options:\n  name: {}\n  port: {type: int, default: 8080}\n").Hash(), gc.Not(gc.Equals), hash)
+	c.Assert(read("options:\n  name: {}\n  port: {type: float, default: 80}\n").Hash(), gc.Not(gc.Equals), hash)
+	c.Assert(read("options:\n  name: {default: \"\"}\n  port: {type: int, default: 80}\n").Hash(), gc.Not(gc.Equals), hash)
+	c.Assert(read("options:\n  port: {type: int, default: 80}\n").Hash(), gc.Not(gc.Equals), hash)
+	c.Assert(charm.NewConfig().Hash(), gc.Equals, read("options: {}\n").Hash())
+}
+
+func (s *ConfigDiffSuite) TestHashUsing(c *gc.C) {
+	cfg := &charm.Config{Options: map[string]charm.Option{
+		"port": {Type: "int", Default: 80},
+	}}
+	fp, err := cfg.HashUsing(resource.SHA256)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fp.Algorithm, gc.Equals, resource.SHA256)
+	c.Assert(fp.Hex(), gc.Equals, cfg.Hash())
+	c.Assert(fp.String(), gc.Equals, "sha256:"+cfg.Hash())
+
+	fp, err = cfg.HashUsing(resource.SHA512)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fp.Algorithm, gc.Equals, resource.SHA512)
+	c.Assert(fp.Bytes(), gc.HasLen, 64)
+
+	_, err = cfg.HashUsing("md5")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"bytes"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// ContentPolicy describes files that may not be included in a charm or
+// bundle archive, for instance by a charm store screening uploads. It
+// is applied by ArchiveToWithOptions when set in ArchiveOptions, and by
+// CharmArchive.CheckContent.
+type ContentPolicy struct {
+	// DeniedPatterns holds shell patterns, as used by path.Match, of
+	// the files that are denied. A pattern containing a slash is
+	// matched against the slash-separated path of the file relative
+	// to the archive root; other patterns are matched against the
+	// base name of the file.
+	DeniedPatterns []string
+
+	// DenyBinaries causes compiled executables and libraries, in ELF,
+	// Mach-O or PE format, to be denied.
+	DenyBinaries bool
+
+	// DenyPrivateKeys causes files holding PEM encoded private keys to
+	// be denied.
+	DenyPrivateKeys bool
+}
+
+// DefaultContentPolicy returns the policy suggested for screening charms
+// published to a store. It denies environment files, SSH private keys,
+// compiled binaries and any file holding a private key.
+func DefaultContentPolicy() ContentPolicy {
+	return ContentPolicy{
+		DeniedPatterns:  []string{".env", ".env.*", "id_rsa", "id_dsa", "id_ecdsa", "id_ed25519"},
+		DenyBinaries:    true,
+		DenyPrivateKeys: true,
+	}
+}
+
+// Validate returns an error if the policy is not valid.
+func (p ContentPolicy) Validate() error {
+	for _, pattern := range p.DeniedPatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return errors.NotValidf("denied pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// ContentViolation describes a file denied by a ContentPolicy.
+type ContentViolation struct {
+	// Path holds the slash-separated path of the file relative to
+	// the archive root.
+	Path string
+
+	// Reason describes why the file is denied.
+	Reason string
+}
+
+// ContentPolicyError is returned when files are denied by a
+// ContentPolicy.
+type ContentPolicyError struct {
+	// Violations holds the denied files, ordered by path.
+	Violations []ContentViolation
+}
+
+// Error implements error.
+func (e *ContentPolicyError) Error() string {
+	var descriptions []string
+	for _, v := range e.Violations {
+		descriptions = append(descriptions, v.Path+" ("+v.Reason+")")
+	}
+	return "content policy violated by " + strings.Join(descriptions, ", ")
+}
+
+// IsContentPolicyError reports whether the cause of err is a
+// *ContentPolicyError.
+func IsContentPolicyError(err error) bool {
+	_, ok := errors.Cause(err).(*ContentPolicyError)
+	return ok
+}
+
+// contentSniffLimit holds the number of bytes at the start of a file
+// that are examined for binaries and private keys.
+const contentSniffLimit = 64 * 1024
+
+// binaryMagic holds the magic numbers of compiled binaries.
+var binaryMagic = []struct {
+	magic  string
+	format string
+}{
+	{"\x7fELF", "ELF"},
+	{"\xfe\xed\xfa\xce", "Mach-O"},
+	{"\xfe\xed\xfa\xcf", "Mach-O"},
+	{"\xce\xfa\xed\xfe", "Mach-O"},
+	{"\xcf\xfa\xed\xfe", "Mach-O"},
+	{"MZ", "PE"},
+}
+
+var privateKeyPattern = regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----`)
+
+// deniedName returns the reason the file at the slash-separated path
+// filePath is denied by its name, if it is.
+func (p ContentPolicy) deniedName(filePath string) (string, bool) {
+	for _, pattern := range p.DeniedPatterns {
+		name := filePath
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(filePath)
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return "matches denied pattern " + pattern, true
+		}
+	}
+	return "", false
+}
+
+// deniedContent returns the reason a file starting with head is denied
+// by its content, if it is.
+func (p ContentPolicy) deniedContent(head []byte) (string, bool) {
+	if p.DenyBinaries {
+		for _, b := range binaryMagic {
+			if bytes.HasPrefix(head, []byte(b.magic)) {
+				return "is a compiled " + b.format + " binary", true
+			}
+		}
+	}
+	if p.DenyPrivateKeys && privateKeyPattern.Match(head) {
+		return "holds a private key", true
+	}
+	return "", false
+}
+
+// sniffsContent reports whether the policy needs the content of files.
+func (p ContentPolicy) sniffsContent() bool {
+	return p.DenyBinaries || p.DenyPrivateKeys
+}
+
+// check returns the reason that the regular file at the slash-separated
+// path filePath, with the given content, is denied, if it is. It
+// returns a reader yielding the full content, as some of it may have
+// been consumed.
+func (p ContentPolicy) check(filePath string, content io.Reader) (string, bool, io.Reader, error) {
+	if reason, denied := p.deniedName(filePath); denied {
+		return reason, true, content, nil
+	}
+	if !p.sniffsContent() {
+		return "", false, content, nil
+	}
+	head := make([]byte, contentSniffLimit)
+	n, err := io.ReadFull(content, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", false, nil, errors.Trace(err)
+	}
+	head = head[:n]
+	reason, denied := p.deniedContent(head)
+	return reason, denied, io.MultiReader(bytes.NewReader(head), content), nil
+}
+
+// CheckContent returns the files of the charm archive that are denied by
+// policy, ordered by path.
+func (a *CharmArchive) CheckContent(policy ContentPolicy) ([]ContentViolation, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	zipr, err := a.zopen.openZip()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer zipr.Close()
+	var violations []ContentViolation
+	for _, f := range zipr.File {
+		mode := f.Mode()
+		if mode.IsDir() || strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+		name := strings.TrimPrefix(path.Clean(f.Name), "./")
+		if !mode.IsRegular() {
+			if reason, denied := policy.deniedName(name); denied {
+				violations = append(violations, ContentViolation{Path: name, Reason: reason})
+			}
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot read %q", f.Name)
+		}
+		reason, denied, _, err := policy.check(name, rc)
+		rc.Close()
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot read %q", f.Name)
+		}
+		if denied {
+			violations = append(violations, ContentViolation{Path: name, Reason: reason})
+		}
+	}
+	sortContentViolations(violations)
+	return violations, nil
+}
+
+func sortContentViolations(violations []ContentViolation) {
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Path < violations[j].Path
+	})
+}
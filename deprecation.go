@@ -0,0 +1,112 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+)
+
+// deprecationDateLayout is the layout of the date in the deprecated
+// section of metadata.yaml.
+const deprecationDateLayout = "2006-01-02"
+
+// Deprecation holds the author's declaration, in the deprecated section
+// of metadata.yaml, that a charm is deprecated. The section may also be
+// given as just "deprecated: true".
+type Deprecation struct {
+	// Date optionally holds the day from which the charm is
+	// deprecated. It is zero if not given.
+	Date time.Time `bson:"date,omitempty" json:"date,omitempty"`
+
+	// Message optionally explains the deprecation to users.
+	Message string `bson:"message,omitempty" json:"message,omitempty"`
+}
+
+var deprecationSchema = schema.FieldMap(
+	schema.Fields{
+		"date":    schema.String(),
+		"message": schema.String(),
+	},
+	schema.Defaults{
+		"date":    schema.Omit,
+		"message": schema.Omit,
+	},
+)
+
+func parseDeprecation(input interface{}) (*Deprecation, error) {
+	switch input := input.(type) {
+	case bool:
+		if !input {
+			return nil, nil
+		}
+		return &Deprecation{}, nil
+	case map[string]interface{}:
+		var d Deprecation
+		if date, ok := input["date"].(string); ok {
+			t, err := time.Parse(deprecationDateLayout, date)
+			if err != nil {
+				return nil, errors.NotValidf("date %q", date)
+			}
+			d.Date = t
+		}
+		if message, ok := input["message"].(string); ok {
+			d.Message = message
+		}
+		return &d, nil
+	}
+	return nil, nil
+}
+
+// marshaledDeprecation returns the YAML form of d: true when it holds
+// no details, or the deprecated section in full.
+func marshaledDeprecation(d *Deprecation) interface{} {
+	if d == nil {
+		return nil
+	}
+	if d.Date.IsZero() && d.Message == "" {
+		return true
+	}
+	md := struct {
+		Date    string `yaml:"date,omitempty"`
+		Message string `yaml:"message,omitempty"`
+	}{
+		Message: d.Message,
+	}
+	if !d.Date.IsZero() {
+		md.Date = d.Date.Format(deprecationDateLayout)
+	}
+	return md
+}
+
+// IsDeprecated reports whether the charm is deprecated, either
+// explicitly or by being superseded by another charm.
+func (m Meta) IsDeprecated() bool {
+	return m.Deprecated != nil || m.SupersededBy != ""
+}
+
+// DeprecationWarnings returns a warning describing the deprecation of
+// the charm, if it is deprecated, including the date, message and
+// replacement declared by its author.
+func (m Meta) DeprecationWarnings() []string {
+	if !m.IsDeprecated() {
+		return nil
+	}
+	warning := fmt.Sprintf("charm %q is deprecated", m.Name)
+	if m.Deprecated != nil {
+		if !m.Deprecated.Date.IsZero() {
+			warning += " since " + m.Deprecated.Date.Format(deprecationDateLayout)
+		}
+		if m.Deprecated.Message != "" {
+			warning += ": " + m.Deprecated.Message
+		}
+	}
+	if m.SupersededBy != "" {
+		warning += fmt.Sprintf("; use %q instead", m.SupersededBy)
+	}
+	return []string{warning}
+}
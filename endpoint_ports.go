@@ -0,0 +1,128 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+)
+
+// EndpointPort describes a range of ports, and the protocol used on
+// them, that traffic for a relation endpoint is expected to use by
+// default. The ports of each endpoint are held in Relation.Ports and
+// are declared in the ports list of a relation, as in:
+//
+//	provides:
+//	  website:
+//	    interface: http
+//	    ports: [80/tcp, 8000-8010/udp, icmp]
+//
+// A port given without a protocol is assumed to be TCP.
+type EndpointPort struct {
+	FromPort int    `bson:"from-port"`
+	ToPort   int    `bson:"to-port"`
+	Protocol string `bson:"protocol"`
+}
+
+var endpointPortRE = regexp.MustCompile(`^([0-9]+)(?:-([0-9]+))?(?:/([a-z]+))?$`)
+
+// ParseEndpointPort parses an endpoint port in the form "port",
+// "port/protocol", "from-to/protocol" or "icmp".
+func ParseEndpointPort(s string) (EndpointPort, error) {
+	if s == "icmp" {
+		return EndpointPort{FromPort: -1, ToPort: -1, Protocol: "icmp"}, nil
+	}
+	match := endpointPortRE.FindStringSubmatch(s)
+	if match == nil {
+		return EndpointPort{}, errors.NotValidf("endpoint port %q", s)
+	}
+	port := EndpointPort{Protocol: "tcp"}
+	var err error
+	if port.FromPort, err = strconv.Atoi(match[1]); err != nil {
+		return EndpointPort{}, errors.NotValidf("endpoint port %q", s)
+	}
+	port.ToPort = port.FromPort
+	if match[2] != "" {
+		if port.ToPort, err = strconv.Atoi(match[2]); err != nil {
+			return EndpointPort{}, errors.NotValidf("endpoint port %q", s)
+		}
+	}
+	if match[3] != "" {
+		port.Protocol = match[3]
+	}
+	if err := port.Validate(); err != nil {
+		return EndpointPort{}, errors.Trace(err)
+	}
+	return port, nil
+}
+
+// Validate checks that the port range and protocol are valid.
+func (p EndpointPort) Validate() error {
+	switch p.Protocol {
+	case "icmp":
+		if p.FromPort != -1 || p.ToPort != -1 {
+			return errors.NotValidf("icmp endpoint port with port numbers")
+		}
+		return nil
+	case "tcp", "udp":
+	default:
+		return errors.NotValidf("endpoint port protocol %q", p.Protocol)
+	}
+	if p.FromPort < 1 || p.FromPort > 65535 || p.ToPort < 1 || p.ToPort > 65535 {
+		return errors.NotValidf("endpoint port %s outside 1-65535", p)
+	}
+	if p.FromPort > p.ToPort {
+		return errors.NotValidf("endpoint port range %s", p)
+	}
+	return nil
+}
+
+// String returns the port in the form accepted by ParseEndpointPort.
+func (p EndpointPort) String() string {
+	switch {
+	case p.Protocol == "icmp":
+		return p.Protocol
+	case p.FromPort == p.ToPort:
+		return fmt.Sprintf("%d/%s", p.FromPort, p.Protocol)
+	}
+	return fmt.Sprintf("%d-%d/%s", p.FromPort, p.ToPort, p.Protocol)
+}
+
+// endpointPortC coerces a port number or a string accepted by
+// ParseEndpointPort into an EndpointPort.
+type endpointPortC struct{}
+
+func (c endpointPortC) Coerce(v interface{}, path []string) (newv interface{}, err error) {
+	s, err := schema.OneOf(schema.Int(), stringC).Coerce(v, path)
+	if err != nil {
+		return nil, err
+	}
+	if n, ok := s.(int64); ok {
+		s = strconv.FormatInt(n, 10)
+	}
+	port, err := ParseEndpointPort(s.(string))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", strings.Join(path[1:], ""), err)
+	}
+	return port, nil
+}
+
+// validateEndpointPorts checks that the endpoint ports declared by the
+// relations of the charm are valid.
+func validateEndpointPorts(meta Meta) error {
+	relations := meta.CombinedRelations()
+	for _, name := range sortedRelationNames(relations) {
+		for _, port := range relations[name].Ports {
+			if err := port.Validate(); err != nil {
+				return errors.Annotatef(err, "relation %q", name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"bytes"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/charm/v8"
+)
+
+type EndpointPortSuite struct{}
+
+var _ = gc.Suite(&EndpointPortSuite{})
+
+var parseEndpointPortTests = []struct {
+	input  string
+	port   charm.EndpointPort
+	output string
+	err    string
+}{{
+	input:  "80",
+	port:   charm.EndpointPort{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+	output: "80/tcp",
+}, {
+	input:  "53/udp",
+	port:   charm.EndpointPort{FromPort: 53, ToPort: 53, Protocol: "udp"},
+	output: "53/udp",
+}, {
+	input:  "8000-8010/tcp",
+	port:   charm.EndpointPort{FromPort: 8000, ToPort: 8010, Protocol: "tcp"},
+	output: "8000-8010/tcp",
+}, {
+	input:  "icmp",
+	port:   charm.EndpointPort{FromPort: -1, ToPort: -1, Protocol: "icmp"},
+	output: "icmp",
+}, {
+	input: "http",
+	err:   `endpoint port "http" not valid`,
+}, {
+	input: "80/sctp",
+	err:   `endpoint port protocol "sctp" not valid`,
+}, {
+	input: "0/tcp",
+	err:   `endpoint port 0/tcp outside 1-65535 not valid`,
+}, {
+	input: "70000",
+	err:   `endpoint port 70000/tcp outside 1-65535 not valid`,
+}, {
+	input: "90-80/udp",
+	err:   `endpoint port range 90-80/udp not valid`,
+}}
+
+func (s *EndpointPortSuite) TestParseEndpointPort(c *gc.C) {
+	for i, test := range parseEndpointPortTests {
+		c.Logf("test %d: %q", i, test.input)
+		port, err := charm.ParseEndpointPort(test.input)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(port, jc.DeepEquals, test.port)
+		c.Check(port.String(), gc.Equals, test.output)
+	}
+}
+
+func (s *EndpointPortSuite) TestReadMetaPorts(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(`
+name: a
+summary: b
+description: c
+provides:
+  website:
+    interface: http
+    ports: [80, 443/tcp, 8000-8010/udp]
+requires:
+  db: mysql
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.Provides["website"].Ports, jc.DeepEquals, []charm.EndpointPort{
+		{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+		{FromPort: 443, ToPort: 443, Protocol: "tcp"},
+		{FromPort: 8000, ToPort: 8010, Protocol: "udp"},
+	})
+	c.Assert(meta.Requires["db"].Ports, gc.HasLen, 0)
+
+	data, err := yaml.Marshal(meta)
+	c.Assert(err, jc.ErrorIsNil)
+	roundTripped, err := charm.ReadMeta(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(roundTripped.Provides, jc.DeepEquals, meta.Provides)
+}
+
+func (s *EndpointPortSuite) TestReadMetaInvalidPort(c *gc.C) {
+	_, err := charm.ReadMeta(strings.NewReader(`
+name: a
+summary: b
+description: c
+provides:
+  website:
+    interface: http
+    ports: [80/sctp]
+`))
+	c.Assert(err, gc.ErrorMatches, `metadata: provides.website.ports\[0\]: endpoint port protocol "sctp" not valid`)
+}
+
+func (s *EndpointPortSuite) TestCheckInvalidPort(c *gc.C) {
+	meta := charm.Meta{
+		Name: "a",
+		Provides: map[string]charm.Relation{
+			"website": {
+				Name:      "website",
+				Role:      charm.RoleProvider,
+				Interface: "http",
+				Scope:     charm.ScopeGlobal,
+				Ports:     []charm.EndpointPort{{FromPort: 0, ToPort: 0, Protocol: "tcp"}},
+			},
+		},
+	}
+	c.Assert(meta.Check(), gc.ErrorMatches, `charm "a" has invalid endpoint ports: relation "website": endpoint port 0/tcp outside 1-65535 not valid`)
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/charm/v8/resource"
+)
+
+// ImportedCharm holds a charm read by Import, along with the URL it was
+// exported from.
+type ImportedCharm struct {
+	URL   *URL
+	Charm *CharmArchive
+}
+
+// Export writes the given charms to w as a pack, recording the URL and
+// digest of each charm in the index and signing the index with key, so
+// that the charms can be mirrored into an offline environment and
+// checked there by Import. Each charm is exported under its local URL,
+// and only charm directories and charm archives can be exported.
+func Export(charms []Charm, w io.Writer, key ed25519.PrivateKey) error {
+	return ExportWithOptions(charms, w, key, ExportOptions{})
+}
+
+// ExportOptions holds options for ExportWithOptions. The zero value
+// gives the exports written by Export.
+type ExportOptions struct {
+	// HashAlgorithm holds the algorithm the digests of the charms are
+	// computed with. If it is empty, SHA-384 is used.
+	HashAlgorithm resource.HashAlgorithm
+}
+
+// ExportWithOptions is like Export but allows the export to be
+// configured with the given options.
+func ExportWithOptions(charms []Charm, w io.Writer, key ed25519.PrivateKey, opts ExportOptions) error {
+	if len(key) != ed25519.PrivateKeySize {
+		return errors.NotValidf("signing key")
+	}
+	algorithm := opts.HashAlgorithm
+	if algorithm == "" {
+		algorithm = resource.SHA384
+	}
+	pw, err := NewPackWriterUsing(w, algorithm)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	pw.signingKey = key
+	for _, ch := range charms {
+		name := ch.Meta().Name
+		url := &URL{
+			Schema:   Local.String(),
+			Name:     name,
+			Revision: ch.Revision(),
+		}
+		pr, pipeWriter := io.Pipe()
+		go func() {
+			pipeWriter.CloseWithError(writeCharmArchive(ch, pipeWriter))
+		}()
+		err := pw.add(name, PackCharm, url.String(), pr)
+		pr.Close()
+		if err != nil {
+			return errors.Annotatef(err, "exporting charm %q", url)
+		}
+	}
+	return errors.Trace(pw.Close())
+}
+
+// Import reads the charms exported to r by Export. It checks that the
+// index was signed with the private key matching key, and that each
+// charm matches the digest and URL recorded for it. The export is read
+// into memory.
+func Import(r io.Reader, key ed25519.PublicKey) ([]ImportedCharm, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading export")
+	}
+	pack, err := ReadPack(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := pack.VerifySignature(key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var imported []ImportedCharm
+	for _, member := range pack.Members() {
+		if member.Kind != PackCharm {
+			return nil, errors.NotValidf("export member %q of kind %q", member.Name, member.Kind)
+		}
+		url, err := ParseURL(member.URL)
+		if err != nil {
+			return nil, errors.Annotatef(err, "importing charm %q", member.Name)
+		}
+		ch, err := pack.ReadCharm(member.Name)
+		if err != nil {
+			return nil, errors.Annotatef(err, "importing charm %q", url)
+		}
+		if ch.Meta().Name != url.Name || ch.Revision() != url.Revision {
+			return nil, errors.NotValidf("charm %q with name %q and revision %d",
+				url, ch.Meta().Name, ch.Revision())
+		}
+		imported = append(imported, ImportedCharm{
+			URL:   url,
+			Charm: ch,
+		})
+	}
+	return imported, nil
+}
+
+// writeCharmArchive writes the charm to w as a charm archive.
+func writeCharmArchive(ch Charm, w io.Writer) error {
+	switch ch := ch.(type) {
+	case *CharmDir:
+		return ch.ArchiveTo(w)
+	case *CharmArchive:
+		switch zo := ch.zopen.(type) {
+		case *zipPathOpener:
+			f, err := os.Open(zo.path)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			defer f.Close()
+			_, err = io.Copy(w, f)
+			return errors.Trace(err)
+		case *zipReaderOpener:
+			_, err := io.Copy(w, io.NewSectionReader(zo.r, 0, zo.size))
+			return errors.Trace(err)
+		}
+	}
+	return errors.NotSupportedf("exporting charm of type %T", ch)
+}
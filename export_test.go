@@ -3,6 +3,10 @@
 
 package charm
 
+import (
+	"github.com/juju/schema"
+)
+
 // Export meaningful bits for tests only.
 
 var (
@@ -16,6 +20,21 @@ var (
 	ParseResourceMeta         = parseResourceMeta
 )
 
+// MetaSchemaFields holds the fields accepted by each of the checkers
+// used by ReadMeta, keyed by the part of the metadata they check.
+var MetaSchemaFields = map[string]schema.Fields{
+	"charm":      charmFields,
+	"relation":   ifaceFields,
+	"storage":    storageFields,
+	"device":     deviceFields,
+	"deployment": deploymentFields,
+	"payload":    payloadClassFields,
+	"resource":   resourceFields,
+	"system":     systemFields,
+	"container":  containerFields,
+	"mount":      mountFields,
+}
+
 func MissingSeriesError() error {
 	return missingSeriesError
 }
@@ -23,3 +42,35 @@ func MissingSeriesError() error {
 func (bd *BundleData) ClearUnmarshaledWithServices() {
 	bd.unmarshaledWithServices = false
 }
+
+// SaveKnownSeries returns a function that restores the known series
+// to their current state.
+func SaveKnownSeries() (restore func()) {
+	knownSeriesMutex.Lock()
+	saved := make(map[string]SeriesInfo, len(knownSeries))
+	for name, info := range knownSeries {
+		saved[name] = info
+	}
+	knownSeriesMutex.Unlock()
+	return func() {
+		knownSeriesMutex.Lock()
+		knownSeries = saved
+		knownSeriesMutex.Unlock()
+	}
+}
+
+// SaveKnownInterfaces returns a function that restores the interface
+// catalog to its current state.
+func SaveKnownInterfaces() (restore func()) {
+	knownInterfacesMutex.Lock()
+	saved := make(map[string]InterfaceInfo, len(knownInterfaces))
+	for name, info := range knownInterfaces {
+		saved[name] = info
+	}
+	knownInterfacesMutex.Unlock()
+	return func() {
+		knownInterfacesMutex.Lock()
+		knownInterfaces = saved
+		knownInterfacesMutex.Unlock()
+	}
+}
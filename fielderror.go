@@ -0,0 +1,280 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// Position holds a location in a YAML document. Lines and columns are
+// numbered from 1; zero means that the line or column is not known.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// String returns the position in the usual "line:column" form.
+func (p Position) String() string {
+	if p.Column == 0 {
+		return strconv.Itoa(p.Line)
+	}
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// The kinds of problem reported by Meta.Check, ReadMeta and ReadConfig. A
+// *FieldError matches its Reason with errors.Is from the standard
+// library, so that callers can tell problems apart without parsing
+// messages.
+var (
+	ErrInvalidYAML                         = errors.New("invalid YAML")
+	ErrInvalidFieldValue                   = errors.New("invalid field value")
+	ErrUnknownField                        = errors.New("unknown field")
+	ErrMismatchedRelation                  = errors.New("mismatched relation")
+	ErrReservedRelationName                = errors.New("reserved relation name")
+	ErrReservedInterface                   = errors.New("reserved interface")
+	ErrDuplicateRelationName               = errors.New("duplicate relation name")
+	ErrInvalidRenamedRelations             = errors.New("invalid renamed relations")
+	ErrInvalidEndpointPorts                = errors.New("invalid endpoint ports")
+	ErrInvalidExtraBindings                = errors.New("invalid extra bindings")
+	ErrSubordinateWithoutContainerRelation = errors.New("subordinate without container relation")
+	ErrInvalidSeries                       = errors.New("invalid series")
+	ErrReservedStorageName                 = errors.New("reserved storage name")
+	ErrStorageNameClash                    = errors.New("storage name clash")
+	ErrMissingStorageType                  = errors.New("missing storage type")
+	ErrInvalidStorageCount                 = errors.New("invalid storage count")
+	ErrStorageMinimumSizeTooLarge          = errors.New("storage minimum size too large")
+	ErrUnknownStorageProperty              = errors.New("unknown storage property")
+	ErrInvalidStorageLocation              = errors.New("invalid storage location")
+	ErrMissingDeviceType                   = errors.New("missing device type")
+	ErrInvalidDeviceCount                  = errors.New("invalid device count")
+	ErrInvalidPayloadClass                 = errors.New("invalid payload class")
+	ErrInvalidResource                     = errors.New("invalid resource")
+	ErrInvalidTerm                         = errors.New("invalid term")
+	ErrInvalidCharmUser                    = errors.New("invalid charm user")
+	ErrInvalidCharmUserGroup               = errors.New("invalid charm user group")
+	ErrInvalidLinkURL                      = errors.New("invalid link URL")
+	ErrInvalidCharmName                    = errors.New("invalid charm name")
+	ErrInvalidInterface                    = errors.New("invalid interface")
+	ErrSeriesInFormatV2                    = errors.New("series in format 2 metadata")
+	ErrInvalidRelationLimit                = errors.New("invalid relation limit")
+	ErrInvalidSupersededBy                 = errors.New("invalid superseded-by")
+	ErrInvalidLocale                       = errors.New("invalid locale")
+	ErrInvalidConfigDefault                = errors.New("invalid config default")
+	ErrInvalidReplacedBy                   = errors.New("invalid replaced-by")
+	ErrInvalidAllowedValues                = errors.New("invalid allowed values")
+	ErrInvalidRange                        = errors.New("invalid range")
+	ErrInvalidPattern                      = errors.New("invalid pattern")
+)
+
+// FieldError is returned by Meta.Check and ReadMeta for problems with
+// a particular field of the metadata, and by ReadConfig for invalid
+// option defaults, replacements and constraints. Its message is that of the
+// underlying error; the kind of problem, the field and, when the
+// metadata was read from a document, its position are available so
+// that editors and tools can point at the offending field.
+type FieldError struct {
+	// Path holds the path of the field, in the form used by
+	// ReadMetaStrict, as in "storage.data.location" or
+	// "series[1]". It is empty if the field is not known.
+	Path string
+
+	// Position holds the position of the field in the metadata
+	// document. It is the zero Position if it is not known.
+	Position Position
+
+	// Reason holds one of the Err* errors declared above, giving
+	// the kind of problem, or nil if it is not known.
+	Reason error
+
+	// Err holds the underlying error.
+	Err error
+
+	// File optionally holds the path of the file holding the
+	// metadata document, as set by ReadMetaFile. When it is set, it
+	// prefixes the message of the error.
+	File string
+}
+
+// Error implements error.
+func (e *FieldError) Error() string {
+	if e.File != "" {
+		return e.File + ": " + e.Err.Error()
+	}
+	return e.Err.Error()
+}
+
+// Cause returns the cause of the underlying error, so that FieldError
+// works with errors.Cause and the errors.Is* functions.
+func (e *FieldError) Cause() error {
+	return errors.Cause(e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the reason for the error.
+func (e *FieldError) Is(target error) bool {
+	return e.Reason != nil && e.Reason == target
+}
+
+// fieldErrorf returns a *FieldError for the field at path, with the
+// given reason and a message formatted as for fmt.Errorf.
+func fieldErrorf(path string, reason error, format string, args ...interface{}) error {
+	return &FieldError{
+		Path:   path,
+		Reason: reason,
+		Err:    fmt.Errorf(format, args...),
+	}
+}
+
+var (
+	// yamlLineError matches the errors returned by the YAML
+	// parser, which hold the line of the problem.
+	yamlLineError = regexp.MustCompile(`^yaml: (?:unmarshal errors:\n\s*)?line (\d+):`)
+
+	// schemaPathError matches the errors returned when the metadata
+	// does not match charmSchema, which start with the path of the
+	// field.
+	schemaPathError = regexp.MustCompile(`^metadata: ([^\s:]+): `)
+)
+
+// locateMetaError returns err, found reading the metadata document held
+// in data, as a *FieldError holding the position of the field that it
+// concerns, if that can be found.
+func locateMetaError(data []byte, err error) error {
+	if fieldErr, ok := err.(*FieldError); ok {
+		fieldErr.Position, _ = findFieldPosition(data, fieldErr.Path)
+		return fieldErr
+	}
+	message := err.Error()
+	if m := yamlLineError.FindStringSubmatch(message); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return &FieldError{
+			Position: Position{Line: line},
+			Reason:   ErrInvalidYAML,
+			Err:      err,
+		}
+	}
+	if m := schemaPathError.FindStringSubmatch(message); m != nil {
+		pos, _ := findFieldPosition(data, m[1])
+		return &FieldError{
+			Path:     m[1],
+			Position: pos,
+			Reason:   ErrInvalidFieldValue,
+			Err:      err,
+		}
+	}
+	return err
+}
+
+// fieldPathElem holds one element of a field path: either the key of a
+// mapping or, if index is not negative, an item of a sequence.
+type fieldPathElem struct {
+	key   string
+	index int
+}
+
+var fieldPathIndex = regexp.MustCompile(`\[(\d+)\]`)
+
+func parseFieldPath(path string) []fieldPathElem {
+	var elems []fieldPathElem
+	for _, part := range strings.Split(path, ".") {
+		key := part
+		if i := strings.Index(part, "["); i >= 0 {
+			key = part[:i]
+		}
+		if key != "" {
+			elems = append(elems, fieldPathElem{key: key, index: -1})
+		}
+		for _, m := range fieldPathIndex.FindAllStringSubmatch(part, -1) {
+			index, _ := strconv.Atoi(m[1])
+			elems = append(elems, fieldPathElem{index: index})
+		}
+	}
+	return elems
+}
+
+// findFieldPosition returns the position in the YAML document held in
+// data of the field at path. Only block style mappings and sequences
+// are followed; if the field lies within a flow style collection, or
+// within an item of a sequence, the position of the innermost field
+// that can be found is returned. It returns false if not even the top
+// level field can be found.
+func findFieldPosition(data []byte, path string) (Position, bool) {
+	lines := strings.Split(string(data), "\n")
+	var pos Position
+	start, parentIndent := 0, -1
+	for _, elem := range parseFieldPath(path) {
+		line, column, ok := findYAMLChild(lines, start, parentIndent, elem)
+		if !ok {
+			break
+		}
+		pos = Position{Line: line + 1, Column: column + 1}
+		if elem.index >= 0 {
+			break
+		}
+		start, parentIndent = line+1, column
+	}
+	return pos, pos.Line > 0
+}
+
+// findYAMLChild returns the line and column, numbered from 0, of the
+// entry matching elem in the block that starts at lines[start] and
+// belongs to a parent entry indented by parentIndent.
+func findYAMLChild(lines []string, start, parentIndent int, elem fieldPathElem) (int, int, bool) {
+	childIndent, item := -1, 0
+	for i := start; i < len(lines); i++ {
+		text := strings.TrimLeft(lines[i], " ")
+		if text == "" || text[0] == '#' || text == "---" {
+			continue
+		}
+		indent := len(lines[i]) - len(text)
+		isItem := text == "-" || strings.HasPrefix(text, "- ")
+		// Sequence items may be indented as much as their parent.
+		if indent < parentIndent || indent == parentIndent && !(isItem && elem.index >= 0) {
+			break
+		}
+		if childIndent < 0 {
+			childIndent = indent
+		}
+		if indent != childIndent {
+			continue
+		}
+		if elem.index < 0 {
+			if yamlKeyMatches(text, elem.key) {
+				return i, indent, true
+			}
+			continue
+		}
+		if !isItem {
+			break
+		}
+		if item == elem.index {
+			return i, indent + 2, true
+		}
+		item++
+	}
+	return 0, 0, false
+}
+
+// yamlKeyMatches reports whether the line text, stripped of its
+// indentation, holds a mapping entry with the given key.
+func yamlKeyMatches(text, key string) bool {
+	for _, quoted := range []string{key, `"` + key + `"`, "'" + key + "'"} {
+		if rest := strings.TrimPrefix(text, quoted); rest != text {
+			rest = strings.TrimLeft(rest, " ")
+			if rest == ":" || strings.HasPrefix(rest, ": ") {
+				return true
+			}
+		}
+	}
+	return false
+}
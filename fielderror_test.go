@@ -0,0 +1,247 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	stderrors "errors"
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type FieldErrorSuite struct{}
+
+var _ = gc.Suite(&FieldErrorSuite{})
+
+func (s *FieldErrorSuite) TestReadMetaPositions(c *gc.C) {
+	for i, test := range []struct {
+		about    string
+		yaml     string
+		path     string
+		position charm.Position
+		err      string
+	}{{
+		about: "check failure",
+		yaml: `
+name: a
+summary: b
+description: c
+storage:
+  data:
+    type: filesystem
+    location: /etc
+`,
+		path:     "storage.data.location",
+		position: charm.Position{Line: 8, Column: 5},
+		err:      `charm "a" storage "data": invalid location "/etc": "/etc" is a reserved mount point`,
+	}, {
+		about: "sequence item",
+		yaml: `
+name: a
+summary: b
+description: c
+series:
+- focal
+- "bad series"
+`,
+		path:     "series[1]",
+		position: charm.Position{Line: 7, Column: 3},
+		err:      `charm "a" declares invalid series: "bad series"`,
+	}, {
+		about: "schema failure",
+		yaml: `
+name: a
+summary: b
+description: c
+requires:
+  # The database.
+  db:
+    scope: global
+`,
+		path:     "requires.db.interface",
+		position: charm.Position{Line: 7, Column: 3},
+		err:      `metadata: requires.db.interface: expected string, got nothing`,
+	}, {
+		about:    "parse failure",
+		yaml:     "name: a\nsummary: [b\n",
+		position: charm.Position{Line: 2},
+		err:      `yaml: line 2: .*`,
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		_, err := charm.ReadMeta(strings.NewReader(test.yaml))
+		c.Assert(err, gc.ErrorMatches, test.err)
+		fieldErr, ok := err.(*charm.FieldError)
+		c.Assert(ok, jc.IsTrue, gc.Commentf("%#v", err))
+		c.Check(fieldErr.Path, gc.Equals, test.path)
+		c.Check(fieldErr.Position, gc.Equals, test.position)
+	}
+}
+
+func (s *FieldErrorSuite) TestCheckWithoutDocument(c *gc.C) {
+	meta := charm.Meta{
+		Name:        "a",
+		Subordinate: true,
+	}
+	err := meta.Check()
+	fieldErr, ok := err.(*charm.FieldError)
+	c.Assert(ok, jc.IsTrue)
+	c.Check(fieldErr.Path, gc.Equals, "subordinate")
+	c.Check(fieldErr.Position, gc.Equals, charm.Position{})
+}
+
+func (s *FieldErrorSuite) TestCause(c *gc.C) {
+	err := &charm.FieldError{
+		Path: "a",
+		Err:  errors.Annotate(errors.NotValidf("b"), "c"),
+	}
+	c.Check(err, gc.ErrorMatches, "c: b not valid")
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *FieldErrorSuite) TestPositionString(c *gc.C) {
+	c.Check(charm.Position{Line: 3, Column: 5}.String(), gc.Equals, "3:5")
+	c.Check(charm.Position{Line: 3}.String(), gc.Equals, "3")
+}
+
+func (s *FieldErrorSuite) TestReasons(c *gc.C) {
+	for i, test := range []struct {
+		about  string
+		yaml   string
+		reason error
+		path   string
+	}{{
+		about: "reserved relation name",
+		yaml: `
+name: a
+summary: b
+description: c
+provides:
+  juju-foo: foo
+`,
+		reason: charm.ErrReservedRelationName,
+		path:   "provides.juju-foo",
+	}, {
+		about: "storage location",
+		yaml: `
+name: a
+summary: b
+description: c
+storage:
+  data:
+    type: filesystem
+    location: /proc/x
+`,
+		reason: charm.ErrInvalidStorageLocation,
+		path:   "storage.data.location",
+	}, {
+		about:  "invalid YAML",
+		yaml:   "name: [",
+		reason: charm.ErrInvalidYAML,
+	}, {
+		about: "invalid field value",
+		yaml: `
+name: a
+summary: b
+description: c
+subordinate: maybe
+`,
+		reason: charm.ErrInvalidFieldValue,
+		path:   "subordinate",
+	}, {
+		about: "invalid charm name",
+		yaml: `
+name: My_Charm
+summary: b
+description: c
+`,
+		reason: charm.ErrInvalidCharmName,
+		path:   "name",
+	}, {
+		about: "invalid interface",
+		yaml: `
+name: a
+summary: b
+description: c
+requires:
+  db: My SQL
+`,
+		reason: charm.ErrInvalidInterface,
+		path:   "requires.db.interface",
+	}, {
+		about: "series in format 2 metadata",
+		yaml: `
+name: a
+summary: b
+description: c
+series: [jammy]
+bases:
+  - name: ubuntu
+    channel: "22.04"
+`,
+		reason: charm.ErrSeriesInFormatV2,
+		path:   "series",
+	}, {
+		about: "negative relation limit",
+		yaml: `
+name: a
+summary: b
+description: c
+requires:
+  db:
+    interface: mysql
+    limit: -1
+`,
+		reason: charm.ErrInvalidRelationLimit,
+		path:   "requires.db.limit",
+	}, {
+		about: "superseded by itself",
+		yaml: `
+name: a
+summary: b
+description: c
+superseded-by: a
+`,
+		reason: charm.ErrInvalidSupersededBy,
+		path:   "superseded-by",
+	}, {
+		about: "invalid locale",
+		yaml: `
+name: a
+summary: b
+description: c
+description-i18n:
+  en_GB!: c
+`,
+		reason: charm.ErrInvalidLocale,
+		path:   "description-i18n.en_GB!",
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		_, err := charm.ReadMeta(strings.NewReader(test.yaml))
+		c.Assert(err, gc.NotNil)
+		c.Check(stderrors.Is(err, test.reason), jc.IsTrue, gc.Commentf("%v", err))
+		c.Check(stderrors.Is(err, charm.ErrInvalidSeries), jc.IsFalse)
+		var fieldErr *charm.FieldError
+		c.Assert(stderrors.As(err, &fieldErr), jc.IsTrue)
+		c.Check(fieldErr.Path, gc.Equals, test.path)
+	}
+}
+
+func (s *FieldErrorSuite) TestUnknownFieldReason(c *gc.C) {
+	_, err := charm.ReadMetaStrict(strings.NewReader(`
+name: a
+summary: b
+description: c
+provids:
+  db: mysql
+`))
+	c.Assert(err, gc.ErrorMatches, `metadata: unknown field\(s\): provids`)
+	c.Assert(stderrors.Is(err, charm.ErrUnknownField), jc.IsTrue)
+	fieldErr := err.(*charm.FieldError)
+	c.Assert(fieldErr.Path, gc.Equals, "provids")
+	c.Assert(fieldErr.Position, gc.Equals, charm.Position{Line: 5, Column: 1})
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"os"
+	"path"
+	"sort"
+)
+
+// The names of the files, relative to the root of a charm, that this
+// package reads or writes.
+const (
+	MetadataFile   = "metadata.yaml"
+	ConfigFile     = "config.yaml"
+	ActionsFile    = "actions.yaml"
+	MetricsFile    = "metrics.yaml"
+	LXDProfileFile = "lxd-profile.yaml"
+	ChangelogFile  = "changelog.yaml"
+	RevisionFile   = "revision"
+	VersionFile    = "version"
+	IconFile       = "icon.svg"
+	JujuIgnoreFile = ".jujuignore"
+
+	// HooksDir is the directory holding the charm's hooks.
+	HooksDir = "hooks"
+
+	// ActionsDir is the directory holding the executables that run
+	// the charm's actions.
+	ActionsDir = "actions"
+
+	// DispatchFile is the executable that, when present, runs all of
+	// the charm's hooks and actions.
+	DispatchFile = "dispatch"
+)
+
+// contentsManifest holds the files recognized by this package.
+var contentsManifest = []string{
+	MetadataFile,
+	ConfigFile,
+	ActionsFile,
+	MetricsFile,
+	LXDProfileFile,
+	ChangelogFile,
+	RevisionFile,
+	VersionFile,
+	IconFile,
+	JujuIgnoreFile,
+}
+
+// ContentsManifest returns the names of the files at the root of a charm
+// that this package recognizes, sorted by name. Only metadata.yaml is
+// required; the others are optional.
+func ContentsManifest() []string {
+	result := append([]string(nil), contentsManifest...)
+	sort.Strings(result)
+	return result
+}
+
+// collectMetricsHook is the hook that collects the metrics declared in
+// metrics.yaml.
+var collectMetricsHook = path.Join(HooksDir, "collect-metrics")
+
+// MissingFiles returns the files, relative to the root of the charm,
+// that the charm is expected to hold given its actions and metrics, but
+// that are absent: action executables, the collect-metrics hook for
+// charm metrics and metrics.yaml for a collect-metrics hook. Charms with
+// a dispatch executable are not expected to hold action executables or
+// hooks.
+func (dir *CharmDir) MissingFiles() []string {
+	return missingFiles(dir, func(name string) bool {
+		_, err := os.Lstat(dir.join(name))
+		return err == nil
+	})
+}
+
+// MissingFiles is like CharmDir.MissingFiles, for a charm archive.
+func (a *CharmArchive) MissingFiles() ([]string, error) {
+	manifest, err := a.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	return missingFiles(a, manifest.Contains), nil
+}
+
+// missingFiles returns the files expected in the charm for which exists
+// returns false, sorted by name.
+func missingFiles(ch Charm, exists func(name string) bool) []string {
+	var missing []string
+	dispatch := exists(DispatchFile)
+	if actions := ch.Actions(); actions != nil && !dispatch {
+		for name := range actions.ActionSpecs {
+			if executable := path.Join(ActionsDir, name); !exists(executable) {
+				missing = append(missing, executable)
+			}
+		}
+	}
+	metrics := ch.Metrics()
+	if metrics == nil && exists(collectMetricsHook) {
+		missing = append(missing, MetricsFile)
+	}
+	if metrics != nil && !dispatch && !exists(collectMetricsHook) {
+		for name := range metrics.Metrics {
+			// Juju collects the builtin metrics itself.
+			if !IsBuiltinMetric(name) {
+				missing = append(missing, collectMetricsHook)
+				break
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type FilesSuite struct{}
+
+var _ = gc.Suite(&FilesSuite{})
+
+func (s *FilesSuite) TestContentsManifest(c *gc.C) {
+	manifest := charm.ContentsManifest()
+	c.Assert(sort.StringsAreSorted(manifest), jc.IsTrue)
+	c.Assert(manifest, jc.SameContents, []string{
+		charm.MetadataFile,
+		charm.ConfigFile,
+		charm.ActionsFile,
+		charm.MetricsFile,
+		charm.LXDProfileFile,
+		charm.ChangelogFile,
+		charm.RevisionFile,
+		charm.VersionFile,
+		charm.IconFile,
+		charm.JujuIgnoreFile,
+	})
+
+	// The result is a copy.
+	manifest[0] = "mutated"
+	c.Assert(charm.ContentsManifest()[0], gc.Not(gc.Equals), "mutated")
+}
+
+func (s *FilesSuite) TestDummyCharmFilesRecognized(c *gc.C) {
+	path := charmDirPath(c, "dummy")
+	for _, name := range []string{charm.MetadataFile, charm.ConfigFile, charm.ActionsFile, charm.RevisionFile} {
+		_, err := os.Stat(filepath.Join(path, name))
+		c.Check(err, jc.ErrorIsNil, gc.Commentf("%s", name))
+	}
+}
+
+func (s *FilesSuite) TestMissingFiles(c *gc.C) {
+	dir := readCharmDir(c, "dummy")
+	c.Assert(dir.MissingFiles(), jc.DeepEquals, []string{"actions/snapshot"})
+
+	archive, err := charm.ReadCharmArchive(archivePath(c, dir))
+	c.Assert(err, jc.ErrorIsNil)
+	missing, err := archive.MissingFiles()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(missing, jc.DeepEquals, []string{"actions/snapshot"})
+
+	c.Assert(readCharmDir(c, "metered").MissingFiles(), jc.DeepEquals, []string{"hooks/collect-metrics"})
+}
+
+func (s *FilesSuite) TestMissingFilesMetrics(c *gc.C) {
+	path := cloneDir(c, charmDirPath(c, "dummy"))
+	err := os.MkdirAll(filepath.Join(path, "actions"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(path, "actions", "snapshot"), nil, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(path, "hooks", "collect-metrics"), nil, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+
+	dir, err := charm.ReadCharmDir(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(dir.MissingFiles(), jc.DeepEquals, []string{"metrics.yaml"})
+}
+
+func (s *FilesSuite) TestMissingFilesDispatch(c *gc.C) {
+	path := cloneDir(c, charmDirPath(c, "metered"))
+	err := ioutil.WriteFile(filepath.Join(path, "dispatch"), nil, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	dir, err := charm.ReadCharmDir(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(dir.MissingFiles(), gc.HasLen, 0)
+}
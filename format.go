@@ -0,0 +1,254 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/juju/errors"
+)
+
+// WriteMeta writes a human readable description of the charm metadata
+// to w, for commands that show charms: its name and summary, followed
+// by tables of its endpoints, storage and, if config is not nil,
+// configuration options. Empty tables are omitted.
+func WriteMeta(w io.Writer, meta *Meta, config *Config) error {
+	tw := newTextTable(w)
+	tw.field("Name", meta.Name)
+	tw.field("Summary", meta.Summary)
+	if meta.Subordinate {
+		tw.field("Subordinate", "yes")
+	}
+	if series := meta.ComputedSeries(); len(series) > 0 {
+		tw.field("Series", strings.Join(series, ", "))
+	}
+
+	var endpoints [][]string
+	for _, rel := range meta.SortedEndpoints() {
+		endpoints = append(endpoints, []string{
+			relationSection(rel.Role), rel.Name, strings.Join(rel.Interfaces(), ", "), string(rel.Scope),
+		})
+	}
+	tw.table("Endpoints", []string{"ROLE", "NAME", "INTERFACE", "SCOPE"}, endpoints)
+
+	var storage [][]string
+	for _, name := range sortedNames(meta.Storage) {
+		store := meta.Storage[name]
+		storage = append(storage, []string{
+			name, string(store.Type), storageCount(store), store.Location, firstLine(store.Description),
+		})
+	}
+	tw.table("Storage", []string{"NAME", "TYPE", "COUNT", "LOCATION", "DESCRIPTION"}, storage)
+
+	if config != nil {
+		var options [][]string
+		for _, name := range sortedNames(config.Options) {
+			option := config.Options[name]
+			def := ""
+			if list, ok := option.Default.([]string); ok {
+				def = strings.Join(list, ", ")
+			} else if option.Default != nil {
+				def = fmt.Sprint(option.Default)
+			}
+			options = append(options, []string{name, option.Type, firstLine(def), firstLine(option.Description)})
+		}
+		tw.table("Options", []string{"NAME", "TYPE", "DEFAULT", "DESCRIPTION"}, options)
+	}
+	return errors.Trace(tw.Flush())
+}
+
+// WriteBundleData writes a human readable description of the bundle to
+// w, for commands that show bundles: tables of its applications,
+// machines, offers it consumes and relations. Empty tables are omitted.
+func WriteBundleData(w io.Writer, data *BundleData) error {
+	tw := newTextTable(w)
+	if data.Type != "" {
+		tw.field("Type", data.Type)
+	}
+	if data.Series != "" {
+		tw.field("Series", data.Series)
+	}
+	if data.Description != "" {
+		tw.field("Description", firstLine(data.Description))
+	}
+
+	var applications [][]string
+	for _, name := range sortedNames(data.Applications) {
+		app := data.Applications[name]
+		if app == nil {
+			continue
+		}
+		units := app.NumUnits
+		if app.Scale_ > units {
+			units = app.Scale_
+		}
+		exposed := ""
+		if app.Expose || len(app.ExposedEndpoints) > 0 {
+			exposed = "yes"
+		}
+		applications = append(applications, []string{
+			name, app.Charm, app.Channel, strconv.Itoa(units), strings.Join(app.To, ", "), exposed,
+		})
+	}
+	tw.table("Applications", []string{"NAME", "CHARM", "CHANNEL", "UNITS", "TO", "EXPOSED"}, applications)
+
+	var machines [][]string
+	for _, id := range sortedMachineIds(data.Machines) {
+		var series, constraints string
+		if machine := data.Machines[id]; machine != nil {
+			series, constraints = machine.Series, machine.Constraints
+		}
+		machines = append(machines, []string{id, series, constraints})
+	}
+	tw.table("Machines", []string{"ID", "SERIES", "CONSTRAINTS"}, machines)
+
+	var saas [][]string
+	for _, name := range sortedNames(data.Saas) {
+		url := ""
+		if offer := data.Saas[name]; offer != nil {
+			url = offer.URL
+		}
+		saas = append(saas, []string{name, url})
+	}
+	tw.table("Saas", []string{"NAME", "URL"}, saas)
+
+	var relations [][]string
+	for _, rel := range data.Relations {
+		if len(rel) == 2 {
+			relations = append(relations, []string{rel[0], rel[1]})
+		}
+	}
+	tw.table("Relations", []string{"FROM", "TO"}, relations)
+	return errors.Trace(tw.Flush())
+}
+
+// textTable writes fields and tables aligned in columns.
+type textTable struct {
+	*tabwriter.Writer
+	w     io.Writer
+	buf   bytes.Buffer
+	empty bool
+}
+
+func newTextTable(w io.Writer) *textTable {
+	t := &textTable{
+		w:     w,
+		empty: true,
+	}
+	t.Writer = tabwriter.NewWriter(&t.buf, 0, 8, 2, ' ', 0)
+	return t
+}
+
+// Flush writes the aligned text, without the spaces that pad empty
+// trailing cells.
+func (t *textTable) Flush() error {
+	if err := t.Writer.Flush(); err != nil {
+		return errors.Trace(err)
+	}
+	lines := strings.SplitAfter(t.buf.String(), "\n")
+	for i, line := range lines {
+		if strings.HasSuffix(line, "\n") {
+			lines[i] = strings.TrimRight(line[:len(line)-1], " ") + "\n"
+		}
+	}
+	t.buf.Reset()
+	_, err := io.WriteString(t.w, strings.Join(lines, ""))
+	return errors.Trace(err)
+}
+
+// field writes a line holding the named value. Fields written in
+// sequence are aligned.
+func (t *textTable) field(name, value string) {
+	fmt.Fprintf(t, "%s:\t%s\n", name, value)
+	t.empty = false
+}
+
+// table writes the titled table, preceded by a blank line, unless it
+// has no rows.
+func (t *textTable) table(title string, header []string, rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+	if !t.empty {
+		fmt.Fprintln(t)
+	}
+	fmt.Fprintf(t, "%s:\n", title)
+	fmt.Fprintln(t, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(t, strings.Join(row, "\t"))
+	}
+	t.empty = false
+}
+
+// relationSection returns the name of the metadata section declaring
+// relations with the given role.
+func relationSection(role RelationRole) string {
+	switch role {
+	case RoleProvider:
+		return "provides"
+	case RoleRequirer:
+		return "requires"
+	case RolePeer:
+		return "peers"
+	}
+	return string(role)
+}
+
+// storageCount returns the range of the number of instances of the
+// store, such as "1", "0-3" or "2+".
+func storageCount(store Storage) string {
+	switch {
+	case store.CountMax < 0:
+		return strconv.Itoa(store.CountMin) + "+"
+	case store.CountMin == store.CountMax:
+		return strconv.Itoa(store.CountMin)
+	}
+	return strconv.Itoa(store.CountMin) + "-" + strconv.Itoa(store.CountMax)
+}
+
+// firstLine returns the first line of s, without surrounding white
+// space, so that multi-line text does not break table rows.
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s
+}
+
+// sortedNames returns the keys of m, a map with string keys, sorted.
+func sortedNames(m interface{}) []string {
+	keys := reflect.ValueOf(m).MapKeys()
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = key.String()
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedMachineIds returns the ids of the machines, in numerical order
+// where they are numbers.
+func sortedMachineIds(machines map[string]*MachineSpec) []string {
+	ids := make([]string, 0, len(machines))
+	for id := range machines {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, errA := strconv.Atoi(ids[i])
+		b, errB := strconv.Atoi(ids[j])
+		if errA == nil && errB == nil {
+			return a < b
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
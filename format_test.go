@@ -0,0 +1,153 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"bytes"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type FormatSuite struct{}
+
+var _ = gc.Suite(&FormatSuite{})
+
+func (s *FormatSuite) TestWriteMeta(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(`
+name: postgresql
+summary: An object-relational database.
+description: c
+series: [focal, jammy]
+provides:
+  db:
+    interface: postgresql
+    interface-aliases: [pgsql]
+requires:
+  certificates: tls-certificates
+peers:
+  replicas: pg-replicas
+storage:
+  pgdata:
+    type: filesystem
+    description: |
+      The data directory.
+      Never shrink it.
+    location: /srv/pgdata
+  wal:
+    type: block
+    multiple:
+      range: 0-
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	config, err := charm.ReadConfig(strings.NewReader(`
+options:
+  port:
+    type: int
+    default: 5432
+    description: The port to listen on.
+  admin-password:
+    type: string
+    description: The password of the administrator.
+`))
+	c.Assert(err, jc.ErrorIsNil)
+
+	var buf bytes.Buffer
+	err = charm.WriteMeta(&buf, meta, config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, `
+Name:     postgresql
+Summary:  An object-relational database.
+Series:   focal, jammy
+
+Endpoints:
+ROLE      NAME          INTERFACE          SCOPE
+provides  db            postgresql, pgsql  global
+requires  certificates  tls-certificates   global
+peers     replicas      pg-replicas        global
+
+Storage:
+NAME    TYPE        COUNT  LOCATION     DESCRIPTION
+pgdata  filesystem  1      /srv/pgdata  The data directory.
+wal     block       0+
+
+Options:
+NAME            TYPE    DEFAULT  DESCRIPTION
+admin-password  string           The password of the administrator.
+port            int     5432     The port to listen on.
+`[1:])
+}
+
+func (s *FormatSuite) TestWriteMetaMinimal(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(dummyMetadata))
+	c.Assert(err, jc.ErrorIsNil)
+	var buf bytes.Buffer
+	err = charm.WriteMeta(&buf, meta, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, "Name:     a\nSummary:  b\n")
+}
+
+func (s *FormatSuite) TestWriteBundleData(c *gc.C) {
+	data, err := charm.ReadBundleData(strings.NewReader(`
+series: focal
+description: |
+  A blog.
+  With a database.
+applications:
+  wordpress:
+    charm: wordpress
+    channel: stable
+    num_units: 2
+    to: ["1", "10"]
+    expose: true
+  mysql:
+    charm: mysql
+    num_units: 1
+    to: ["2"]
+machines:
+  "1": {}
+  "2":
+    constraints: mem=4G
+  "10":
+    series: jammy
+saas:
+  logs:
+    url: admin/logs.logging
+relations:
+  - ["wordpress:db", "mysql:server"]
+  - ["wordpress:logging", "logs"]
+`))
+	c.Assert(err, jc.ErrorIsNil)
+
+	var buf bytes.Buffer
+	err = charm.WriteBundleData(&buf, data)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, `
+Series:       focal
+Description:  A blog.
+
+Applications:
+NAME       CHARM      CHANNEL  UNITS  TO     EXPOSED
+mysql      mysql               1      2
+wordpress  wordpress  stable   2      1, 10  yes
+
+Machines:
+ID  SERIES  CONSTRAINTS
+1
+2           mem=4G
+10  jammy
+
+Saas:
+NAME  URL
+logs  admin/logs.logging
+
+Relations:
+FROM               TO
+wordpress:db       mysql:server
+wordpress:logging  logs
+`[1:])
+}
@@ -4,6 +4,10 @@
 // hooks provides types and constants that define the hooks known to Juju.
 package hooks
 
+import (
+	"strings"
+)
+
 // Kind enumerates the different kinds of hooks that exist.
 type Kind string
 
@@ -46,6 +50,11 @@ const (
 	// "shared-fs-storage-attached".
 	StorageAttached  Kind = "storage-attached"
 	StorageDetaching Kind = "storage-detaching"
+
+	// This hook requires an associated workload container. The hook file
+	// name that it represents will be prefixed by the container name; for
+	// example, "redis-pebble-ready".
+	PebbleReady Kind = "pebble-ready"
 )
 
 var unitHooks = []Kind{
@@ -99,6 +108,17 @@ func StorageHooks() []Kind {
 	return hooks
 }
 
+var workloadHooks = []Kind{
+	PebbleReady,
+}
+
+// WorkloadHooks returns all known workload container hook kinds.
+func WorkloadHooks() []Kind {
+	hooks := make([]Kind, len(workloadHooks))
+	copy(hooks, workloadHooks)
+	return hooks
+}
+
 // IsRelation returns whether the Kind represents a relation hook.
 func (kind Kind) IsRelation() bool {
 	switch kind {
@@ -116,3 +136,32 @@ func (kind Kind) IsStorage() bool {
 	}
 	return false
 }
+
+// IsWorkload returns whether the Kind represents a workload container hook.
+func (kind Kind) IsWorkload() bool {
+	return kind == PebbleReady
+}
+
+// ParseRelationHook splits a relation hook name, such as
+// "db-relation-changed", into the name of the relation and the kind
+// of relation hook. Relation names may themselves contain hyphens, so
+// the hook kind is matched as a suffix. The final result is false if
+// hookName does not name a relation hook.
+//
+// ParseRelationHook cannot tell whether the relation exists; use
+// charm.Meta.ParseRelationHook to check the name against a charm's
+// declared relations.
+func ParseRelationHook(hookName string) (string, Kind, bool) {
+	for _, kind := range relationHooks {
+		suffix := "-" + string(kind)
+		if !strings.HasSuffix(hookName, suffix) {
+			continue
+		}
+		relationName := strings.TrimSuffix(hookName, suffix)
+		if relationName == "" {
+			return "", "", false
+		}
+		return relationName, kind, true
+	}
+	return "", "", false
+}
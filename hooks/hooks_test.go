@@ -0,0 +1,28 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package hooks_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8/hooks"
+)
+
+type HooksSuite struct{}
+
+var _ = gc.Suite(&HooksSuite{})
+
+func (s *HooksSuite) TestParseRelationHook(c *gc.C) {
+	// Without a Meta, any relation name is accepted.
+	relationName, kind, ok := hooks.ParseRelationHook("my-db-relation-departed")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(relationName, gc.Equals, "my-db")
+	c.Assert(kind, gc.Equals, hooks.RelationDeparted)
+
+	_, _, ok = hooks.ParseRelationHook("install")
+	c.Assert(ok, jc.IsFalse)
+	_, _, ok = hooks.ParseRelationHook("-relation-joined")
+	c.Assert(ok, jc.IsFalse)
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"regexp"
+	"strings"
+)
+
+// validLocale matches the locales that key translations in the
+// summary-i18n and description-i18n sections of metadata.yaml: a
+// language code optionally followed by a territory or other variant,
+// such as "fr", "pt_BR" or "zh-Hant".
+var validLocale = regexp.MustCompile(`^[a-z]{2,3}([_-][A-Za-z0-9]{2,8})*$`)
+
+// IsValidLocale reports whether locale is valid as a key of the
+// translations of the charm summary and description.
+func IsValidLocale(locale string) bool {
+	return validLocale.MatchString(locale)
+}
+
+// Localized returns the summary and description of the charm in the
+// language lang, such as "pt_BR" or "pt-BR". A translation for lang
+// itself is preferred, followed by one for its language alone ("pt");
+// the untranslated Summary and Description are returned for anything
+// not translated.
+func (m Meta) Localized(lang string) (summary, description string) {
+	return localized(m.Summary, m.SummaryI18n, lang), localized(m.Description, m.DescriptionI18n, lang)
+}
+
+func localized(text string, translations map[string]string, lang string) string {
+	if len(translations) == 0 || lang == "" {
+		return text
+	}
+	lang = strings.Replace(lang, "-", "_", -1)
+	for locale, translation := range translations {
+		if strings.EqualFold(strings.Replace(locale, "-", "_", -1), lang) {
+			return translation
+		}
+	}
+	if i := strings.IndexByte(lang, '_'); i >= 0 {
+		if translation, ok := translations[strings.ToLower(lang[:i])]; ok {
+			return translation
+		}
+	}
+	return text
+}
+
+// parseTranslations returns the translations held by the given
+// summary-i18n or description-i18n section.
+func parseTranslations(input interface{}) map[string]string {
+	if input == nil {
+		return nil
+	}
+	translations := make(map[string]string)
+	for locale, translation := range input.(map[string]interface{}) {
+		translations[locale] = translation.(string)
+	}
+	return translations
+}
@@ -0,0 +1,297 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/juju/errors"
+)
+
+// InterfaceInfo describes a well-known relation interface.
+type InterfaceInfo struct {
+	// Name is the interface name as used in the provides, requires
+	// and peers sections of metadata.yaml.
+	Name string
+
+	// Description is a short human-readable summary of what the
+	// interface is used for.
+	Description string
+
+	// Endpoints holds the endpoint names charms conventionally use
+	// for the interface.
+	Endpoints []string
+
+	// Deprecated holds whether charms should stop using the
+	// interface.
+	Deprecated bool
+
+	// Replacement holds the name of the interface that charms should
+	// use instead of a deprecated one, if there is one.
+	Replacement string
+}
+
+// knownInterfacesMutex guards knownInterfaces.
+var knownInterfacesMutex sync.RWMutex
+
+// knownInterfaces holds the catalog of well-known interfaces, keyed by
+// interface name. It starts as a copy of builtinInterfaces.
+var knownInterfaces = copyInterfaceCatalog(builtinInterfaces)
+
+// builtinInterfaces holds the curated catalog of well-known interfaces
+// shipped with this package. It is never modified.
+var builtinInterfaces = map[string]InterfaceInfo{
+	"juju-info": {
+		Name:        "juju-info",
+		Description: "Implicit interface provided by every charm, exposing basic unit information.",
+		Endpoints:   []string{"juju-info"},
+	},
+	"http": {
+		Name:        "http",
+		Description: "HTTP service exposing a hostname and port.",
+		Endpoints:   []string{"website", "web", "url"},
+	},
+	"mysql": {
+		Name:        "mysql",
+		Description: "MySQL database connection details.",
+		Endpoints:   []string{"db", "database", "server"},
+	},
+	"mysql-root": {
+		Name:        "mysql-root",
+		Description: "MySQL database connection details with root privileges.",
+		Endpoints:   []string{"db-admin"},
+	},
+	"pgsql": {
+		Name:        "pgsql",
+		Description: "PostgreSQL database connection details.",
+		Endpoints:   []string{"db", "db-admin"},
+	},
+	"mongodb": {
+		Name:        "mongodb",
+		Description: "MongoDB database connection details.",
+		Endpoints:   []string{"database", "db"},
+	},
+	"memcache": {
+		Name:        "memcache",
+		Description: "Memcached cache server address.",
+		Endpoints:   []string{"cache"},
+	},
+	"redis": {
+		Name:        "redis",
+		Description: "Redis server address.",
+		Endpoints:   []string{"redis", "db"},
+	},
+	"rabbitmq": {
+		Name:        "rabbitmq",
+		Description: "AMQP message broker credentials.",
+		Endpoints:   []string{"amqp"},
+	},
+	"nrpe-external-master": {
+		Name:        "nrpe-external-master",
+		Description: "Nagios NRPE checks exported to an external monitoring host.",
+		Endpoints:   []string{"nrpe-external-master"},
+	},
+	"monitors": {
+		Name:        "monitors",
+		Description: "Monitoring targets and checks.",
+		Endpoints:   []string{"monitors"},
+	},
+	"prometheus": {
+		Name:        "prometheus",
+		Description: "Prometheus scrape target.",
+		Endpoints:   []string{"prometheus", "scrape"},
+	},
+	"syslog": {
+		Name:        "syslog",
+		Description: "Remote syslog forwarding.",
+		Endpoints:   []string{"logging"},
+	},
+	"ntp": {
+		Name:        "ntp",
+		Description: "NTP time source.",
+		Endpoints:   []string{"ntp"},
+	},
+	"keystone": {
+		Name:        "keystone",
+		Description: "OpenStack identity service endpoints.",
+		Endpoints:   []string{"identity-service"},
+	},
+	"elasticsearch": {
+		Name:        "elasticsearch",
+		Description: "Elasticsearch cluster address.",
+		Endpoints:   []string{"client"},
+	},
+	"kafka": {
+		Name:        "kafka",
+		Description: "Kafka broker addresses.",
+		Endpoints:   []string{"kafka"},
+	},
+	"zookeeper": {
+		Name:        "zookeeper",
+		Description: "ZooKeeper ensemble addresses.",
+		Endpoints:   []string{"zookeeper"},
+	},
+	"etcd": {
+		Name:        "etcd",
+		Description: "etcd cluster connection details.",
+		Endpoints:   []string{"db", "etcd"},
+	},
+	"tls-certificates": {
+		Name:        "tls-certificates",
+		Description: "TLS certificate requests and issued certificates.",
+		Endpoints:   []string{"certificates"},
+	},
+}
+
+// KnownInterfaces returns a snapshot of the catalog of well-known
+// interfaces, sorted by name, which later deprecations do not affect.
+func KnownInterfaces() []InterfaceInfo {
+	knownInterfacesMutex.RLock()
+	defer knownInterfacesMutex.RUnlock()
+	result := make([]InterfaceInfo, 0, len(knownInterfaces))
+	for _, info := range knownInterfaces {
+		result = append(result, copyInterfaceInfo(info))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// LookupInterface returns the catalog entry for the named interface,
+// and whether it was found.
+func LookupInterface(name string) (InterfaceInfo, bool) {
+	knownInterfacesMutex.RLock()
+	defer knownInterfacesMutex.RUnlock()
+	info, ok := knownInterfaces[name]
+	if !ok {
+		return InterfaceInfo{}, false
+	}
+	return copyInterfaceInfo(info), true
+}
+
+// DeprecateInterface marks the named interface as deprecated, in favour
+// of replacement if that is not empty. An interface missing from the
+// catalog is added to it, so that deprecations can be declared for
+// interfaces that were never well-known.
+func DeprecateInterface(name, replacement string) error {
+	if name == "" {
+		return errors.NotValidf("empty interface name")
+	}
+	if replacement == name {
+		return errors.NotValidf("interface %q replaced by itself", name)
+	}
+	knownInterfacesMutex.Lock()
+	defer knownInterfacesMutex.Unlock()
+	info, ok := knownInterfaces[name]
+	if !ok {
+		info = InterfaceInfo{Name: name}
+	}
+	info.Deprecated = true
+	info.Replacement = replacement
+	knownInterfaces[name] = info
+	return nil
+}
+
+// ResetKnownInterfaces discards the deprecations added by
+// DeprecateInterface, restoring the catalog shipped with this package.
+// It is meant for tests of code that deprecates interfaces at runtime.
+func ResetKnownInterfaces() {
+	knownInterfacesMutex.Lock()
+	defer knownInterfacesMutex.Unlock()
+	knownInterfaces = copyInterfaceCatalog(builtinInterfaces)
+}
+
+func copyInterfaceCatalog(catalog map[string]InterfaceInfo) map[string]InterfaceInfo {
+	result := make(map[string]InterfaceInfo, len(catalog))
+	for name, info := range catalog {
+		result[name] = copyInterfaceInfo(info)
+	}
+	return result
+}
+
+// InterfaceDeprecations returns a warning for each relation of the
+// charm that uses a deprecated interface, naming its replacement.
+func (m Meta) InterfaceDeprecations() []string {
+	var warnings []string
+	relations := m.CombinedRelations()
+	for _, name := range sortedRelationNames(relations) {
+		iface := relations[name].Interface
+		info, ok := LookupInterface(iface)
+		if !ok || !info.Deprecated {
+			continue
+		}
+		if info.Replacement == "" {
+			warnings = append(warnings, fmt.Sprintf(
+				"charm %q relation %q uses deprecated interface %q", m.Name, name, iface))
+		} else {
+			warnings = append(warnings, fmt.Sprintf(
+				"charm %q relation %q uses deprecated interface %q; use %q instead",
+				m.Name, name, iface, info.Replacement))
+		}
+	}
+	return warnings
+}
+
+// maxInterfaceSuggestionDistance is the largest edit distance at which
+// SuggestInterface still considers a known interface a likely match.
+const maxInterfaceSuggestionDistance = 2
+
+// SuggestInterface returns the name of the well-known interface that
+// most closely resembles name, suitable for "did you mean" messages.
+// It returns false if name is itself well-known or if no known
+// interface is close enough.
+func SuggestInterface(name string) (string, bool) {
+	knownInterfacesMutex.RLock()
+	defer knownInterfacesMutex.RUnlock()
+	if _, ok := knownInterfaces[name]; ok {
+		return "", false
+	}
+	best, bestDistance := "", maxInterfaceSuggestionDistance+1
+	for candidate := range knownInterfaces {
+		d := editDistance(name, candidate)
+		if d < bestDistance || (d == bestDistance && candidate < best) {
+			best, bestDistance = candidate, d
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+	return best, true
+}
+
+func copyInterfaceInfo(info InterfaceInfo) InterfaceInfo {
+	info.Endpoints = append([]string(nil), info.Endpoints...)
+	return info
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, minInt(cur[j-1]+1, prev[j-1]+cost))
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"sort"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type InterfacesSuite struct{}
+
+var _ = gc.Suite(&InterfacesSuite{})
+
+func (s *InterfacesSuite) TestKnownInterfacesSorted(c *gc.C) {
+	infos := charm.KnownInterfaces()
+	c.Assert(infos, gc.Not(gc.HasLen), 0)
+	c.Assert(sort.SliceIsSorted(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	}), jc.IsTrue)
+	for _, info := range infos {
+		c.Check(info.Name, gc.Not(gc.Equals), "")
+		c.Check(info.Description, gc.Not(gc.Equals), "")
+	}
+}
+
+func (s *InterfacesSuite) TestLookupInterface(c *gc.C) {
+	info, ok := charm.LookupInterface("http")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(info.Name, gc.Equals, "http")
+	c.Assert(info.Endpoints, jc.DeepEquals, []string{"website", "web", "url"})
+
+	// Mutating the result must not affect the catalog.
+	info.Endpoints[0] = "mutated"
+	info, _ = charm.LookupInterface("http")
+	c.Assert(info.Endpoints[0], gc.Equals, "website")
+
+	_, ok = charm.LookupInterface("no-such-interface")
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *InterfacesSuite) TestSuggestInterface(c *gc.C) {
+	for i, test := range []struct {
+		name    string
+		suggest string
+		ok      bool
+	}{
+		{name: "htp", suggest: "http", ok: true},
+		{name: "mysq", suggest: "mysql", ok: true},
+		{name: "pgsq1", suggest: "pgsql", ok: true},
+		{name: "http"},
+		{name: "something-entirely-different"},
+	} {
+		c.Logf("test %d: %q", i, test.name)
+		suggest, ok := charm.SuggestInterface(test.name)
+		c.Check(ok, gc.Equals, test.ok)
+		c.Check(suggest, gc.Equals, test.suggest)
+	}
+}
+
+func (s *InterfacesSuite) TestDeprecateInterface(c *gc.C) {
+	defer charm.SaveKnownInterfaces()()
+
+	err := charm.DeprecateInterface("pgsql", "postgresql_client")
+	c.Assert(err, jc.ErrorIsNil)
+	info, ok := charm.LookupInterface("pgsql")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(info.Deprecated, jc.IsTrue)
+	c.Assert(info.Replacement, gc.Equals, "postgresql_client")
+	c.Assert(info.Description, gc.Not(gc.Equals), "")
+
+	err = charm.DeprecateInterface("legacy-thing", "")
+	c.Assert(err, jc.ErrorIsNil)
+	info, ok = charm.LookupInterface("legacy-thing")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(info, jc.DeepEquals, charm.InterfaceInfo{Name: "legacy-thing", Deprecated: true})
+
+	err = charm.DeprecateInterface("a", "a")
+	c.Assert(err, gc.ErrorMatches, `interface "a" replaced by itself not valid`)
+	err = charm.DeprecateInterface("", "a")
+	c.Assert(err, gc.ErrorMatches, `empty interface name not valid`)
+}
+
+func (s *InterfacesSuite) TestResetKnownInterfaces(c *gc.C) {
+	defer charm.SaveKnownInterfaces()()
+	before := charm.KnownInterfaces()
+
+	err := charm.DeprecateInterface("pgsql", "postgresql_client")
+	c.Assert(err, jc.ErrorIsNil)
+	err = charm.DeprecateInterface("legacy-thing", "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charm.KnownInterfaces(), gc.HasLen, len(before)+1)
+
+	charm.ResetKnownInterfaces()
+	c.Assert(charm.KnownInterfaces(), jc.DeepEquals, before)
+	_, ok := charm.LookupInterface("legacy-thing")
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *InterfacesSuite) TestInterfaceDeprecations(c *gc.C) {
+	defer charm.SaveKnownInterfaces()()
+	c.Assert(charm.DeprecateInterface("pgsql", "postgresql_client"), jc.ErrorIsNil)
+	c.Assert(charm.DeprecateInterface("legacy-thing", ""), jc.ErrorIsNil)
+
+	meta, err := charm.ReadMeta(strings.NewReader(`
+name: a
+summary: b
+description: c
+requires:
+  db: pgsql
+  old: legacy-thing
+  web: http
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.InterfaceDeprecations(), jc.DeepEquals, []string{
+		`charm "a" relation "db" uses deprecated interface "pgsql"; use "postgresql_client" instead`,
+		`charm "a" relation "old" uses deprecated interface "legacy-thing"`,
+	})
+}
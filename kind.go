@@ -0,0 +1,131 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+)
+
+// Kind identifies what a path or archive holds: a charm or a bundle,
+// as a directory or as an archive.
+type Kind int
+
+// The kinds reported by DetectKind.
+const (
+	// KindUnknown is reported for anything that is neither a charm nor
+	// a bundle.
+	KindUnknown Kind = iota
+	KindCharmDir
+	KindCharmArchive
+	KindBundleDir
+	KindBundleArchive
+)
+
+var kindNames = map[Kind]string{
+	KindUnknown:       "unknown",
+	KindCharmDir:      "charm directory",
+	KindCharmArchive:  "charm archive",
+	KindBundleDir:     "bundle directory",
+	KindBundleArchive: "bundle archive",
+}
+
+// String returns a description of the kind, such as "charm archive".
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// IsCharm reports whether the kind is a charm directory or archive.
+func (k Kind) IsCharm() bool {
+	return k == KindCharmDir || k == KindCharmArchive
+}
+
+// IsBundle reports whether the kind is a bundle directory or archive.
+func (k Kind) IsBundle() bool {
+	return k == KindBundleDir || k == KindBundleArchive
+}
+
+// DetectKind reports whether path holds a charm or a bundle, as a
+// directory or as an archive, by looking for metadata.yaml and
+// bundle.yaml at its root rather than at its name. Anything else,
+// including a file that is not a zip archive and a directory or archive
+// holding both files, is reported as KindUnknown. An error is returned
+// only if path cannot be read; as with ReadCharm, the error from
+// os.Stat is returned unchanged.
+func DetectKind(path string) (Kind, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return KindUnknown, err
+	}
+	if info.IsDir() {
+		isCharm, err := isRegularFile(filepath.Join(path, MetadataFile))
+		if err != nil {
+			return KindUnknown, errors.Trace(err)
+		}
+		isBundle, err := isRegularFile(filepath.Join(path, "bundle.yaml"))
+		if err != nil {
+			return KindUnknown, errors.Trace(err)
+		}
+		return kindFromContents(isCharm, isBundle, KindCharmDir, KindBundleDir), nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return KindUnknown, errors.Trace(err)
+	}
+	defer f.Close()
+	return DetectArchiveKind(f, info.Size())
+}
+
+// DetectArchiveKind reports whether r, holding size bytes, is a charm
+// archive or a bundle archive, as DetectKind does for files.
+func DetectArchiveKind(r io.ReaderAt, size int64) (Kind, error) {
+	zipr, err := zip.NewReader(r, size)
+	if err != nil {
+		// Not a zip archive, so neither a charm nor a bundle.
+		return KindUnknown, nil
+	}
+	var isCharm, isBundle bool
+	for _, f := range zipr.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		switch f.Name {
+		case MetadataFile:
+			isCharm = true
+		case "bundle.yaml":
+			isBundle = true
+		}
+	}
+	return kindFromContents(isCharm, isBundle, KindCharmArchive, KindBundleArchive), nil
+}
+
+func kindFromContents(isCharm, isBundle bool, charmKind, bundleKind Kind) Kind {
+	switch {
+	case isCharm && !isBundle:
+		return charmKind
+	case isBundle && !isCharm:
+		return bundleKind
+	}
+	return KindUnknown
+}
+
+// isRegularFile reports whether path names a regular file, following
+// symbolic links.
+func isRegularFile(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return info.Mode().IsRegular(), nil
+}
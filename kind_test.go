@@ -0,0 +1,65 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type KindSuite struct{}
+
+var _ = gc.Suite(&KindSuite{})
+
+func (s *KindSuite) TestDetectKind(c *gc.C) {
+	empty := c.MkDir()
+	notZip := filepath.Join(c.MkDir(), "charm.zip")
+	err := ioutil.WriteFile(notZip, []byte("name: a\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	both := cloneDir(c, charmDirPath(c, "dummy"))
+	err = ioutil.WriteFile(filepath.Join(both, "bundle.yaml"), []byte("applications: {}\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for i, test := range []struct {
+		path   string
+		expect charm.Kind
+	}{
+		{charmDirPath(c, "dummy"), charm.KindCharmDir},
+		{archivePath(c, readCharmDir(c, "dummy")), charm.KindCharmArchive},
+		{bundleDirPath(c, "wordpress-simple"), charm.KindBundleDir},
+		{archivePath(c, readBundleDir(c, "wordpress-simple")), charm.KindBundleArchive},
+		{empty, charm.KindUnknown},
+		{notZip, charm.KindUnknown},
+		{both, charm.KindUnknown},
+	} {
+		c.Logf("test %d: %s", i, test.path)
+		kind, err := charm.DetectKind(test.path)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(kind, gc.Equals, test.expect)
+	}
+}
+
+func (s *KindSuite) TestDetectKindMissing(c *gc.C) {
+	kind, err := charm.DetectKind(filepath.Join(c.MkDir(), "missing"))
+	c.Check(err, jc.Satisfies, os.IsNotExist)
+	c.Check(kind, gc.Equals, charm.KindUnknown)
+}
+
+func (s *KindSuite) TestDetectArchiveKind(c *gc.C) {
+	data, err := ioutil.ReadFile(archivePath(c, readBundleDir(c, "wordpress-simple")))
+	c.Assert(err, jc.ErrorIsNil)
+	kind, err := charm.DetectArchiveKind(bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(kind, gc.Equals, charm.KindBundleArchive)
+	c.Check(kind.IsBundle(), jc.IsTrue)
+	c.Check(kind.IsCharm(), jc.IsFalse)
+	c.Check(kind.String(), gc.Equals, "bundle archive")
+}
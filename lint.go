@@ -0,0 +1,202 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// LintMeta returns warnings about the charm's metadata that do not
+// prevent it from being read, but that authors should address: series
+// past their standard support at the given time, deprecated categories,
+// tags outside the canonical vocabulary, deprecated relation interfaces,
+// ineffective peer relation limits and optional flags, and the
+// deprecation of the charm itself. It is meant for the same pack time
+// tools as ReadMetaStrict.
+func LintMeta(meta *Meta, now time.Time) []string {
+	warnings := meta.DeprecationWarnings()
+	warnings = append(warnings, meta.SeriesWarnings(now)...)
+	_, categoryWarnings := meta.CanonicalTags()
+	warnings = append(warnings, categoryWarnings...)
+	warnings = append(warnings, meta.TagSuggestions()...)
+	warnings = append(warnings, meta.InterfaceDeprecations()...)
+	warnings = append(warnings, meta.LimitWarnings()...)
+	return append(warnings, meta.OptionalWarnings()...)
+}
+
+// LintBundle returns warnings about the bundle that do not prevent it
+// from being deployed, but that its authors should address: required
+// relations of its applications that are not optional but that the
+// bundle does not relate. The charms map holds the charm used by each
+// application, keyed by charm URL as for BundleData.VerifyWithCharms;
+// applications whose charm is missing from it are not checked.
+func LintBundle(bd *BundleData, charms map[string]Charm) []string {
+	getMeta := func(appName string) (*Meta, error) {
+		app := bd.Applications[appName]
+		if app == nil || charms[app.Charm] == nil {
+			return nil, errors.NotFoundf("charm for application %q", appName)
+		}
+		return charms[app.Charm].Meta(), nil
+	}
+	related := make(map[endpoint]bool)
+	for _, relPair := range bd.Relations {
+		if len(relPair) != 2 {
+			continue
+		}
+		ep0, err0 := parseEndpoint(relPair[0])
+		ep1, err1 := parseEndpoint(relPair[1])
+		if err0 != nil || err1 != nil {
+			continue
+		}
+		if ep0, ep1, err := inferEndpoints(ep0, ep1, getMeta); err == nil {
+			related[ep0] = true
+			related[ep1] = true
+		}
+	}
+	var warnings []string
+	for _, appName := range sortedNames(bd.Applications) {
+		meta, err := getMeta(appName)
+		if err != nil {
+			continue
+		}
+		required := meta.RequiredRelations()
+		for _, name := range sortedRelationNames(required) {
+			if !related[endpoint{application: appName, relation: name}] {
+				warnings = append(warnings, fmt.Sprintf(
+					"application %q does not relate required endpoint %q of charm %q",
+					appName, name, bd.Applications[appName].Charm))
+			}
+		}
+	}
+	return warnings
+}
+
+// LintProfile names a set of rules used by LintCharmDir.
+type LintProfile string
+
+// LintSourceCharm checks charm directories laid out as source trees,
+// such as those built with charmcraft: charm code in src/, libraries in
+// lib/ and Python dependencies in requirements.txt, with the hooks and
+// other built artifacts generated when the charm is packed.
+const LintSourceCharm LintProfile = "source-charm"
+
+// IsSourceCharmDir reports whether the charm directory at path is laid
+// out as a source tree, with a src/ directory and a requirements.txt
+// file.
+func IsSourceCharmDir(path string) bool {
+	return isDir(filepath.Join(path, "src")) && isFile(filepath.Join(path, "requirements.txt"))
+}
+
+// builtArtifacts holds the files and directories that are generated
+// when a source charm is built, and that should not be kept in its
+// source tree.
+var builtArtifacts = []struct {
+	name   string
+	reason string
+}{
+	{"build", "is the build directory"},
+	{"dispatch", "is generated when the charm is built"},
+	{"venv", "holds dependencies installed when the charm is built"},
+}
+
+// LintCharmDir returns warnings about the layout of the charm directory
+// at path, using the rules of the given profile.
+func LintCharmDir(path string, profile LintProfile) ([]string, error) {
+	switch profile {
+	case LintSourceCharm:
+		return lintSourceCharm(path)
+	}
+	return nil, errors.NotValidf("lint profile %q", profile)
+}
+
+func lintSourceCharm(path string) ([]string, error) {
+	if !isDir(path) {
+		return nil, errors.NotFoundf("charm directory %q", path)
+	}
+	var warnings []string
+	warn := func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+	hasSrc := isDir(filepath.Join(path, "src"))
+	if !hasSrc {
+		warn("source charm has no src/ directory")
+	}
+	if !isFile(filepath.Join(path, "requirements.txt")) {
+		warn("source charm has no requirements.txt")
+	}
+	hasCharmPy := isFile(filepath.Join(path, "src", "charm.py"))
+	if hasSrc && !hasCharmPy {
+		warn("source charm has no src/charm.py")
+	}
+	if lib := filepath.Join(path, "lib"); exists(lib) && !isDir(lib) {
+		warn("lib is not a directory")
+	}
+	for _, artifact := range builtArtifacts {
+		if exists(filepath.Join(path, artifact.name)) {
+			warn("%s %s; remove it from the source tree", artifact.name, artifact.reason)
+		}
+	}
+	archives, err := filepath.Glob(filepath.Join(path, "*.charm"))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, archive := range archives {
+		warn("%s is a built charm archive; remove it from the source tree", filepath.Base(archive))
+	}
+	if hasCharmPy {
+		// Hooks in a source charm, if any, must be links to the
+		// charm code, or they will go stale as it changes.
+		stale, err := staleHooks(path)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, hook := range stale {
+			warn("hooks/%s is a copy rather than a link to src/charm.py, and may be stale", hook)
+		}
+	}
+	return warnings, nil
+}
+
+// staleHooks returns the names of the files in the hooks directory of
+// the charm at path that are regular files rather than symbolic links.
+func staleHooks(path string) ([]string, error) {
+	infos, err := ioutil.ReadDir(filepath.Join(path, "hooks"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stale []string
+	for _, info := range infos {
+		if info.Mode().IsRegular() && !strings.HasPrefix(info.Name(), ".") {
+			stale = append(stale, info.Name())
+		}
+	}
+	sort.Strings(stale)
+	return stale, nil
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
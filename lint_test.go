@@ -0,0 +1,232 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type LintSuite struct{}
+
+var _ = gc.Suite(&LintSuite{})
+
+func (s *LintSuite) TestLintMeta(c *gc.C) {
+	meta, err := charm.ReadMetaStrict(strings.NewReader(`
+name: a
+summary: b
+description: c
+series: [precise, trusty]
+categories: [database]
+tags: [db]
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	now := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	c.Assert(charm.LintMeta(meta, now), jc.DeepEquals, []string{
+		`charm "a" only declares end-of-life series: precise (eol), trusty (eol)`,
+		`charm "a" uses deprecated category "database"; use tag "databases" instead`,
+		`charm "a" tag "db" could be replaced by canonical tag "databases"`,
+	})
+}
+
+func (s *LintSuite) TestLintMetaDeprecatedInterface(c *gc.C) {
+	defer charm.SaveKnownInterfaces()()
+	c.Assert(charm.DeprecateInterface("pgsql", "postgresql_client"), jc.ErrorIsNil)
+	meta := &charm.Meta{
+		Name:   "a",
+		Series: []string{"noble"},
+		Requires: map[string]charm.Relation{
+			"db": {Name: "db", Role: charm.RoleRequirer, Interface: "pgsql", Scope: charm.ScopeGlobal},
+		},
+	}
+	now := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	c.Assert(charm.LintMeta(meta, now), jc.DeepEquals, []string{
+		`charm "a" relation "db" uses deprecated interface "pgsql"; use "postgresql_client" instead`,
+	})
+}
+
+func (s *LintSuite) TestLintMetaPeerLimit(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(`
+name: a
+summary: b
+description: c
+series: [noble]
+peers:
+  cluster:
+    interface: a-cluster
+    limit: 3
+  replicas:
+    interface: a-replicas
+    limit: 1
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	now := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	c.Assert(charm.LintMeta(meta, now), jc.DeepEquals, []string{
+		`charm "a" peer relation "cluster" has limit 3, but a peer endpoint takes part in one relation only`,
+	})
+}
+
+func (s *LintSuite) TestLintMetaDeprecated(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(`
+name: a
+summary: b
+description: c
+series: [noble]
+deprecated:
+  message: Unmaintained.
+superseded-by: b
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	now := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	c.Assert(charm.LintMeta(meta, now), jc.DeepEquals, []string{
+		`charm "a" is deprecated: Unmaintained.; use "b" instead`,
+	})
+}
+
+func (s *LintSuite) TestLintMetaOptional(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(`
+name: a
+summary: b
+description: c
+series: [noble]
+provides:
+  dso:
+    interface: terracotta
+    optional: true
+requires:
+  db:
+    interface: mysql
+    optional: true
+peers:
+  ring:
+    interface: a-ring
+    optional: true
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	now := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	c.Assert(charm.LintMeta(meta, now), jc.DeepEquals, []string{
+		`charm "a" provider relation "dso" is marked optional, but only required relations can be optional`,
+		`charm "a" peer relation "ring" is marked optional, but only required relations can be optional`,
+	})
+}
+
+func (s *LintSuite) TestLintBundle(c *gc.C) {
+	wordpress, err := charm.ReadMeta(strings.NewReader(`
+name: wordpress
+summary: b
+description: c
+provides:
+  website: http
+requires:
+  db: mysql
+  cache:
+    interface: memcache
+    optional: true
+  logging: syslog
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	mysql, err := charm.ReadMeta(strings.NewReader(`
+name: mysql
+summary: b
+description: c
+provides:
+  server: mysql
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	bd, err := charm.ReadBundleData(strings.NewReader(`
+applications:
+  wordpress:
+    charm: wordpress
+  mysql:
+    charm: mysql
+  unknown:
+    charm: unknown
+relations:
+  - [wordpress, mysql]
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	charms := map[string]charm.Charm{
+		"wordpress": testCharmImpl{meta: wordpress},
+		"mysql":     testCharmImpl{meta: mysql},
+	}
+	c.Assert(charm.LintBundle(bd, charms), jc.DeepEquals, []string{
+		`application "wordpress" does not relate required endpoint "logging" of charm "wordpress"`,
+	})
+}
+
+func (s *LintSuite) TestLintMetaClean(c *gc.C) {
+	meta := &charm.Meta{
+		Name:   "a",
+		Series: []string{"noble"},
+		Tags:   []string{"databases"},
+	}
+	now := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	c.Assert(charm.LintMeta(meta, now), gc.HasLen, 0)
+}
+
+func (s *LintSuite) writeFiles(c *gc.C, dir string, files ...string) {
+	for _, file := range files {
+		path := filepath.Join(dir, file)
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		c.Assert(err, jc.ErrorIsNil)
+		err = ioutil.WriteFile(path, nil, 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *LintSuite) TestLintSourceCharmClean(c *gc.C) {
+	dir := c.MkDir()
+	s.writeFiles(c, dir, "metadata.yaml", "requirements.txt", "src/charm.py", "lib/charms/x/v0/y.py")
+	err := os.Mkdir(filepath.Join(dir, "hooks"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.Symlink("../src/charm.py", filepath.Join(dir, "hooks", "install"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(charm.IsSourceCharmDir(dir), jc.IsTrue)
+	warnings, err := charm.LintCharmDir(dir, charm.LintSourceCharm)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(warnings, gc.HasLen, 0)
+}
+
+func (s *LintSuite) TestLintSourceCharmBuiltArtifacts(c *gc.C) {
+	dir := c.MkDir()
+	s.writeFiles(c, dir,
+		"metadata.yaml", "requirements.txt", "src/charm.py",
+		"hooks/install", "hooks/start", "dispatch", "venv/ops/__init__.py", "a_ubuntu-22.04-amd64.charm",
+	)
+	warnings, err := charm.LintCharmDir(dir, charm.LintSourceCharm)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(warnings, jc.DeepEquals, []string{
+		"dispatch is generated when the charm is built; remove it from the source tree",
+		"venv holds dependencies installed when the charm is built; remove it from the source tree",
+		"a_ubuntu-22.04-amd64.charm is a built charm archive; remove it from the source tree",
+		"hooks/install is a copy rather than a link to src/charm.py, and may be stale",
+		"hooks/start is a copy rather than a link to src/charm.py, and may be stale",
+	})
+}
+
+func (s *LintSuite) TestLintSourceCharmMissingFiles(c *gc.C) {
+	dir := c.MkDir()
+	s.writeFiles(c, dir, "metadata.yaml", "src/other.py")
+	c.Assert(charm.IsSourceCharmDir(dir), jc.IsFalse)
+	warnings, err := charm.LintCharmDir(dir, charm.LintSourceCharm)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(warnings, jc.DeepEquals, []string{
+		"source charm has no requirements.txt",
+		"source charm has no src/charm.py",
+	})
+}
+
+func (s *LintSuite) TestLintCharmDirUnknownProfile(c *gc.C) {
+	_, err := charm.LintCharmDir(c.MkDir(), "bogus")
+	c.Assert(err, gc.ErrorMatches, `lint profile "bogus" not valid`)
+}
@@ -0,0 +1,226 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// LintFix describes a problem found in a charm directory that
+// ApplyFixes can fix.
+type LintFix struct {
+	// File holds the path, relative to the charm directory, of the
+	// file changed by the fix.
+	File string
+
+	// Problem describes the problem.
+	Problem string
+
+	// Fix describes the change that fixes it.
+	Fix string
+}
+
+// String returns a line describing the problem and its fix.
+func (f LintFix) String() string {
+	return f.File + ": " + f.Problem + "; " + f.Fix
+}
+
+// iconPlaceholder holds the icon written by ApplyFixes for charms that
+// lack one.
+const iconPlaceholder = `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+  <circle cx="50" cy="50" r="48" fill="#e95420"/>
+</svg>
+`
+
+// lintFixer holds a problem that ApplyFixes can fix.
+type lintFixer struct {
+	fix LintFix
+
+	// check reports whether the charm directory has the problem,
+	// given the top level fields of its metadata.yaml.
+	check func(dir *CharmDir, fields map[string]interface{}) bool
+
+	// apply fixes the problem, returning the new content of
+	// metadata.yaml given the current content doc.
+	apply func(dir *CharmDir, doc string) (string, error)
+}
+
+var lintFixers = []lintFixer{{
+	fix: LintFix{
+		File:    MetadataFile,
+		Problem: "categories are deprecated and tags should be unique",
+		Fix:     "replace categories and tags with the canonical tags",
+	},
+	check: func(dir *CharmDir, _ map[string]interface{}) bool {
+		meta := dir.Meta()
+		tags, _ := meta.CanonicalTags()
+		return len(meta.Categories) > 0 || !reflect.DeepEqual(tags, meta.Tags)
+	},
+	apply: func(dir *CharmDir, doc string) (string, error) {
+		tags, _ := dir.Meta().CanonicalTags()
+		replacement := ""
+		if len(tags) > 0 {
+			data, err := yaml.Marshal(map[string][]string{"tags": tags})
+			if err != nil {
+				return "", errors.Trace(err)
+			}
+			replacement = string(data)
+		}
+		doc = replaceTopLevelField(doc, "categories", "")
+		return replaceTopLevelField(doc, "tags", replacement), nil
+	},
+}, {
+	fix: LintFix{
+		File:    MetadataFile,
+		Problem: "the revision field is obsolete",
+		Fix:     "remove the revision field",
+	},
+	check: func(_ *CharmDir, fields map[string]interface{}) bool {
+		_, ok := fields["revision"]
+		return ok
+	},
+	apply: func(_ *CharmDir, doc string) (string, error) {
+		return replaceTopLevelField(doc, "revision", ""), nil
+	},
+}, {
+	fix: LintFix{
+		File:    IconFile,
+		Problem: "the charm has no icon",
+		Fix:     "add a placeholder icon",
+	},
+	check: func(dir *CharmDir, _ map[string]interface{}) bool {
+		return !exists(dir.join(IconFile))
+	},
+	apply: func(dir *CharmDir, doc string) (string, error) {
+		err := ioutil.WriteFile(dir.join(IconFile), []byte(iconPlaceholder), 0644)
+		return doc, errors.Trace(err)
+	},
+}}
+
+// FixableProblems returns the problems of the charm directory that
+// ApplyFixes can fix, without changing anything: legacy categories and
+// duplicate tags, which are replaced by the charm's canonical tags; the
+// obsolete revision field of metadata.yaml; and a missing icon, which
+// is replaced by a placeholder.
+func FixableProblems(dir *CharmDir) ([]LintFix, error) {
+	fixers, _, err := applicableFixers(dir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var fixes []LintFix
+	for _, fixer := range fixers {
+		fixes = append(fixes, fixer.fix)
+	}
+	return fixes, nil
+}
+
+// applicableFixers returns the fixers for the problems of the charm
+// directory, and the content of its metadata.yaml.
+func applicableFixers(dir *CharmDir) ([]lintFixer, string, error) {
+	data, err := ioutil.ReadFile(dir.join(MetadataFile))
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	var fields map[string]interface{}
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return nil, "", errors.Annotatef(err, "cannot parse %q", MetadataFile)
+	}
+	var fixers []lintFixer
+	for _, fixer := range lintFixers {
+		if fixer.check(dir, fields) {
+			fixers = append(fixers, fixer)
+		}
+	}
+	return fixers, string(data), nil
+}
+
+// ApplyFixes fixes the problems reported by FixableProblems, rewriting
+// the files of the charm directory, and returns the fixes that it
+// applied. Fields of metadata.yaml are rewritten in place, leaving the
+// rest of the file, including comments, unchanged. The metadata of dir
+// is updated to match.
+func ApplyFixes(dir *CharmDir) ([]LintFix, error) {
+	fixers, doc, err := applicableFixers(dir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	original := doc
+	var fixes []LintFix
+	for _, fixer := range fixers {
+		if doc, err = fixer.apply(dir, doc); err != nil {
+			return nil, errors.Annotatef(err, "cannot fix %q", fixer.fix.File)
+		}
+		fixes = append(fixes, fixer.fix)
+	}
+	if doc == original {
+		return fixes, nil
+	}
+	meta, err := ReadMeta(strings.NewReader(doc))
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot fix %q", MetadataFile)
+	}
+	info, err := os.Stat(dir.join(MetadataFile))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := ioutil.WriteFile(dir.join(MetadataFile), []byte(doc), info.Mode().Perm()); err != nil {
+		return nil, errors.Trace(err)
+	}
+	dir.meta = meta
+	return fixes, nil
+}
+
+// replaceTopLevelField returns doc, a YAML document holding a mapping,
+// with the lines of its top level field name replaced by replacement,
+// which holds complete lines. If doc has no such field, replacement is
+// appended to it.
+func replaceTopLevelField(doc, name, replacement string) string {
+	lines := strings.SplitAfter(doc, "\n")
+	start, end := -1, len(lines)
+	for i, line := range lines {
+		if start < 0 {
+			if strings.HasPrefix(line, name+":") {
+				start = i
+			}
+			continue
+		}
+		// The field continues while lines are blank, indented or
+		// hold the items of a sequence.
+		trimmed := strings.TrimSpace(line)
+		continued := trimmed == "" || line[0] == ' ' || line[0] == '\t' ||
+			(line[0] == '-' && trimmed != "---")
+		if !continued {
+			end = i
+			break
+		}
+	}
+	var buf bytes.Buffer
+	if start < 0 {
+		buf.WriteString(doc)
+		if doc != "" && !strings.HasSuffix(doc, "\n") {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(replacement)
+		return buf.String()
+	}
+	// Keep the blank lines that separate the field from the next one.
+	for end > start+1 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	for _, line := range lines[:start] {
+		buf.WriteString(line)
+	}
+	buf.WriteString(replacement)
+	for _, line := range lines[end:] {
+		buf.WriteString(line)
+	}
+	return buf.String()
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type LintFixSuite struct{}
+
+var _ = gc.Suite(&LintFixSuite{})
+
+const unfixedMetadata = `# The charm's metadata.
+name: a
+summary: b
+revision: 3
+description: |
+  tags: this is not a field
+categories:
+- database
+- misc
+
+# The tags.
+tags: [databases, db, db]
+series: [focal]
+`
+
+const fixedMetadata = `# The charm's metadata.
+name: a
+summary: b
+description: |
+  tags: this is not a field
+
+# The tags.
+tags:
+- databases
+- db
+- misc
+series: [focal]
+`
+
+func (s *LintFixSuite) readCharmDir(c *gc.C, metadata string) *charm.CharmDir {
+	dir := c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(dir, charm.MetadataFile), []byte(metadata), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	ch, err := charm.ReadCharmDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	return ch
+}
+
+func (s *LintFixSuite) TestApplyFixes(c *gc.C) {
+	dir := s.readCharmDir(c, unfixedMetadata)
+	expect := []charm.LintFix{{
+		File:    charm.MetadataFile,
+		Problem: "categories are deprecated and tags should be unique",
+		Fix:     "replace categories and tags with the canonical tags",
+	}, {
+		File:    charm.MetadataFile,
+		Problem: "the revision field is obsolete",
+		Fix:     "remove the revision field",
+	}, {
+		File:    charm.IconFile,
+		Problem: "the charm has no icon",
+		Fix:     "add a placeholder icon",
+	}}
+	fixes, err := charm.FixableProblems(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fixes, jc.DeepEquals, expect)
+
+	fixes, err = charm.ApplyFixes(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fixes, jc.DeepEquals, expect)
+	c.Assert(fixes[1].String(), gc.Equals, "metadata.yaml: the revision field is obsolete; remove the revision field")
+
+	data, err := ioutil.ReadFile(filepath.Join(dir.Path, charm.MetadataFile))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, fixedMetadata)
+	c.Assert(dir.Meta().Tags, jc.DeepEquals, []string{"databases", "db", "misc"})
+	c.Assert(dir.Meta().Categories, gc.HasLen, 0)
+	_, err = os.Stat(filepath.Join(dir.Path, charm.IconFile))
+	c.Assert(err, jc.ErrorIsNil)
+
+	fixes, err = charm.FixableProblems(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fixes, gc.HasLen, 0)
+	fixes, err = charm.ApplyFixes(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fixes, gc.HasLen, 0)
+}
+
+func (s *LintFixSuite) TestApplyFixesAddsTags(c *gc.C) {
+	dir := s.readCharmDir(c, "name: a\nsummary: b\ndescription: c\ncategories: [network]")
+	_, err := charm.ApplyFixes(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(filepath.Join(dir.Path, charm.MetadataFile))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "name: a\nsummary: b\ndescription: c\ntags:\n- networking\n")
+}
+
+func (s *LintFixSuite) TestApplyFixesIconOnly(c *gc.C) {
+	dir := s.readCharmDir(c, dummyMetadata)
+	fixes, err := charm.ApplyFixes(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fixes, gc.HasLen, 1)
+	c.Assert(fixes[0].File, gc.Equals, charm.IconFile)
+	data, err := ioutil.ReadFile(filepath.Join(dir.Path, charm.MetadataFile))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, dummyMetadata)
+}
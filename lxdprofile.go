@@ -6,7 +6,6 @@ package charm
 import (
 	"fmt"
 	"io"
-	"io/ioutil"
 	"strings"
 
 	"github.com/juju/collections/set"
@@ -33,11 +32,14 @@ func NewLXDProfile() *LXDProfile {
 // ReadLXDProfile reads in a LXDProfile from a charm's lxd-profile.yaml.
 // It is not validated at this point so that the caller can choose to override
 // any validation.
-func ReadLXDProfile(r io.Reader) (*LXDProfile, error) {
-	data, err := ioutil.ReadAll(r)
+func ReadLXDProfile(r io.Reader) (_ *LXDProfile, err error) {
+	data, err := readYAMLInput(r)
 	if err != nil {
 		return nil, err
 	}
+	defer func() {
+		reportParseFailure(LXDProfileFile, data, err)
+	}()
 	var profile LXDProfile
 	if err := yaml.Unmarshal(data, &profile); err != nil {
 		return nil, errors.Annotate(err, "failed to unmarshall lxd-profile.yaml")
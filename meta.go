@@ -6,8 +6,12 @@ package charm
 import (
 	"fmt"
 	"io"
-	"io/ioutil"
+	"math"
+	"net/url"
+	"path"
+	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -56,6 +60,11 @@ const (
 	StorageFilesystem StorageType = "filesystem"
 )
 
+// StoragePropertyTransient is the storage property indicating that
+// non-persistent storage, such as tmpfs or ephemeral instance disks,
+// is acceptable.
+const StoragePropertyTransient = "transient"
+
 // Storage represents a charm's storage requirement.
 type Storage struct {
 	// Name is the name of the store.
@@ -200,18 +209,107 @@ type Deployment struct {
 // Relation represents a single relation defined in the charm
 // metadata.yaml file.
 type Relation struct {
-	Name      string        `bson:"name"`
-	Role      RelationRole  `bson:"role"`
-	Interface string        `bson:"interface"`
-	Optional  bool          `bson:"optional"`
-	Limit     int           `bson:"limit"`
-	Scope     RelationScope `bson:"scope"`
+	Name      string       `bson:"name"`
+	Role      RelationRole `bson:"role"`
+	Interface string       `bson:"interface"`
+
+	// Optional reports whether a required relation may be left
+	// unrelated, the charm working without it. It has no effect on
+	// provided and peer relations.
+	Optional bool `bson:"optional"`
+
+	// Limit holds the largest number of relations that the endpoint
+	// may take part in, or zero if there is no limit. A required
+	// relation that is not optional should therefore take part in
+	// between one and Limit relations, and an optional one in at
+	// most Limit.
+	Limit int `bson:"limit"`
+
+	Scope RelationScope `bson:"scope"`
+
+	// Description optionally documents the purpose of the relation.
+	Description string `bson:"description,omitempty"`
+
+	// RenamedFrom optionally holds the name the relation had in
+	// earlier revisions of the charm. See CheckUpgrade.
+	RenamedFrom string `bson:"renamed-from,omitempty"`
+
+	// Ports optionally holds the ports that traffic for the relation
+	// is expected to use by default.
+	Ports []EndpointPort `bson:"ports,omitempty"`
+
+	// InterfaceAliases optionally holds alternative names of the
+	// interface that the relation also satisfies, such as the name
+	// the interface had before it was renamed. Only provided and
+	// required relations may have aliases.
+	InterfaceAliases []string `bson:"interface-aliases,omitempty"`
+}
+
+// Interfaces returns the names of the interfaces that the relation
+// satisfies: its interface followed by any aliases.
+func (r Relation) Interfaces() []string {
+	return append([]string{r.Interface}, r.InterfaceAliases...)
+}
+
+// HasInterface reports whether the relation satisfies the interface
+// with the given name, either as its interface or as an alias.
+func (r Relation) HasInterface(name string) bool {
+	for _, iface := range r.Interfaces() {
+		if iface == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sharesInterface reports whether r and other satisfy a common
+// interface.
+func (r Relation) sharesInterface(other Relation) bool {
+	for _, iface := range r.Interfaces() {
+		if other.HasInterface(iface) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxInterfaceLength holds the length of the longest valid interface
+// name.
+const maxInterfaceLength = 64
+
+var validInterface = regexp.MustCompile(`^[a-z][a-z0-9]*([_-][a-z0-9]+)*$`)
+
+// IsValidInterface reports whether name is a valid relation interface
+// name: lowercase letters and digits, starting with a letter, with
+// single hyphens or underscores between them, and at most 64 characters
+// long.
+func IsValidInterface(name string) bool {
+	return len(name) <= maxInterfaceLength && validInterface.MatchString(name)
 }
 
 // ImplementedBy returns whether the relation is implemented by the supplied charm.
+// It returns false if the relation has an unknown role or scope; use
+// CheckImplementedBy to distinguish malformed relations.
 func (r Relation) ImplementedBy(ch Charm) bool {
-	if r.IsImplicit() {
-		return true
+	ok, err := r.CheckImplementedBy(ch)
+	return ok && err == nil
+}
+
+// CheckImplementedBy returns whether the relation is implemented by the
+// supplied charm, as ImplementedBy does. It returns an error satisfying
+// errors.IsNotValid if the relation has an unknown role or scope, or a
+// negative limit, as may be the case for relations decoded from BSON or
+// JSON rather than read by ReadMeta.
+//
+// A relation with a limit is only implemented by a charm whose relation
+// allows at least as many relations; a relation without one places no
+// requirement on the charm's limit.
+func (r Relation) CheckImplementedBy(ch Charm) (bool, error) {
+	if r.Limit < 0 {
+		return false, errors.NotValidf("relation limit %d", r.Limit)
+	}
+	if r.IsImplicit() && (r.Role == RoleProvider || ch.Meta().Subordinate) {
+		return true, nil
 	}
 	var m map[string]Relation
 	switch r.Role {
@@ -222,41 +320,140 @@ func (r Relation) ImplementedBy(ch Charm) bool {
 	case RolePeer:
 		m = ch.Meta().Peers
 	default:
-		panic(fmt.Errorf("unknown relation role %q", r.Role))
+		return false, errors.NotValidf("relation role %q", r.Role)
+	}
+	if !validRelationScope(r.Scope) {
+		return false, errors.NotValidf("relation scope %q", r.Scope)
 	}
 	rel, found := m[r.Name]
-	if !found {
+	if !found || !rel.sharesInterface(r) {
+		return false, nil
+	}
+	if r.Limit > 0 && rel.Limit > 0 && rel.Limit < r.Limit {
+		return false, nil
+	}
+	if r.Scope == ScopeGlobal {
+		return rel.Scope != ScopeContainer, nil
+	}
+	return true, nil
+}
+
+// IsImplicit returns whether the relation is supplied by juju itself,
+// rather than by a charm: the juju-info provider of every charm, or the
+// container-scoped juju-info requirer of a subordinate charm. See
+// Meta.ImplicitRelations.
+func (r Relation) IsImplicit() bool {
+	if r.Name != "juju-info" || r.Interface != "juju-info" {
 		return false
 	}
-	if rel.Interface == r.Interface {
-		switch r.Scope {
-		case ScopeGlobal:
-			return rel.Scope != ScopeContainer
-		case ScopeContainer:
-			return true
-		default:
-			panic(fmt.Errorf("unknown relation scope %q", r.Scope))
+	return r.Role == RoleProvider || (r.Role == RoleRequirer && r.Scope == ScopeContainer)
+}
+
+// ImplicitRelations returns the relations that juju supplies for the
+// charm in addition to those declared in its metadata. Every charm
+// provides juju-info, and a subordinate charm that does not declare its
+// own juju-info requirer requires juju-info with container scope, so
+// that it can be attached to any principal. Note that both relations
+// are named "juju-info".
+func (m Meta) ImplicitRelations() []Relation {
+	relations := []Relation{{
+		Name:      "juju-info",
+		Role:      RoleProvider,
+		Interface: "juju-info",
+		Scope:     ScopeGlobal,
+	}}
+	if _, ok := m.Requires["juju-info"]; m.Subordinate && !ok {
+		relations = append(relations, Relation{
+			Name:      "juju-info",
+			Role:      RoleRequirer,
+			Interface: "juju-info",
+			Scope:     ScopeContainer,
+		})
+	}
+	return relations
+}
+
+// CanRelateTo reports whether r can be related to other: they must
+// satisfy a common interface, counting aliases, one must be a provider and the other a
+// requirer, and both must have a known scope. Peer relations are
+// established by juju itself and cannot be related to anything.
+//
+// A relation in which either endpoint has container scope also needs
+// one of the charms to be subordinate; CanRelate checks that as well.
+func (r Relation) CanRelateTo(other Relation) bool {
+	if !r.sharesInterface(other) {
+		return false
+	}
+	if !validRelationScope(r.Scope) || !validRelationScope(other.Scope) {
+		return false
+	}
+	switch r.Role {
+	case RoleProvider:
+		return other.Role == RoleRequirer
+	case RoleRequirer:
+		return other.Role == RoleProvider
+	}
+	return false
+}
+
+func validRelationScope(scope RelationScope) bool {
+	return scope == ScopeGlobal || scope == ScopeContainer
+}
+
+// CanRelate reports whether the endpoint named endpointA of a charm with
+// metadata metaA can be related to the endpoint named endpointB of a
+// charm with metadata metaB. The endpoints may be any provided or
+// required relation, or one of the implicit relations; it reports
+// false if either endpoint does not exist. In addition to the rules of
+// Relation.CanRelateTo, a relation where either endpoint has container
+// scope is only possible if one of the charms is subordinate.
+func CanRelate(metaA *Meta, endpointA string, metaB *Meta, endpointB string) bool {
+	for _, relA := range relatableEndpoints(metaA, endpointA) {
+		for _, relB := range relatableEndpoints(metaB, endpointB) {
+			if !relA.CanRelateTo(relB) {
+				continue
+			}
+			if relA.Scope != ScopeContainer && relB.Scope != ScopeContainer {
+				return true
+			}
+			if metaA.Subordinate || metaB.Subordinate {
+				return true
+			}
 		}
 	}
 	return false
 }
 
-// IsImplicit returns whether the relation is supplied by juju itself,
-// rather than by a charm.
-func (r Relation) IsImplicit() bool {
-	return (r.Name == "juju-info" &&
-		r.Interface == "juju-info" &&
-		r.Role == RoleProvider)
+// relatableEndpoints returns the provided, required and implicit
+// relations of meta with the given name. There may be more than one,
+// as the implicit relations of a subordinate charm share their name.
+func relatableEndpoints(meta *Meta, name string) []Relation {
+	if meta == nil {
+		return nil
+	}
+	var relations []Relation
+	if rel, ok := meta.Provides[name]; ok {
+		relations = append(relations, rel)
+	}
+	if rel, ok := meta.Requires[name]; ok {
+		relations = append(relations, rel)
+	}
+	for _, rel := range meta.ImplicitRelations() {
+		if rel.Name == name {
+			relations = append(relations, rel)
+		}
+	}
+	return relations
 }
 
 // Meta represents all the known content that may be defined
 // within a charm's metadata.yaml file.
-// Note: Series is serialised for backward compatibility
-// as "supported-series" because a previous
-// charm version had an incompatible Series field that
-// was unused in practice but still serialized. This
-// only applies to JSON because Meta has a custom
-// YAML marshaller.
+// Meta has custom YAML and JSON marshalers; its JSON field tags
+// describe the format of the version 1 metadata documents read by
+// LoadMetaJSON. In that format, Series is serialised for backward
+// compatibility as "SupportedSeries" because a previous charm version
+// had an incompatible Series field that was unused in practice but
+// still serialized.
 type Meta struct {
 	Name           string                   `bson:"name" json:"Name"`
 	Summary        string                   `bson:"summary" json:"Summary"`
@@ -276,8 +473,34 @@ type Meta struct {
 	Resources      map[string]resource.Meta `bson:"resources,omitempty" json:"Resources,omitempty"`
 	Terms          []string                 `bson:"terms,omitempty" json:"Terms,omitempty"`
 	MinJujuVersion version.Number           `bson:"min-juju-version,omitempty" json:"min-juju-version,omitempty"`
+	CharmUser      CharmUser                `bson:"charm-user,omitempty" json:"charm-user,omitempty"`
+	CharmUserGroup string                   `bson:"charm-user-group,omitempty" json:"charm-user-group,omitempty"`
+	Website        []string                 `bson:"website,omitempty" json:"website,omitempty"`
+	Docs           []string                 `bson:"docs,omitempty" json:"docs,omitempty"`
+	Issues         []string                 `bson:"issues,omitempty" json:"issues,omitempty"`
+
+	// SummaryI18n and DescriptionI18n hold translations of Summary
+	// and Description, keyed by locale. See Localized.
+	SummaryI18n     map[string]string `bson:"summary-i18n,omitempty" json:"summary-i18n,omitempty"`
+	DescriptionI18n map[string]string `bson:"description-i18n,omitempty" json:"description-i18n,omitempty"`
+
+	// Deprecated holds the author's declaration that the charm is
+	// deprecated, or nil if it is not.
+	Deprecated *Deprecation `bson:"deprecated,omitempty" json:"deprecated,omitempty"`
+
+	// SupersededBy optionally holds the name of the charm that
+	// replaces this one. A superseded charm is deprecated whether or
+	// not Deprecated is set.
+	SupersededBy string `bson:"superseded-by,omitempty" json:"superseded-by,omitempty"`
+
+	// Extra holds the top level fields of metadata.yaml that this
+	// package does not recognize, such as those added by newer
+	// versions of Juju, so that they are not lost when the metadata
+	// is marshaled again. It may not hold fields that are recognized.
+	Extra map[string]interface{} `bson:"extra,omitempty" json:"extra,omitempty"`
 
 	Systems       []systems.System     `bson:"systems,omitempty" json:"systems,omitempty" yaml:"systems,omitempty"`
+	Bases         []Base               `bson:"bases,omitempty" json:"bases,omitempty" yaml:"bases,omitempty"`
 	Platforms     []Platform           `bson:"platforms,omitempty" json:"platforms,omitempty" yaml:"platforms,omitempty"`
 	Architectures []Architecture       `bson:"architectures,omitempty" json:"architectures,omitempty" yaml:"architectures,omitempty"`
 	Containers    map[string]Container `bson:"containers,omitempty" json:"containers,omitempty" yaml:"containers,omitempty"`
@@ -305,6 +528,20 @@ const (
 	S390X   Architecture = "s390x"
 )
 
+// CharmUser describes the privilege level the charm needs to run.
+type CharmUser string
+
+// Privilege levels a charm may declare with charm-user.
+const (
+	CharmUserRoot    CharmUser = "root"
+	CharmUserSudoer  CharmUser = "sudoer"
+	CharmUserNonRoot CharmUser = "non-root"
+)
+
+// validCharmUserGroup matches the names accepted for charm-user-group,
+// following the conventions for POSIX group names.
+var validCharmUserGroup = regexp.MustCompile(`^[a-z_][a-z0-9_-]{0,31}$`)
+
 // Container specifies the possible systems it supports and mounts it wants.
 type Container struct {
 	Systems []systems.System `bson:"systems,omitempty" json:"systems,omitempty" yaml:"systems,omitempty"`
@@ -354,11 +591,51 @@ func (m Meta) Hooks() map[string]bool {
 	return allHooks
 }
 
+// AllHooks returns a map of all possible valid hooks, as Hooks does,
+// together with the storage hooks of the charm's stores and the
+// workload hooks of its containers. If actions is not nil, the names of
+// its actions, which are run from the actions directory rather than the
+// hooks directory, are included too. The value is always true.
+func (m Meta) AllHooks(actions *Actions) map[string]bool {
+	allHooks := m.Hooks()
+	for name := range m.Storage {
+		for _, kind := range hooks.StorageHooks() {
+			allHooks[fmt.Sprintf("%s-%s", name, kind)] = true
+		}
+	}
+	for name := range m.Containers {
+		for _, kind := range hooks.WorkloadHooks() {
+			allHooks[fmt.Sprintf("%s-%s", name, kind)] = true
+		}
+	}
+	if actions != nil {
+		for name := range actions.ActionSpecs {
+			allHooks[name] = true
+		}
+	}
+	return allHooks
+}
+
+// ParseRelationHook splits a relation hook name, such as
+// "db-relation-changed", into the relation name and hook kind. The
+// final result is false unless hookName names a relation hook for a
+// relation declared by the charm.
+func (m Meta) ParseRelationHook(hookName string) (string, hooks.Kind, bool) {
+	relationName, kind, ok := hooks.ParseRelationHook(hookName)
+	if !ok {
+		return "", "", false
+	}
+	if _, ok := m.CombinedRelations()[relationName]; !ok {
+		return "", "", false
+	}
+	return relationName, kind, true
+}
+
 // Format returns the charm metadata format version.
-// Charms that specify systems are v2. Otherwise it
+// Charms that specify systems or bases are v2. Otherwise it
 // defaults to v1.
 func (m Meta) Format() Format {
-	if m.Systems != nil {
+	if m.Systems != nil || m.Bases != nil {
 		return FormatV2
 	}
 	return FormatV1
@@ -374,13 +651,20 @@ func (m Meta) ComputedSeries() []string {
 	// have unique elements.
 	seriesSlice := []string(nil)
 	seriesSet := set.NewStrings()
-	for _, system := range m.Systems {
-		series := system.String()
+	add := func(series string) {
 		if !seriesSet.Contains(series) {
 			seriesSet.Add(series)
 			seriesSlice = append(seriesSlice, series)
 		}
 	}
+	for _, system := range m.Systems {
+		add(system.String())
+	}
+	// Bases are looked up in the package's own series table, which
+	// knows more releases than that of systems.
+	for _, base := range m.Bases {
+		add(baseSeries(base))
+	}
 	return seriesSlice
 }
 
@@ -528,41 +812,122 @@ func MustParseTerm(s string) *TermsId {
 // ReadMeta reads the content of a metadata.yaml file and returns
 // its representation.
 func ReadMeta(r io.Reader) (*Meta, error) {
-	data, err := ioutil.ReadAll(r)
+	data, err := readYAMLInput(r)
 	if err != nil {
 		return nil, err
 	}
 	var meta Meta
 	err = yaml.Unmarshal(data, &meta)
 	if err != nil {
+		err = locateMetaError(data, err)
+		reportParseFailure(MetadataFile, data, err)
 		return nil, err
 	}
 	return &meta, nil
 }
 
+// ReadMetaRaw is like ReadMeta, but also returns the fields of the
+// metadata after they have been coerced by the metadata schema, with
+// defaults filled in, together with any fields that this package does
+// not recognize, as they were read. It is meant for tools that need
+// fields that Meta does not yet model, without parsing the document a
+// second time.
+func ReadMetaRaw(r io.Reader) (*Meta, map[string]interface{}, error) {
+	data, err := readYAMLInput(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	raw := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		err = locateMetaError(data, err)
+		reportParseFailure(MetadataFile, data, err)
+		return nil, nil, err
+	}
+	meta, fields, err := metaFromRaw(raw)
+	if err != nil {
+		err = locateMetaError(data, err)
+		reportParseFailure(MetadataFile, data, err)
+		return nil, nil, err
+	}
+	for name, value := range meta.Extra {
+		fields[name] = value
+	}
+	return meta, fields, nil
+}
+
 func (meta *Meta) UnmarshalYAML(f func(interface{}) error) error {
 	raw := make(map[interface{}]interface{})
 	err := f(&raw)
 	if err != nil {
 		return err
 	}
+	meta1, _, err := metaFromRaw(raw)
+	if err != nil {
+		return err
+	}
+	*meta = *meta1
+	return nil
+}
+
+// metaFromRaw returns the checked metadata held in the top level fields
+// raw of a metadata.yaml document, and the fields as coerced by the
+// metadata schema.
+func metaFromRaw(raw map[interface{}]interface{}) (*Meta, map[string]interface{}, error) {
 	v, err := charmSchema.Coerce(raw, nil)
 	if err != nil {
-		return errors.New("metadata: " + err.Error())
+		return nil, nil, errors.New("metadata: " + err.Error())
 	}
 
 	m := v.(map[string]interface{})
-	meta1, err := parseMeta(m)
+	meta, err := parseMeta(m)
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+	meta.Extra = extraMetaFields(raw)
+	if err := coerceRegisteredFields(meta.Extra); err != nil {
+		return nil, nil, err
 	}
 
-	if err := meta1.Check(); err != nil {
-		return err
+	if err := meta.Check(); err != nil {
+		return nil, nil, err
 	}
+	return meta, m, nil
+}
 
-	*meta = *meta1
-	return nil
+// extraMetaFields returns the top level fields of raw that are not
+// known, with any maps they hold converted to map[string]interface{}
+// so that they may also be marshaled as JSON or BSON.
+func extraMetaFields(raw map[interface{}]interface{}) map[string]interface{} {
+	var extra map[string]interface{}
+	for key, value := range raw {
+		name := fmt.Sprint(key)
+		if _, ok := charmFields[name]; ok {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]interface{})
+		}
+		extra[name] = stringKeyedValue(value)
+	}
+	return extra
+}
+
+func stringKeyedValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			result[fmt.Sprint(key)] = stringKeyedValue(value)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, value := range v {
+			result[i] = stringKeyedValue(value)
+		}
+		return result
+	}
+	return v
 }
 
 func parseMeta(m map[string]interface{}) (*Meta, error) {
@@ -602,6 +967,23 @@ func parseMeta(m map[string]interface{}) (*Meta, error) {
 		meta.MinJujuVersion = minver
 	}
 	meta.Terms = parseStringList(m["terms"])
+	if user := m["charm-user"]; user != nil {
+		meta.CharmUser = CharmUser(user.(string))
+	}
+	if group := m["charm-user-group"]; group != nil {
+		meta.CharmUserGroup = group.(string)
+	}
+	meta.Website = parseStringList(m["website"])
+	meta.Docs = parseStringList(m["docs"])
+	meta.Issues = parseStringList(m["issues"])
+	meta.SummaryI18n = parseTranslations(m["summary-i18n"])
+	meta.DescriptionI18n = parseTranslations(m["description-i18n"])
+	if meta.Deprecated, err = parseDeprecation(m["deprecated"]); err != nil {
+		return nil, errors.Annotate(err, "invalid deprecated")
+	}
+	if supersededBy := m["superseded-by"]; supersededBy != nil {
+		meta.SupersededBy = supersededBy.(string)
+	}
 
 	meta.Resources, err = parseMetaResources(m["resources"])
 	if err != nil {
@@ -613,6 +995,10 @@ func parseMeta(m map[string]interface{}) (*Meta, error) {
 	if err != nil {
 		return nil, errors.Annotatef(err, "parsing systems")
 	}
+	meta.Bases, err = parseBases(m["bases"])
+	if err != nil {
+		return nil, errors.Annotatef(err, "parsing bases")
+	}
 	meta.Platforms, err = parsePlatforms(m["platforms"])
 	if err != nil {
 		return nil, errors.Annotatef(err, "parsing platforms")
@@ -630,55 +1016,84 @@ func parseMeta(m map[string]interface{}) (*Meta, error) {
 
 // MarshalYAML implements yaml.Marshaler (yaml.v2).
 func (m Meta) MarshalYAML() (interface{}, error) {
+	for name := range m.Extra {
+		if _, ok := charmFields[name]; ok {
+			return nil, errors.NotValidf("extra metadata field %q clashing with a known field", name)
+		}
+	}
 	var minver string
 	if m.MinJujuVersion != version.Zero {
 		minver = m.MinJujuVersion.String()
 	}
 
 	return struct {
-		Name           string                           `yaml:"name"`
-		Summary        string                           `yaml:"summary"`
-		Description    string                           `yaml:"description"`
-		Provides       map[string]marshaledRelation     `yaml:"provides,omitempty"`
-		Requires       map[string]marshaledRelation     `yaml:"requires,omitempty"`
-		Peers          map[string]marshaledRelation     `yaml:"peers,omitempty"`
-		ExtraBindings  map[string]interface{}           `yaml:"extra-bindings,omitempty"`
-		Categories     []string                         `yaml:"categories,omitempty"`
-		Tags           []string                         `yaml:"tags,omitempty"`
-		Subordinate    bool                             `yaml:"subordinate,omitempty"`
-		Series         []string                         `yaml:"series,omitempty"`
-		Storage        map[string]Storage               `yaml:"storage,omitempty"`
-		Devices        map[string]Device                `yaml:"devices,omitempty"`
-		Deployment     *Deployment                      `yaml:"deployment,omitempty"`
-		Terms          []string                         `yaml:"terms,omitempty"`
-		MinJujuVersion string                           `yaml:"min-juju-version,omitempty"`
-		Resources      map[string]marshaledResourceMeta `yaml:"resources,omitempty"`
-		Systems        []marshaledSystem                `yaml:"systems,omitempty"`
-		Platforms      []Platform                       `yaml:"platforms,omitempty"`
-		Architectures  []Architecture                   `yaml:"architectures,omitempty"`
-		Containers     map[string]marshaledContainer    `yaml:"containers,omitempty"`
+		Name            string                           `yaml:"name"`
+		Summary         string                           `yaml:"summary"`
+		Description     string                           `yaml:"description"`
+		Provides        map[string]marshaledRelation     `yaml:"provides,omitempty"`
+		Requires        map[string]marshaledRelation     `yaml:"requires,omitempty"`
+		Peers           map[string]marshaledRelation     `yaml:"peers,omitempty"`
+		ExtraBindings   map[string]interface{}           `yaml:"extra-bindings,omitempty"`
+		Categories      []string                         `yaml:"categories,omitempty"`
+		Tags            []string                         `yaml:"tags,omitempty"`
+		Subordinate     bool                             `yaml:"subordinate,omitempty"`
+		Series          []string                         `yaml:"series,omitempty"`
+		Storage         map[string]marshaledStorage      `yaml:"storage,omitempty"`
+		Devices         map[string]marshaledDevice       `yaml:"devices,omitempty"`
+		Deployment      *marshaledDeployment             `yaml:"deployment,omitempty"`
+		PayloadClasses  map[string]marshaledPayloadClass `yaml:"payloads,omitempty"`
+		Terms           []string                         `yaml:"terms,omitempty"`
+		MinJujuVersion  string                           `yaml:"min-juju-version,omitempty"`
+		CharmUser       CharmUser                        `yaml:"charm-user,omitempty"`
+		CharmUserGroup  string                           `yaml:"charm-user-group,omitempty"`
+		Website         []string                         `yaml:"website,omitempty"`
+		Docs            []string                         `yaml:"docs,omitempty"`
+		Issues          []string                         `yaml:"issues,omitempty"`
+		SummaryI18n     map[string]string                `yaml:"summary-i18n,omitempty"`
+		DescriptionI18n map[string]string                `yaml:"description-i18n,omitempty"`
+		Deprecated      interface{}                      `yaml:"deprecated,omitempty"`
+		SupersededBy    string                           `yaml:"superseded-by,omitempty"`
+		Resources       map[string]marshaledResourceMeta `yaml:"resources,omitempty"`
+		Systems         []marshaledSystem                `yaml:"systems,omitempty"`
+		Bases           []marshaledBase                  `yaml:"bases,omitempty"`
+		Platforms       []Platform                       `yaml:"platforms,omitempty"`
+		Architectures   []Architecture                   `yaml:"architectures,omitempty"`
+		Containers      map[string]marshaledContainer    `yaml:"containers,omitempty"`
+		Extra           map[string]interface{}           `yaml:",inline"`
 	}{
-		Name:           m.Name,
-		Summary:        m.Summary,
-		Description:    m.Description,
-		Provides:       marshaledRelations(m.Provides),
-		Requires:       marshaledRelations(m.Requires),
-		Peers:          marshaledRelations(m.Peers),
-		ExtraBindings:  marshaledExtraBindings(m.ExtraBindings),
-		Categories:     m.Categories,
-		Tags:           m.Tags,
-		Subordinate:    m.Subordinate,
-		Series:         m.Series,
-		Storage:        m.Storage,
-		Devices:        m.Devices,
-		Deployment:     m.Deployment,
-		Terms:          m.Terms,
-		MinJujuVersion: minver,
-		Resources:      marshaledResources(m.Resources),
-		Systems:        marshaledSystems(m.Systems),
-		Platforms:      m.Platforms,
-		Architectures:  m.Architectures,
-		Containers:     marshaledContainers(m.Containers),
+		Name:            m.Name,
+		Summary:         m.Summary,
+		Description:     m.Description,
+		Provides:        marshaledRelations(m.Provides),
+		Requires:        marshaledRelations(m.Requires),
+		Peers:           marshaledRelations(m.Peers),
+		ExtraBindings:   marshaledExtraBindings(m.ExtraBindings),
+		Categories:      m.Categories,
+		Tags:            m.Tags,
+		Subordinate:     m.Subordinate,
+		Series:          m.Series,
+		Storage:         marshaledStorages(m.Storage),
+		Devices:         marshaledDevices(m.Devices),
+		Deployment:      (*marshaledDeployment)(m.Deployment),
+		PayloadClasses:  marshaledPayloadClasses(m.PayloadClasses),
+		Terms:           m.Terms,
+		MinJujuVersion:  minver,
+		CharmUser:       m.CharmUser,
+		CharmUserGroup:  m.CharmUserGroup,
+		Website:         m.Website,
+		Docs:            m.Docs,
+		Issues:          m.Issues,
+		SummaryI18n:     m.SummaryI18n,
+		DescriptionI18n: m.DescriptionI18n,
+		Deprecated:      marshaledDeprecation(m.Deprecated),
+		SupersededBy:    m.SupersededBy,
+		Resources:       marshaledResources(m.Resources),
+		Systems:         marshaledSystems(m.Systems),
+		Bases:           marshaledBases(m.Bases),
+		Platforms:       m.Platforms,
+		Architectures:   m.Architectures,
+		Containers:      marshaledContainers(m.Containers),
+		Extra:           m.Extra,
 	}, nil
 }
 
@@ -703,6 +1118,109 @@ func marshaledResources(rs map[string]resource.Meta) map[string]marshaledResourc
 	return rs1
 }
 
+func marshaledStorages(stores map[string]Storage) map[string]marshaledStorage {
+	if len(stores) == 0 {
+		return nil
+	}
+	marshaled := make(map[string]marshaledStorage, len(stores))
+	for name, store := range stores {
+		marshaled[name] = marshaledStorage(store)
+	}
+	return marshaled
+}
+
+type marshaledStorage Storage
+
+func (s marshaledStorage) MarshalYAML() (interface{}, error) {
+	// See storageSchema.
+	ms := struct {
+		Type        StorageType       `yaml:"type"`
+		Description string            `yaml:"description,omitempty"`
+		Shared      bool              `yaml:"shared,omitempty"`
+		ReadOnly    bool              `yaml:"read-only,omitempty"`
+		Multiple    map[string]string `yaml:"multiple,omitempty"`
+		MinimumSize string            `yaml:"minimum-size,omitempty"`
+		Location    string            `yaml:"location,omitempty"`
+		Properties  []string          `yaml:"properties,omitempty"`
+	}{
+		Type:        s.Type,
+		Description: s.Description,
+		Shared:      s.Shared,
+		ReadOnly:    s.ReadOnly,
+		Location:    s.Location,
+		Properties:  s.Properties,
+	}
+	// The zero value is taken as a singleton store, as it is when
+	// multiple is left out of metadata.yaml.
+	singleton := s.CountMin == s.CountMax && (s.CountMin == 0 || s.CountMin == 1)
+	if !singleton {
+		ms.Multiple = map[string]string{"range": Storage(s).CountRangeString()}
+	}
+	if s.MinimumSize > 0 {
+		// The size is held in MiB, which is the unit assumed
+		// by storageSizeC when none is given.
+		ms.MinimumSize = fmt.Sprintf("%dM", s.MinimumSize)
+	}
+	return ms, nil
+}
+
+type marshaledDevice struct {
+	// See deviceSchema.
+	Description string     `yaml:"description,omitempty"`
+	Type        DeviceType `yaml:"type"`
+	CountMin    int64      `yaml:"countmin"`
+	CountMax    int64      `yaml:"countmax"`
+}
+
+func marshaledDevices(devices map[string]Device) map[string]marshaledDevice {
+	if len(devices) == 0 {
+		return nil
+	}
+	marshaled := make(map[string]marshaledDevice, len(devices))
+	for name, device := range devices {
+		marshaled[name] = marshaledDevice{
+			Description: device.Description,
+			Type:        device.Type,
+			CountMin:    device.CountMin,
+			CountMax:    device.CountMax,
+		}
+	}
+	return marshaled
+}
+
+type marshaledDeployment Deployment
+
+func (d marshaledDeployment) MarshalYAML() (interface{}, error) {
+	// See deploymentSchema.
+	return struct {
+		DeploymentType DeploymentType `yaml:"type,omitempty"`
+		DeploymentMode DeploymentMode `yaml:"mode,omitempty"`
+		ServiceType    ServiceType    `yaml:"service,omitempty"`
+		MinVersion     string         `yaml:"min-version,omitempty"`
+	}{
+		DeploymentType: d.DeploymentType,
+		DeploymentMode: d.DeploymentMode,
+		ServiceType:    d.ServiceType,
+		MinVersion:     d.MinVersion,
+	}, nil
+}
+
+type marshaledPayloadClass struct {
+	// See payloadClassSchema.
+	Type string `yaml:"type"`
+}
+
+func marshaledPayloadClasses(classes map[string]PayloadClass) map[string]marshaledPayloadClass {
+	if len(classes) == 0 {
+		return nil
+	}
+	marshaled := make(map[string]marshaledPayloadClass, len(classes))
+	for name, class := range classes {
+		marshaled[name] = marshaledPayloadClass{Type: class.Type}
+	}
+	return marshaled
+}
+
 func marshaledRelations(relations map[string]Relation) map[string]marshaledRelation {
 	marshaled := make(map[string]marshaledRelation)
 	for name, relation := range relations {
@@ -716,18 +1234,28 @@ type marshaledRelation Relation
 func (r marshaledRelation) MarshalYAML() (interface{}, error) {
 	// See calls to ifaceExpander in charmSchema.
 	var noLimit int
-	if !r.Optional && r.Limit == noLimit && r.Scope == ScopeGlobal {
+	if !r.Optional && r.Limit == noLimit && r.Scope == ScopeGlobal && r.Description == "" && r.RenamedFrom == "" && len(r.Ports) == 0 && len(r.InterfaceAliases) == 0 {
 		// All attributes are default, so use the simple string form of the relation.
 		return r.Interface, nil
 	}
 	mr := struct {
-		Interface string        `yaml:"interface"`
-		Limit     *int          `yaml:"limit,omitempty"`
-		Optional  bool          `yaml:"optional,omitempty"`
-		Scope     RelationScope `yaml:"scope,omitempty"`
+		Interface        string        `yaml:"interface"`
+		InterfaceAliases []string      `yaml:"interface-aliases,omitempty"`
+		Limit            *int          `yaml:"limit,omitempty"`
+		Optional         bool          `yaml:"optional,omitempty"`
+		Scope            RelationScope `yaml:"scope,omitempty"`
+		Ports            []string      `yaml:"ports,omitempty"`
+		Description      string        `yaml:"description,omitempty"`
+		RenamedFrom      string        `yaml:"renamed-from,omitempty"`
 	}{
-		Interface: r.Interface,
-		Optional:  r.Optional,
+		Interface:        r.Interface,
+		InterfaceAliases: r.InterfaceAliases,
+		Optional:         r.Optional,
+		Description:      r.Description,
+		RenamedFrom:      r.RenamedFrom,
+	}
+	for _, port := range r.Ports {
+		mr.Ports = append(mr.Ports, port.String())
 	}
 	if r.Limit != noLimit {
 		mr.Limit = &r.Limit
@@ -769,6 +1297,29 @@ func (s marshaledSystem) MarshalYAML() (interface{}, error) {
 	return ms, nil
 }
 
+type marshaledBase Base
+
+func marshaledBases(b []Base) []marshaledBase {
+	marshaled := []marshaledBase(nil)
+	for _, v := range b {
+		marshaled = append(marshaled, marshaledBase(v))
+	}
+	return marshaled
+}
+
+func (b marshaledBase) MarshalYAML() (interface{}, error) {
+	mb := struct {
+		Name          string         `yaml:"name"`
+		Channel       string         `yaml:"channel"`
+		Architectures []Architecture `yaml:"architectures,omitempty"`
+	}{
+		Name:          b.Name,
+		Channel:       b.Channel.String(),
+		Architectures: b.Architectures,
+	}
+	return mb, nil
+}
+
 type marshaledContainer Container
 
 func marshaledContainers(c map[string]Container) map[string]marshaledContainer {
@@ -790,49 +1341,118 @@ func (c marshaledContainer) MarshalYAML() (interface{}, error) {
 	return mc, nil
 }
 
-// Check checks that the metadata is well-formed.
+// Check checks that the metadata is well-formed, and returns the first
+// problem found. Use Validate to find all of them.
 func (meta Meta) Check() error {
+	if errs := meta.validate(); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// Validate checks that the metadata is well-formed, like Check, but
+// returns all the problems found, as ValidationErrors, so that they
+// can all be fixed in one pass.
+func (meta Meta) Validate() error {
+	if errs := meta.validate(); len(errs) > 0 {
+		return ValidationErrors(errs)
+	}
+	return nil
+}
+
+// ValidationErrors holds the problems found by Meta.Validate.
+type ValidationErrors []error
+
+// Error implements error.
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// validate returns the problems found with the metadata, in a stable
+// order.
+func (meta Meta) validate() []error {
+	var errs []error
+	fail := func(err error) {
+		errs = append(errs, err)
+	}
+
+	// The name is required by ReadMeta; metadata built in code may
+	// leave it unset.
+	if meta.Name != "" && !IsValidName(meta.Name) {
+		fail(fieldErrorf("name", ErrInvalidCharmName, "invalid charm name %q", meta.Name))
+	}
+
 	// Check for duplicate or forbidden relation names or interfaces.
 	names := map[string]bool{}
-	checkRelations := func(src map[string]Relation, role RelationRole) error {
-		for name, rel := range src {
+	checkRelations := func(src map[string]Relation, role RelationRole, section string) {
+		for _, name := range sortedRelationNames(src) {
+			rel := src[name]
+			field := section + "." + name
 			if rel.Name != name {
-				return fmt.Errorf("charm %q has mismatched relation name %q; expected %q", meta.Name, rel.Name, name)
+				fail(fieldErrorf(field, ErrMismatchedRelation, "charm %q has mismatched relation name %q; expected %q", meta.Name, rel.Name, name))
+				continue
 			}
 			if rel.Role != role {
-				return fmt.Errorf("charm %q has mismatched role %q; expected %q", meta.Name, rel.Role, role)
+				fail(fieldErrorf(field, ErrMismatchedRelation, "charm %q has mismatched role %q; expected %q", meta.Name, rel.Role, role))
+				continue
 			}
 			// Container-scoped require relations on subordinates are allowed
 			// to use the otherwise-reserved juju-* namespace.
 			if !meta.Subordinate || role != RoleRequirer || rel.Scope != ScopeContainer {
 				if reserved, _ := reservedName(name); reserved {
-					return fmt.Errorf("charm %q using a reserved relation name: %q", meta.Name, name)
+					fail(fieldErrorf(field, ErrReservedRelationName, "charm %q using a reserved relation name: %q", meta.Name, name))
 				}
 			}
+			if !IsValidInterface(rel.Interface) {
+				fail(fieldErrorf(field+".interface", ErrInvalidInterface, "charm %q relation %q has invalid interface %q", meta.Name, name, rel.Interface))
+			}
 			if role != RoleRequirer {
 				if reserved, _ := reservedName(rel.Interface); reserved {
-					return fmt.Errorf("charm %q relation %q using a reserved interface: %q", meta.Name, name, rel.Interface)
+					fail(fieldErrorf(field+".interface", ErrReservedInterface, "charm %q relation %q using a reserved interface: %q", meta.Name, name, rel.Interface))
 				}
 			}
+			if len(rel.InterfaceAliases) > 0 && role == RolePeer {
+				fail(fieldErrorf(field+".interface-aliases", ErrInvalidInterface, "charm %q peer relation %q cannot have interface aliases", meta.Name, name))
+			}
+			aliases := map[string]bool{rel.Interface: true}
+			for _, alias := range rel.InterfaceAliases {
+				switch {
+				case !IsValidInterface(alias):
+					fail(fieldErrorf(field+".interface-aliases", ErrInvalidInterface, "charm %q relation %q has invalid interface alias %q", meta.Name, name, alias))
+				case aliases[alias]:
+					fail(fieldErrorf(field+".interface-aliases", ErrInvalidInterface, "charm %q relation %q has duplicated interface alias %q", meta.Name, name, alias))
+				case role == RoleProvider && strings.HasPrefix(alias, "juju-"):
+					fail(fieldErrorf(field+".interface-aliases", ErrReservedInterface, "charm %q relation %q using a reserved interface alias: %q", meta.Name, name, alias))
+				}
+				aliases[alias] = true
+			}
+			if rel.Limit < 0 {
+				fail(fieldErrorf(field+".limit", ErrInvalidRelationLimit, "charm %q relation %q has negative limit %d", meta.Name, name, rel.Limit))
+			}
 			if names[name] {
-				return fmt.Errorf("charm %q using a duplicated relation name: %q", meta.Name, name)
+				fail(fieldErrorf(field, ErrDuplicateRelationName, "charm %q using a duplicated relation name: %q", meta.Name, name))
 			}
 			names[name] = true
 		}
-		return nil
-	}
-	if err := checkRelations(meta.Provides, RoleProvider); err != nil {
-		return err
 	}
-	if err := checkRelations(meta.Requires, RoleRequirer); err != nil {
-		return err
+	checkRelations(meta.Provides, RoleProvider, "provides")
+	checkRelations(meta.Requires, RoleRequirer, "requires")
+	checkRelations(meta.Peers, RolePeer, "peers")
+
+	if err := validateRenamedRelations(meta); err != nil {
+		fail(fieldErrorf("", ErrInvalidRenamedRelations, "charm %q has invalid renamed relations: %v", meta.Name, err))
 	}
-	if err := checkRelations(meta.Peers, RolePeer); err != nil {
-		return err
+
+	if err := validateEndpointPorts(meta); err != nil {
+		fail(fieldErrorf("", ErrInvalidEndpointPorts, "charm %q has invalid endpoint ports: %v", meta.Name, err))
 	}
 
 	if err := validateMetaExtraBindings(meta); err != nil {
-		return fmt.Errorf("charm %q has invalid extra bindings: %v", meta.Name, err)
+		fail(fieldErrorf("extra-bindings", ErrInvalidExtraBindings, "charm %q has invalid extra bindings: %v", meta.Name, err))
 	}
 
 	// Subordinate charms must have at least one relation that
@@ -849,74 +1469,256 @@ func (meta Meta) Check() error {
 			}
 		}
 		if !valid {
-			return fmt.Errorf("subordinate charm %q lacks \"requires\" relation with container scope", meta.Name)
+			fail(fieldErrorf("subordinate", ErrSubordinateWithoutContainerRelation, "subordinate charm %q lacks \"requires\" relation with container scope", meta.Name))
 		}
 	}
 
-	for _, series := range meta.Series {
+	for i, series := range meta.Series {
 		if !IsValidSeries(series) {
-			return fmt.Errorf("charm %q declares invalid series: %q", meta.Name, series)
+			fail(fieldErrorf(fmt.Sprintf("series[%d]", i), ErrInvalidSeries, "charm %q declares invalid series: %q", meta.Name, series))
 		}
 	}
+	if meta.Format() == FormatV2 && len(meta.Series) > 0 {
+		fail(fieldErrorf("series", ErrSeriesInFormatV2, "charm %q declares series as well as bases or systems", meta.Name))
+	}
 
-	names = make(map[string]bool)
-	for name, store := range meta.Storage {
+	relations := meta.CombinedRelations()
+	storageNames := make([]string, 0, len(meta.Storage))
+	for name := range meta.Storage {
+		storageNames = append(storageNames, name)
+	}
+	sort.Strings(storageNames)
+	validLocations := true
+	for _, name := range storageNames {
+		store := meta.Storage[name]
+		field := "storage." + name
+		if reserved, reason := reservedName(name); reserved {
+			fail(fieldErrorf(field, ErrReservedStorageName, "charm %q using a reserved storage name: %q (%s)", meta.Name, name, reason))
+		}
+		if _, ok := relations[name]; ok {
+			fail(fieldErrorf(field, ErrStorageNameClash, "charm %q storage %q: name clashes with a relation", meta.Name, name))
+		}
 		if store.Location != "" && store.Type != StorageFilesystem {
-			return fmt.Errorf(`charm %q storage %q: location may not be specified for "type: %s"`, meta.Name, name, store.Type)
+			fail(fieldErrorf(field+".location", ErrInvalidStorageLocation, `charm %q storage %q: location may not be specified for "type: %s"`, meta.Name, name, store.Type))
 		}
 		if store.Type == "" {
-			return fmt.Errorf("charm %q storage %q: type must be specified", meta.Name, name)
+			fail(fieldErrorf(field+".type", ErrMissingStorageType, "charm %q storage %q: type must be specified", meta.Name, name))
 		}
 		if store.CountMin < 0 {
-			return fmt.Errorf("charm %q storage %q: invalid minimum count %d", meta.Name, name, store.CountMin)
+			fail(fieldErrorf(field+".multiple", ErrInvalidStorageCount, "charm %q storage %q: invalid minimum count %d", meta.Name, name, store.CountMin))
 		}
 		if store.CountMax == 0 || store.CountMax < -1 {
-			return fmt.Errorf("charm %q storage %q: invalid maximum count %d", meta.Name, name, store.CountMax)
+			fail(fieldErrorf(field+".multiple", ErrInvalidStorageCount, "charm %q storage %q: invalid maximum count %d", meta.Name, name, store.CountMax))
 		}
-		if names[name] {
-			return fmt.Errorf("charm %q storage %q: duplicated storage name", meta.Name, name)
+		if store.MinimumSize > maxStorageMinimumSize {
+			fail(fieldErrorf(field+".minimum-size", ErrStorageMinimumSizeTooLarge, "charm %q storage %q: minimum size %dM too large", meta.Name, name, store.MinimumSize))
+		}
+		for _, property := range store.Properties {
+			if property != StoragePropertyTransient {
+				fail(fieldErrorf(field+".properties", ErrUnknownStorageProperty, "charm %q storage %q: unknown property %q", meta.Name, name, property))
+			}
+		}
+		if store.Location != "" {
+			if err := validateStorageLocation(store.Location); err != nil {
+				fail(fieldErrorf(field+".location", ErrInvalidStorageLocation, "charm %q storage %q: invalid location %q: %v", meta.Name, name, store.Location, err))
+				validLocations = false
+			}
+		}
+	}
+	if validLocations {
+		if err := checkStorageLocationOverlap(meta); err != nil {
+			fail(err)
 		}
-		names[name] = true
 	}
 
-	names = make(map[string]bool)
-	for name, device := range meta.Devices {
+	deviceNames := make([]string, 0, len(meta.Devices))
+	for name := range meta.Devices {
+		deviceNames = append(deviceNames, name)
+	}
+	sort.Strings(deviceNames)
+	for _, name := range deviceNames {
+		device := meta.Devices[name]
+		field := "devices." + name
 		if device.Type == "" {
-			return fmt.Errorf("charm %q device %q: type must be specified", meta.Name, name)
+			fail(fieldErrorf(field+".type", ErrMissingDeviceType, "charm %q device %q: type must be specified", meta.Name, name))
 		}
 		if device.CountMax >= 0 && device.CountMin >= 0 && device.CountMin > device.CountMax {
-			return fmt.Errorf(
+			fail(fieldErrorf(field+".countmin", ErrInvalidDeviceCount,
 				"charm %q device %q: maximum count %d can not be smaller than minimum count %d",
-				meta.Name, name, device.CountMax, device.CountMin)
-		}
-		if names[name] {
-			return fmt.Errorf("charm %q device %q: duplicated device name", meta.Name, name)
+				meta.Name, name, device.CountMax, device.CountMin))
 		}
-		names[name] = true
 	}
 
-	for name, payloadClass := range meta.PayloadClasses {
+	payloadNames := make([]string, 0, len(meta.PayloadClasses))
+	for name := range meta.PayloadClasses {
+		payloadNames = append(payloadNames, name)
+	}
+	sort.Strings(payloadNames)
+	for _, name := range payloadNames {
+		payloadClass := meta.PayloadClasses[name]
 		if payloadClass.Name != name {
-			return fmt.Errorf("mismatch on payload class name (%q != %q)", payloadClass.Name, name)
+			fail(fieldErrorf("payloads."+name, ErrInvalidPayloadClass, "mismatch on payload class name (%q != %q)", payloadClass.Name, name))
+			continue
 		}
 		if err := payloadClass.Validate(); err != nil {
-			return err
+			fail(&FieldError{Path: "payloads." + name, Reason: ErrInvalidPayloadClass, Err: err})
 		}
 	}
 
 	if err := validateMetaResources(meta.Resources); err != nil {
-		return err
+		fail(&FieldError{Path: "resources", Reason: ErrInvalidResource, Err: err})
 	}
 
-	for _, term := range meta.Terms {
+	for i, term := range meta.Terms {
 		if _, terr := ParseTerm(term); terr != nil {
-			return errors.Trace(terr)
+			fail(&FieldError{Path: fmt.Sprintf("terms[%d]", i), Reason: ErrInvalidTerm, Err: errors.Trace(terr)})
+		}
+	}
+
+	switch meta.CharmUser {
+	case "", CharmUserRoot, CharmUserSudoer, CharmUserNonRoot:
+	default:
+		fail(fieldErrorf("charm-user", ErrInvalidCharmUser, "charm %q has invalid charm-user %q", meta.Name, meta.CharmUser))
+	}
+	if meta.CharmUserGroup != "" && !validCharmUserGroup.MatchString(meta.CharmUserGroup) {
+		fail(fieldErrorf("charm-user-group", ErrInvalidCharmUserGroup, "charm %q has invalid charm-user-group %q", meta.Name, meta.CharmUserGroup))
+	}
+
+	for _, links := range []struct {
+		field string
+		urls  []string
+	}{
+		{"website", meta.Website},
+		{"docs", meta.Docs},
+		{"issues", meta.Issues},
+	} {
+		for i, link := range links.urls {
+			if err := validateLinkURL(link); err != nil {
+				fail(fieldErrorf(fmt.Sprintf("%s[%d]", links.field, i), ErrInvalidLinkURL,
+					"charm %q has invalid %s URL %q: %v", meta.Name, links.field, link, err))
+			}
+		}
+	}
+
+	for _, translations := range []struct {
+		field        string
+		translations map[string]string
+	}{
+		{"summary-i18n", meta.SummaryI18n},
+		{"description-i18n", meta.DescriptionI18n},
+	} {
+		for _, locale := range sortedNames(translations.translations) {
+			if !IsValidLocale(locale) {
+				fail(fieldErrorf(translations.field+"."+locale, ErrInvalidLocale,
+					"charm %q has invalid %s locale %q", meta.Name, translations.field, locale))
+			}
 		}
 	}
 
+	if meta.SupersededBy != "" {
+		if !IsValidName(meta.SupersededBy) {
+			fail(fieldErrorf("superseded-by", ErrInvalidSupersededBy, "charm %q superseded by invalid charm name %q", meta.Name, meta.SupersededBy))
+		} else if meta.SupersededBy == meta.Name {
+			fail(fieldErrorf("superseded-by", ErrInvalidSupersededBy, "charm %q superseded by itself", meta.Name))
+		}
+	}
+
+	return errs
+}
+
+// validateLinkURL checks that link is an absolute http or https URL.
+func validateLinkURL(link string) error {
+	u, err := url.Parse(link)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("scheme must be http or https")
+	}
+	if u.Host == "" {
+		return errors.New("host must be specified")
+	}
 	return nil
 }
 
+// reservedStorageLocations holds the system directories that a store
+// may not be mounted on.
+var reservedStorageLocations = []string{
+	"/", "/bin", "/boot", "/etc", "/home", "/lib", "/lib64",
+	"/opt", "/root", "/run", "/sbin", "/tmp", "/usr", "/var",
+}
+
+// reservedStorageTrees holds the directories that a store may not be
+// mounted on or below. The charm directory and the agent state live
+// under /var/lib/juju.
+var reservedStorageTrees = []string{
+	"/dev", "/proc", "/sys", "/var/lib/juju",
+}
+
+// validateStorageLocation checks that location is an absolute, clean
+// path that is not a reserved mount point.
+func validateStorageLocation(location string) error {
+	if !path.IsAbs(location) {
+		return errors.New("path must be absolute")
+	}
+	// Allow a trailing slash, which is commonly used for directories.
+	trimmed := location
+	if len(trimmed) > 1 {
+		trimmed = strings.TrimSuffix(trimmed, "/")
+	}
+	if path.Clean(trimmed) != trimmed {
+		return errors.Errorf("path must be clean (expected %q)", path.Clean(location))
+	}
+	for _, reserved := range reservedStorageLocations {
+		if trimmed == reserved {
+			return errors.Errorf("%q is a reserved mount point", reserved)
+		}
+	}
+	for _, reserved := range reservedStorageTrees {
+		if pathWithin(trimmed, reserved) {
+			return errors.Errorf("%q is reserved", reserved)
+		}
+	}
+	return nil
+}
+
+// checkStorageLocationOverlap checks that no store is mounted on or
+// below the location of another. The locations must already have been
+// checked with validateStorageLocation.
+func checkStorageLocationOverlap(meta Meta) error {
+	names := make([]string, 0, len(meta.Storage))
+	for name, store := range meta.Storage {
+		if store.Location != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		location := path.Clean(meta.Storage[name].Location)
+		for _, other := range names[i+1:] {
+			otherLocation := path.Clean(meta.Storage[other].Location)
+			if pathWithin(location, otherLocation) || pathWithin(otherLocation, location) {
+				return fieldErrorf("storage."+name+".location", ErrInvalidStorageLocation, "charm %q storage %q: location %q overlaps storage %q location %q",
+					meta.Name, name, meta.Storage[name].Location, other, meta.Storage[other].Location)
+			}
+		}
+	}
+	return nil
+}
+
+// pathWithin reports whether the clean absolute path p is dir or
+// lies below it.
+func pathWithin(p, dir string) bool {
+	return p == dir || dir == "/" || strings.HasPrefix(p, dir+"/")
+}
+
+// RequiresRoot reports whether the charm needs to run as root. Charms
+// that do not declare a charm-user have always been run as root, so
+// they are reported as requiring it.
+func (m Meta) RequiresRoot() bool {
+	return m.CharmUser == "" || m.CharmUser == CharmUserRoot
+}
+
 func reservedName(name string) (reserved bool, reason string) {
 	if name == "juju" {
 		return true, `"juju" is a reserved name`
@@ -943,6 +1745,22 @@ func parseRelations(relations interface{}, role RelationRole) map[string]Relatio
 		if scope := relMap["scope"]; scope != nil {
 			relation.Scope = RelationScope(scope.(string))
 		}
+		if desc := relMap["description"]; desc != nil {
+			relation.Description = desc.(string)
+		}
+		if renamedFrom := relMap["renamed-from"]; renamedFrom != nil {
+			relation.RenamedFrom = renamedFrom.(string)
+		}
+		if aliases, ok := relMap["interface-aliases"].([]interface{}); ok {
+			for _, alias := range aliases {
+				relation.InterfaceAliases = append(relation.InterfaceAliases, alias.(string))
+			}
+		}
+		if ports, ok := relMap["ports"].([]interface{}); ok {
+			for _, port := range ports {
+				relation.Ports = append(relation.Ports, port.(EndpointPort))
+			}
+		}
 		if relMap["limit"] != nil {
 			// Schema defaults to int64, but we know
 			// the int range should be more than enough.
@@ -969,6 +1787,208 @@ func (m Meta) CombinedRelations() map[string]Relation {
 	return combined
 }
 
+// RequiredRelations returns the required relations that are not
+// optional: those that an application using the charm needs to be
+// related through in order to work.
+func (m Meta) RequiredRelations() map[string]Relation {
+	required := make(map[string]Relation)
+	for name, relation := range m.Requires {
+		if !relation.Optional {
+			required[name] = relation
+		}
+	}
+	return required
+}
+
+// SortedEndpoints returns all defined relations in a deterministic
+// order: the provided relations, then the required relations, then the
+// peer relations, each sorted by name. The implicit relations are not
+// included. The Name and Role of relations that leave them unset are
+// filled in from where they are defined.
+func (m Meta) SortedEndpoints() []Relation {
+	var endpoints []Relation
+	for _, section := range []struct {
+		role      RelationRole
+		relations map[string]Relation
+	}{
+		{RoleProvider, m.Provides},
+		{RoleRequirer, m.Requires},
+		{RolePeer, m.Peers},
+	} {
+		for _, name := range sortedRelationNames(section.relations) {
+			rel := section.relations[name]
+			if rel.Name == "" {
+				rel.Name = name
+			}
+			if rel.Role == "" {
+				rel.Role = section.role
+			}
+			endpoints = append(endpoints, rel)
+		}
+	}
+	return endpoints
+}
+
+// Equal reports whether m and other describe the same metadata. Unlike
+// reflect.DeepEqual, it considers nil and empty slices and maps to be
+// equal, so metadata that has been through a serialization round trip
+// compares equal to the original. The order of the tags, categories
+// and terms is not significant. The order of the series is, as the
+// first is the charm's default series.
+func (m *Meta) Equal(other *Meta) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+	a, b := *m, *other
+	if !sameStrings(a.Tags, b.Tags) || !sameStrings(a.Categories, b.Categories) || !sameStrings(a.Terms, b.Terms) {
+		return false
+	}
+	a.Tags, a.Categories, a.Terms = nil, nil, nil
+	b.Tags, b.Categories, b.Terms = nil, nil, nil
+	return semanticEqual(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+// sameStrings reports whether a and b hold the same strings the same
+// number of times, in any order.
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int)
+	for _, s := range a {
+		counts[s]++
+	}
+	for _, s := range b {
+		if counts[s] == 0 {
+			return false
+		}
+		counts[s]--
+	}
+	return true
+}
+
+// semanticEqual is like reflect.DeepEqual, except that nil and empty
+// slices and maps are equal. It does not handle cyclic values, which
+// do not occur in charm metadata.
+func semanticEqual(a, b reflect.Value) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+	switch a.Kind() {
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !semanticEqual(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for _, key := range a.MapKeys() {
+			bv := b.MapIndex(key)
+			if !bv.IsValid() || !semanticEqual(a.MapIndex(key), bv) {
+				return false
+			}
+		}
+		return true
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return semanticEqual(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !semanticEqual(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float()
+	case reflect.String:
+		return a.String() == b.String()
+	}
+	// Functions, channels and the like never appear in metadata.
+	return false
+}
+
+// Copy returns a deep copy of the metadata, which shares no maps, slices
+// or pointers with m, so that either may be changed without affecting
+// the other.
+func (m *Meta) Copy() *Meta {
+	if m == nil {
+		return nil
+	}
+	return deepCopy(reflect.ValueOf(m)).Interface().(*Meta)
+}
+
+// deepCopy returns a copy of v that shares no maps, slices or pointers
+// with it. Like semanticEqual, it does not handle cyclic values.
+// Unexported struct fields are copied as they are.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Interface:
+		c := reflect.New(v.Type()).Elem()
+		if !v.IsNil() {
+			c.Set(deepCopy(v.Elem()))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return c
+	}
+	return v
+}
+
 // Schema coercer that expands the interface shorthand notation.
 // A consistent format is easier to work with than considering the
 // potential difference everywhere.
@@ -1025,16 +2045,26 @@ func (c ifaceExpC) Coerce(v interface{}, path []string) (newv interface{}, err e
 	return ifaceSchema.Coerce(m, path)
 }
 
+var ifaceFields = schema.Fields{
+	"interface":         schema.String(),
+	"interface-aliases": schema.List(schema.String()),
+	"limit":             schema.OneOf(schema.Const(nil), schema.Int()),
+	"scope":             schema.OneOf(schema.Const(string(ScopeGlobal)), schema.Const(string(ScopeContainer))),
+	"optional":          schema.Bool(),
+	"ports":             schema.List(endpointPortC{}),
+	"description":       schema.String(),
+	"renamed-from":      schema.String(),
+}
+
 var ifaceSchema = schema.FieldMap(
-	schema.Fields{
-		"interface": schema.String(),
-		"limit":     schema.OneOf(schema.Const(nil), schema.Int()),
-		"scope":     schema.OneOf(schema.Const(string(ScopeGlobal)), schema.Const(string(ScopeContainer))),
-		"optional":  schema.Bool(),
-	},
+	ifaceFields,
 	schema.Defaults{
-		"scope":    string(ScopeGlobal),
-		"optional": false,
+		"scope":             string(ScopeGlobal),
+		"optional":          false,
+		"interface-aliases": schema.Omit,
+		"ports":             schema.Omit,
+		"description":       schema.Omit,
+		"renamed-from":      schema.Omit,
 	},
 )
 
@@ -1104,6 +2134,10 @@ func parseDevices(devices interface{}) map[string]Device {
 	return result
 }
 
+// validDeploymentMinVersion matches the dotted numeric versions, such as
+// "1.15" or "1.15.2", accepted as a deployment min-version.
+var validDeploymentMinVersion = regexp.MustCompile(`^[0-9]+(\.[0-9]+){0,2}$`)
+
 func parseDeployment(deployment interface{}, charmSeries []string, storage map[string]Storage) (*Deployment, error) {
 	if deployment == nil {
 		return nil, nil
@@ -1126,6 +2160,9 @@ func parseDeployment(deployment interface{}, charmSeries []string, storage map[s
 		result.ServiceType = ServiceType(serviceType)
 	}
 	if minVersion, ok := deploymentMap["min-version"].(string); ok {
+		if !validDeploymentMinVersion.MatchString(minVersion) {
+			return nil, errors.NotValidf("deployment min-version %q", minVersion)
+		}
 		result.MinVersion = minVersion
 	}
 	if result.ServiceType != "" {
@@ -1190,6 +2227,34 @@ func parseSystems(input interface{}, resources map[string]resource.Meta, disallo
 	return res, nil
 }
 
+func parseBases(input interface{}) ([]Base, error) {
+	if input == nil {
+		return nil, nil
+	}
+	res := []Base(nil)
+	for _, v := range input.([]interface{}) {
+		baseMap := v.(map[string]interface{})
+		base := Base{
+			Name: baseMap["name"].(string),
+		}
+		var err error
+		base.Channel, err = channel.Parse(baseMap["channel"].(string))
+		if err != nil {
+			return nil, errors.Annotatef(err, "parsing channel %q", baseMap["channel"])
+		}
+		if archs, ok := baseMap["architectures"].([]interface{}); ok {
+			for _, arch := range archs {
+				base.Architectures = append(base.Architectures, Architecture(arch.(string)))
+			}
+		}
+		if err := base.Validate(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		res = append(res, base)
+	}
+	return res, nil
+}
+
 func parsePlatforms(input interface{}) ([]Platform, error) {
 	if input == nil {
 		return nil, nil
@@ -1268,22 +2333,24 @@ func parseMounts(input interface{}, storage map[string]Storage) ([]Mount, error)
 	return mounts, nil
 }
 
+var storageFields = schema.Fields{
+	"type":      schema.OneOf(schema.Const(string(StorageBlock)), schema.Const(string(StorageFilesystem))),
+	"shared":    schema.Bool(),
+	"read-only": schema.Bool(),
+	"multiple": schema.FieldMap(
+		schema.Fields{
+			"range": storageCountC{}, // m, m-n, m+, m-
+		},
+		schema.Defaults{},
+	),
+	"minimum-size": storageSizeC{},
+	"location":     schema.String(),
+	"description":  schema.String(),
+	"properties":   schema.List(propertiesC{}),
+}
+
 var storageSchema = schema.FieldMap(
-	schema.Fields{
-		"type":      schema.OneOf(schema.Const(string(StorageBlock)), schema.Const(string(StorageFilesystem))),
-		"shared":    schema.Bool(),
-		"read-only": schema.Bool(),
-		"multiple": schema.FieldMap(
-			schema.Fields{
-				"range": storageCountC{}, // m, m-n, m+, m-
-			},
-			schema.Defaults{},
-		),
-		"minimum-size": storageSizeC{},
-		"location":     schema.String(),
-		"description":  schema.String(),
-		"properties":   schema.List(propertiesC{}),
-	},
+	storageFields,
 	schema.Defaults{
 		"shared":       false,
 		"read-only":    false,
@@ -1295,13 +2362,16 @@ var storageSchema = schema.FieldMap(
 	},
 )
 
+var deviceFields = schema.Fields{
+	"description": schema.String(),
+	"type":        schema.String(),
+	"countmin":    deviceCountC{},
+	"countmax":    deviceCountC{},
+}
+
 var deviceSchema = schema.FieldMap(
-	schema.Fields{
-		"description": schema.String(),
-		"type":        schema.String(),
-		"countmin":    deviceCountC{},
-		"countmax":    deviceCountC{},
-	}, schema.Defaults{
+	deviceFields,
+	schema.Defaults{
 		"description": schema.Omit,
 		"countmin":    schema.Omit,
 		"countmax":    schema.Omit,
@@ -1361,6 +2431,41 @@ func (c storageCountC) Coerce(v interface{}, path []string) (newv interface{}, e
 	return [2]int{m, n}, nil
 }
 
+// CountRangeString returns the storage count range in the form accepted
+// by the "range" attribute of the "multiple" storage section: "m" when
+// the minimum and maximum are the same, "m-n" for a bounded range, and
+// "m-" when there is no upper bound. It is the inverse of the parsing
+// done by storageCountC.
+func (s Storage) CountRangeString() string {
+	switch {
+	case s.CountMax == -1:
+		return fmt.Sprintf("%d-", s.CountMin)
+	case s.CountMin == s.CountMax:
+		return strconv.Itoa(s.CountMin)
+	}
+	return fmt.Sprintf("%d-%d", s.CountMin, s.CountMax)
+}
+
+// urlListC accepts either a single URL or a list of URLs, and
+// coerces both to a list.
+type urlListC struct{}
+
+func (c urlListC) Coerce(v interface{}, path []string) (newv interface{}, err error) {
+	if s, err := stringC.Coerce(v, path); err == nil {
+		return []interface{}{s}, nil
+	}
+	return schema.List(stringC).Coerce(v, path)
+}
+
+// maxStorageMinimumSize is the largest minimum size, in MiB, whose
+// size in bytes fits in a uint64.
+const maxStorageMinimumSize = math.MaxUint64 >> 20
+
+// MinimumSizeBytes returns the minimum size of the store in bytes.
+func (s Storage) MinimumSizeBytes() uint64 {
+	return s.MinimumSize * 1024 * 1024
+}
+
 type storageSizeC struct{}
 
 func (c storageSizeC) Coerce(v interface{}, path []string) (newv interface{}, err error) {
@@ -1374,28 +2479,31 @@ func (c storageSizeC) Coerce(v interface{}, path []string) (newv interface{}, er
 type propertiesC struct{}
 
 func (c propertiesC) Coerce(v interface{}, path []string) (newv interface{}, err error) {
-	return schema.OneOf(schema.Const("transient")).Coerce(v, path)
+	return schema.OneOf(schema.Const(StoragePropertyTransient)).Coerce(v, path)
+}
+
+var deploymentFields = schema.Fields{
+	"type": schema.OneOf(
+		schema.Const(string(DeploymentStateful)),
+		schema.Const(string(DeploymentStateless)),
+		schema.Const(string(DeploymentDaemon)),
+	),
+	"mode": schema.OneOf(
+		schema.Const(string(ModeOperator)),
+		schema.Const(string(ModeWorkload)),
+	),
+	"service": schema.OneOf(
+		schema.Const(string(ServiceCluster)),
+		schema.Const(string(ServiceLoadBalancer)),
+		schema.Const(string(ServiceExternal)),
+		schema.Const(string(ServiceOmit)),
+	),
+	"min-version": schema.String(),
 }
 
 var deploymentSchema = schema.FieldMap(
-	schema.Fields{
-		"type": schema.OneOf(
-			schema.Const(string(DeploymentStateful)),
-			schema.Const(string(DeploymentStateless)),
-			schema.Const(string(DeploymentDaemon)),
-		),
-		"mode": schema.OneOf(
-			schema.Const(string(ModeOperator)),
-			schema.Const(string(ModeWorkload)),
-		),
-		"service": schema.OneOf(
-			schema.Const(string(ServiceCluster)),
-			schema.Const(string(ServiceLoadBalancer)),
-			schema.Const(string(ServiceExternal)),
-			schema.Const(string(ServiceOmit)),
-		),
-		"min-version": schema.String(),
-	}, schema.Defaults{
+	deploymentFields,
+	schema.Defaults{
 		"type":        schema.Omit,
 		"mode":        string(ModeWorkload),
 		"service":     schema.Omit,
@@ -1403,69 +2511,106 @@ var deploymentSchema = schema.FieldMap(
 	},
 )
 
+var systemFields = schema.Fields{
+	"os": schema.OneOf(
+		schema.Const(systems.Ubuntu),
+		schema.Const(systems.Windows),
+		schema.Const(systems.CentOS),
+		schema.Const(systems.OpenSUSE),
+		schema.Const(systems.GenericLinux),
+		schema.Const(systems.OSX),
+	),
+	"channel":  schema.String(),
+	"resource": schema.String(),
+}
+
 var systemSchema = schema.FieldMap(
-	schema.Fields{
-		"os": schema.OneOf(
-			schema.Const(systems.Ubuntu),
-			schema.Const(systems.Windows),
-			schema.Const(systems.CentOS),
-			schema.Const(systems.OpenSUSE),
-			schema.Const(systems.GenericLinux),
-			schema.Const(systems.OSX),
-		),
-		"channel":  schema.String(),
-		"resource": schema.String(),
-	}, schema.Defaults{
+	systemFields,
+	schema.Defaults{
 		"os":       schema.Omit,
 		"channel":  schema.Omit,
 		"resource": schema.Omit,
 	})
 
+var baseFields = schema.Fields{
+	"name":          schema.String(),
+	"channel":       schema.String(),
+	"architectures": schema.List(schema.String()),
+}
+
+var baseSchema = schema.FieldMap(
+	baseFields,
+	schema.Defaults{
+		"architectures": schema.Omit,
+	})
+
+var containerFields = schema.Fields{
+	"systems": schema.List(systemSchema),
+	"mounts":  schema.List(mountSchema),
+}
+
 var containerSchema = schema.FieldMap(
-	schema.Fields{
-		"systems": schema.List(systemSchema),
-		"mounts":  schema.List(mountSchema),
-	}, schema.Defaults{
+	containerFields,
+	schema.Defaults{
 		"systems": schema.Omit,
 		"mounts":  schema.Omit,
 	})
 
+var mountFields = schema.Fields{
+	"storage":  schema.String(),
+	"location": schema.String(),
+}
+
 var mountSchema = schema.FieldMap(
-	schema.Fields{
-		"storage":  schema.String(),
-		"location": schema.String(),
-	}, schema.Defaults{
+	mountFields,
+	schema.Defaults{
 		"storage":  schema.Omit,
 		"location": schema.Omit,
 	})
 
+var charmFields = schema.Fields{
+	"name":             schema.String(),
+	"summary":          schema.String(),
+	"description":      schema.String(),
+	"peers":            schema.StringMap(ifaceExpander(nil)),
+	"provides":         schema.StringMap(ifaceExpander(nil)),
+	"requires":         schema.StringMap(ifaceExpander(nil)),
+	"extra-bindings":   extraBindingsSchema,
+	"revision":         schema.Int(), // Obsolete
+	"format":           schema.Int(), // Obsolete
+	"subordinate":      schema.Bool(),
+	"categories":       schema.List(schema.String()),
+	"tags":             schema.List(schema.String()),
+	"series":           schema.List(schema.String()),
+	"storage":          schema.StringMap(storageSchema),
+	"devices":          schema.StringMap(deviceSchema),
+	"deployment":       deploymentSchema,
+	"payloads":         schema.StringMap(payloadClassSchema),
+	"resources":        schema.StringMap(resourceSchema),
+	"terms":            schema.List(schema.String()),
+	"min-juju-version": schema.String(),
+	"charm-user": schema.OneOf(
+		schema.Const(string(CharmUserRoot)),
+		schema.Const(string(CharmUserSudoer)),
+		schema.Const(string(CharmUserNonRoot)),
+	),
+	"charm-user-group": schema.String(),
+	"website":          urlListC{},
+	"docs":             urlListC{},
+	"issues":           urlListC{},
+	"summary-i18n":     schema.StringMap(schema.String()),
+	"description-i18n": schema.StringMap(schema.String()),
+	"deprecated":       schema.OneOf(schema.Bool(), deprecationSchema),
+	"superseded-by":    schema.String(),
+	"platforms":        schema.List(schema.String()),
+	"architectures":    schema.List(schema.String()),
+	"systems":          schema.List(systemSchema),
+	"bases":            schema.List(baseSchema),
+	"containers":       schema.StringMap(containerSchema),
+}
+
 var charmSchema = schema.FieldMap(
-	schema.Fields{
-		"name":             schema.String(),
-		"summary":          schema.String(),
-		"description":      schema.String(),
-		"peers":            schema.StringMap(ifaceExpander(nil)),
-		"provides":         schema.StringMap(ifaceExpander(nil)),
-		"requires":         schema.StringMap(ifaceExpander(nil)),
-		"extra-bindings":   extraBindingsSchema,
-		"revision":         schema.Int(), // Obsolete
-		"format":           schema.Int(), // Obsolete
-		"subordinate":      schema.Bool(),
-		"categories":       schema.List(schema.String()),
-		"tags":             schema.List(schema.String()),
-		"series":           schema.List(schema.String()),
-		"storage":          schema.StringMap(storageSchema),
-		"devices":          schema.StringMap(deviceSchema),
-		"deployment":       deploymentSchema,
-		"payloads":         schema.StringMap(payloadClassSchema),
-		"resources":        schema.StringMap(resourceSchema),
-		"terms":            schema.List(schema.String()),
-		"min-juju-version": schema.String(),
-		"platforms":        schema.List(schema.String()),
-		"architectures":    schema.List(schema.String()),
-		"systems":          schema.List(systemSchema),
-		"containers":       schema.StringMap(containerSchema),
-	},
+	charmFields,
 	schema.Defaults{
 		"provides":         schema.Omit,
 		"requires":         schema.Omit,
@@ -1484,9 +2629,19 @@ var charmSchema = schema.FieldMap(
 		"resources":        schema.Omit,
 		"terms":            schema.Omit,
 		"min-juju-version": schema.Omit,
+		"charm-user":       schema.Omit,
+		"charm-user-group": schema.Omit,
+		"website":          schema.Omit,
+		"docs":             schema.Omit,
+		"issues":           schema.Omit,
+		"summary-i18n":     schema.Omit,
+		"description-i18n": schema.Omit,
+		"deprecated":       schema.Omit,
+		"superseded-by":    schema.Omit,
 		"platforms":        schema.Omit,
 		"architectures":    schema.Omit,
 		"systems":          schema.Omit,
+		"bases":            schema.Omit,
 		"containers":       schema.Omit,
 	},
 )
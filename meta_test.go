@@ -5,13 +5,17 @@ package charm_test
 
 import (
 	"bytes"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/systems"
 	"github.com/juju/systems/channel"
 	jc "github.com/juju/testing/checkers"
@@ -21,6 +25,7 @@ import (
 	yamlv2 "gopkg.in/yaml.v2"
 
 	"github.com/juju/charm/v8"
+	"github.com/juju/charm/v8/hooks"
 	"github.com/juju/charm/v8/resource"
 )
 
@@ -332,7 +337,7 @@ func (s *MetaSuite) TestScopeConstraint(c *gc.C) {
 func (s *MetaSuite) TestParseMetaRelations(c *gc.C) {
 	meta, err := charm.ReadMeta(repoMeta(c, "mysql"))
 	c.Assert(err, gc.IsNil)
-	c.Assert(meta.Provides["server"], gc.Equals, charm.Relation{
+	c.Assert(meta.Provides["server"], jc.DeepEquals, charm.Relation{
 		Name:      "server",
 		Role:      charm.RoleProvider,
 		Interface: "mysql",
@@ -343,19 +348,19 @@ func (s *MetaSuite) TestParseMetaRelations(c *gc.C) {
 
 	meta, err = charm.ReadMeta(repoMeta(c, "riak"))
 	c.Assert(err, gc.IsNil)
-	c.Assert(meta.Provides["endpoint"], gc.Equals, charm.Relation{
+	c.Assert(meta.Provides["endpoint"], jc.DeepEquals, charm.Relation{
 		Name:      "endpoint",
 		Role:      charm.RoleProvider,
 		Interface: "http",
 		Scope:     charm.ScopeGlobal,
 	})
-	c.Assert(meta.Provides["admin"], gc.Equals, charm.Relation{
+	c.Assert(meta.Provides["admin"], jc.DeepEquals, charm.Relation{
 		Name:      "admin",
 		Role:      charm.RoleProvider,
 		Interface: "http",
 		Scope:     charm.ScopeGlobal,
 	})
-	c.Assert(meta.Peers["ring"], gc.Equals, charm.Relation{
+	c.Assert(meta.Peers["ring"], jc.DeepEquals, charm.Relation{
 		Name:      "ring",
 		Role:      charm.RolePeer,
 		Interface: "riak",
@@ -365,14 +370,14 @@ func (s *MetaSuite) TestParseMetaRelations(c *gc.C) {
 
 	meta, err = charm.ReadMeta(repoMeta(c, "terracotta"))
 	c.Assert(err, gc.IsNil)
-	c.Assert(meta.Provides["dso"], gc.Equals, charm.Relation{
+	c.Assert(meta.Provides["dso"], jc.DeepEquals, charm.Relation{
 		Name:      "dso",
 		Role:      charm.RoleProvider,
 		Interface: "terracotta",
 		Optional:  true,
 		Scope:     charm.ScopeGlobal,
 	})
-	c.Assert(meta.Peers["server-array"], gc.Equals, charm.Relation{
+	c.Assert(meta.Peers["server-array"], jc.DeepEquals, charm.Relation{
 		Name:      "server-array",
 		Role:      charm.RolePeer,
 		Interface: "terracotta-server",
@@ -382,20 +387,20 @@ func (s *MetaSuite) TestParseMetaRelations(c *gc.C) {
 
 	meta, err = charm.ReadMeta(repoMeta(c, "wordpress"))
 	c.Assert(err, gc.IsNil)
-	c.Assert(meta.Provides["url"], gc.Equals, charm.Relation{
+	c.Assert(meta.Provides["url"], jc.DeepEquals, charm.Relation{
 		Name:      "url",
 		Role:      charm.RoleProvider,
 		Interface: "http",
 		Scope:     charm.ScopeGlobal,
 	})
-	c.Assert(meta.Requires["db"], gc.Equals, charm.Relation{
+	c.Assert(meta.Requires["db"], jc.DeepEquals, charm.Relation{
 		Name:      "db",
 		Role:      charm.RoleRequirer,
 		Interface: "mysql",
 		Limit:     1,
 		Scope:     charm.ScopeGlobal,
 	})
-	c.Assert(meta.Requires["cache"], gc.Equals, charm.Relation{
+	c.Assert(meta.Requires["cache"], jc.DeepEquals, charm.Relation{
 		Name:      "cache",
 		Role:      charm.RoleRequirer,
 		Interface: "varnish",
@@ -407,19 +412,19 @@ func (s *MetaSuite) TestParseMetaRelations(c *gc.C) {
 
 	meta, err = charm.ReadMeta(repoMeta(c, "monitoring"))
 	c.Assert(err, gc.IsNil)
-	c.Assert(meta.Provides["monitoring-client"], gc.Equals, charm.Relation{
+	c.Assert(meta.Provides["monitoring-client"], jc.DeepEquals, charm.Relation{
 		Name:      "monitoring-client",
 		Role:      charm.RoleProvider,
 		Interface: "monitoring",
 		Scope:     charm.ScopeGlobal,
 	})
-	c.Assert(meta.Requires["monitoring-port"], gc.Equals, charm.Relation{
+	c.Assert(meta.Requires["monitoring-port"], jc.DeepEquals, charm.Relation{
 		Name:      "monitoring-port",
 		Role:      charm.RoleRequirer,
 		Interface: "monitoring",
 		Scope:     charm.ScopeContainer,
 	})
-	c.Assert(meta.Requires["info"], gc.Equals, charm.Relation{
+	c.Assert(meta.Requires["info"], jc.DeepEquals, charm.Relation{
 		Name:      "info",
 		Role:      charm.RoleRequirer,
 		Interface: "juju-info",
@@ -457,6 +462,58 @@ func (s *MetaSuite) TestCombinedRelations(c *gc.C) {
 	})
 }
 
+func (s *MetaSuite) TestRequiredRelations(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(dummyMetadata + `
+provides:
+  website: http
+requires:
+  db: mysql
+  cache:
+    interface: memcache
+    optional: true
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	required := meta.RequiredRelations()
+	c.Assert(required, gc.HasLen, 1)
+	c.Assert(required["db"].Interface, gc.Equals, "mysql")
+	c.Assert((&charm.Meta{}).RequiredRelations(), gc.HasLen, 0)
+}
+
+func (s *MetaSuite) TestSortedEndpoints(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(dummyMetadata + `
+provides:
+  website: http
+  admin: http
+requires:
+  db: mysql
+  cache: memcache
+peers:
+  ring: riak
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	var names []string
+	for _, rel := range meta.SortedEndpoints() {
+		names = append(names, string(rel.Role)+":"+rel.Name)
+	}
+	c.Assert(names, jc.DeepEquals, []string{
+		"provider:admin",
+		"provider:website",
+		"requirer:cache",
+		"requirer:db",
+		"peer:ring",
+	})
+
+	meta = &charm.Meta{
+		Requires: map[string]charm.Relation{"db": {Interface: "mysql"}},
+	}
+	c.Assert(meta.SortedEndpoints(), jc.DeepEquals, []charm.Relation{{
+		Name:      "db",
+		Role:      charm.RoleRequirer,
+		Interface: "mysql",
+	}})
+	c.Assert((&charm.Meta{}).SortedEndpoints(), gc.HasLen, 0)
+}
+
 var relationsConstraintsTests = []struct {
 	rels string
 	err  string
@@ -592,6 +649,277 @@ func (s *MetaSuite) TestNoMinJujuVersion(c *gc.C) {
 	c.Check(meta.MinJujuVersion, gc.Equals, version.Zero)
 }
 
+func (s *MetaSuite) TestCharmUser(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(dummyMetadata))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.CharmUser, gc.Equals, charm.CharmUser(""))
+	c.Check(meta.RequiresRoot(), jc.IsTrue)
+
+	for _, user := range []charm.CharmUser{charm.CharmUserRoot, charm.CharmUserSudoer, charm.CharmUserNonRoot} {
+		meta, err := charm.ReadMeta(strings.NewReader(fmt.Sprintf("%s\ncharm-user: %s\ncharm-user-group: juju_ops\n", dummyMetadata, user)))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(meta.CharmUser, gc.Equals, user)
+		c.Check(meta.CharmUserGroup, gc.Equals, "juju_ops")
+		c.Check(meta.RequiresRoot(), gc.Equals, user == charm.CharmUserRoot)
+	}
+}
+
+func (s *MetaSuite) TestCharmUserErrors(c *gc.C) {
+	_, err := charm.ReadMeta(strings.NewReader(dummyMetadata + "\ncharm-user: admin\n"))
+	c.Check(err, gc.ErrorMatches, `metadata: charm-user: unexpected value "admin"`)
+
+	_, err = charm.ReadMeta(strings.NewReader(dummyMetadata + "\ncharm-user-group: Bad Group\n"))
+	c.Check(err, gc.ErrorMatches, `charm "a" has invalid charm-user-group "Bad Group"`)
+
+	meta := charm.Meta{Name: "a", CharmUser: "admin"}
+	c.Check(meta.Check(), gc.ErrorMatches, `charm "a" has invalid charm-user "admin"`)
+}
+
+func (s *MetaSuite) TestProjectLinks(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(dummyMetadata + `
+website: https://example.com
+docs: [https://example.com/docs, "http://docs.example.com/a?b=c"]
+issues:
+  - https://bugs.example.com/a
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.Website, jc.DeepEquals, []string{"https://example.com"})
+	c.Check(meta.Docs, jc.DeepEquals, []string{"https://example.com/docs", "http://docs.example.com/a?b=c"})
+	c.Check(meta.Issues, jc.DeepEquals, []string{"https://bugs.example.com/a"})
+}
+
+func (s *MetaSuite) TestProjectLinksErrors(c *gc.C) {
+	for i, test := range []struct {
+		yaml string
+		err  string
+	}{{
+		yaml: "website: example.com",
+		err:  `charm "a" has invalid website URL "example.com": scheme must be http or https`,
+	}, {
+		yaml: "docs: [ftp://example.com/docs]",
+		err:  `charm "a" has invalid docs URL "ftp://example.com/docs": scheme must be http or https`,
+	}, {
+		yaml: "issues: https:///issues",
+		err:  `charm "a" has invalid issues URL "https:///issues": host must be specified`,
+	}, {
+		yaml: "website: {url: https://example.com}",
+		err:  `metadata: website: expected list, got map.*`,
+	}} {
+		c.Logf("test %d: %s", i, test.yaml)
+		_, err := charm.ReadMeta(strings.NewReader(dummyMetadata + "\n" + test.yaml + "\n"))
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *MetaSuite) TestLocalized(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(`
+name: a
+summary: A database.
+description: A fast database.
+summary-i18n:
+  fr: Une base de données.
+  pt: Um banco de dados.
+description-i18n:
+  pt_BR: Um banco de dados rápido.
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.SummaryI18n, jc.DeepEquals, map[string]string{
+		"fr": "Une base de données.",
+		"pt": "Um banco de dados.",
+	})
+	for i, test := range []struct {
+		lang        string
+		summary     string
+		description string
+	}{{
+		lang:        "",
+		summary:     "A database.",
+		description: "A fast database.",
+	}, {
+		lang:        "fr",
+		summary:     "Une base de données.",
+		description: "A fast database.",
+	}, {
+		lang:        "fr_CA",
+		summary:     "Une base de données.",
+		description: "A fast database.",
+	}, {
+		lang:        "pt-br",
+		summary:     "Um banco de dados.",
+		description: "Um banco de dados rápido.",
+	}, {
+		lang:        "de",
+		summary:     "A database.",
+		description: "A fast database.",
+	}} {
+		c.Logf("test %d: %q", i, test.lang)
+		summary, description := meta.Localized(test.lang)
+		c.Check(summary, gc.Equals, test.summary)
+		c.Check(description, gc.Equals, test.description)
+	}
+}
+
+func (s *MetaSuite) TestLocalizedErrors(c *gc.C) {
+	_, err := charm.ReadMeta(strings.NewReader(dummyMetadata + "\nsummary-i18n:\n  French: Une base de données.\n"))
+	c.Check(err, gc.ErrorMatches, `charm "a" has invalid summary-i18n locale "French"`)
+	_, err = charm.ReadMeta(strings.NewReader(dummyMetadata + "\ndescription-i18n: [fr]\n"))
+	c.Check(err, gc.ErrorMatches, `metadata: description-i18n: expected map, got .*`)
+}
+
+func (s *MetaSuite) TestDeprecation(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(dummyMetadata + `
+deprecated:
+  date: 2026-04-01
+  message: No longer maintained.
+superseded-by: b
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.Deprecated, jc.DeepEquals, &charm.Deprecation{
+		Date:    time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC),
+		Message: "No longer maintained.",
+	})
+	c.Check(meta.SupersededBy, gc.Equals, "b")
+	c.Check(meta.IsDeprecated(), jc.IsTrue)
+	c.Check(meta.DeprecationWarnings(), jc.DeepEquals, []string{
+		`charm "a" is deprecated since 2026-04-01: No longer maintained.; use "b" instead`,
+	})
+
+	meta, err = charm.ReadMeta(strings.NewReader(dummyMetadata + "\ndeprecated: true\n"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.Deprecated, jc.DeepEquals, &charm.Deprecation{})
+	c.Check(meta.DeprecationWarnings(), jc.DeepEquals, []string{`charm "a" is deprecated`})
+
+	meta, err = charm.ReadMeta(strings.NewReader(dummyMetadata + "\ndeprecated: false\n"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.Deprecated, gc.IsNil)
+	c.Check(meta.IsDeprecated(), jc.IsFalse)
+	c.Check(meta.DeprecationWarnings(), gc.HasLen, 0)
+}
+
+func (s *MetaSuite) TestDeprecationErrors(c *gc.C) {
+	for i, test := range []struct {
+		yaml string
+		err  string
+	}{{
+		yaml: "deprecated: {date: 1 April 2026}",
+		err:  `invalid deprecated: date "1 April 2026" not valid`,
+	}, {
+		yaml: "deprecated: soon",
+		err:  `metadata: deprecated: .*`,
+	}, {
+		yaml: "superseded-by: a",
+		err:  `charm "a" superseded by itself`,
+	}, {
+		yaml: "superseded-by: Bad_Name",
+		err:  `charm "a" superseded by invalid charm name "Bad_Name"`,
+	}} {
+		c.Logf("test %d: %s", i, test.yaml)
+		_, err := charm.ReadMeta(strings.NewReader(dummyMetadata + "\n" + test.yaml + "\n"))
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *MetaSuite) TestRelationDescription(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(dummyMetadata + `
+provides:
+  website:
+    interface: http
+    description: The public web site.
+requires:
+  db: mysql
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.Provides["website"].Description, gc.Equals, "The public web site.")
+	c.Check(meta.Requires["db"].Description, gc.Equals, "")
+
+	data, err := yaml.Marshal(meta)
+	c.Assert(err, jc.ErrorIsNil)
+	var raw map[string]interface{}
+	err = yaml.Unmarshal(data, &raw)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(raw["provides"], jc.DeepEquals, map[interface{}]interface{}{
+		"website": map[interface{}]interface{}{
+			"interface":   "http",
+			"description": "The public web site.",
+		},
+	})
+	c.Check(raw["requires"], jc.DeepEquals, map[interface{}]interface{}{
+		"db": "mysql",
+	})
+}
+
+func (s *MetaSuite) TestEqual(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(dummyMetadata + `
+provides:
+  website: http
+storage:
+  data:
+    type: filesystem
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := yaml.Marshal(meta)
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := charm.ReadMeta(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.Equal(other), jc.IsTrue)
+
+	// Nil and empty collections are equal.
+	other.Tags = []string{}
+	other.Peers = map[string]charm.Relation{}
+	storage := other.Storage["data"]
+	storage.Properties = []string{}
+	other.Storage["data"] = storage
+	c.Check(meta.Equal(other), jc.IsTrue)
+	c.Check(other.Equal(meta), jc.IsTrue)
+
+	other.Tags = []string{"databases"}
+	c.Check(meta.Equal(other), jc.IsFalse)
+	other.Tags = nil
+
+	storage.ReadOnly = true
+	other.Storage["data"] = storage
+	c.Check(meta.Equal(other), jc.IsFalse)
+
+	c.Check(meta.Equal(nil), jc.IsFalse)
+	var nilMeta *charm.Meta
+	c.Check(nilMeta.Equal(nil), jc.IsTrue)
+}
+
+func (s *MetaSuite) TestEqualIgnoresOrder(c *gc.C) {
+	meta := &charm.Meta{
+		Name:       "a",
+		Tags:       []string{"databases", "monitoring", "databases"},
+		Categories: []string{"misc", "storage"},
+		Series:     []string{"focal", "jammy"},
+	}
+	other := &charm.Meta{
+		Name:       "a",
+		Tags:       []string{"monitoring", "databases", "databases"},
+		Categories: []string{"storage", "misc"},
+		Series:     []string{"focal", "jammy"},
+	}
+	c.Check(meta.Equal(other), jc.IsTrue)
+
+	other.Tags = []string{"databases", "monitoring", "monitoring"}
+	c.Check(meta.Equal(other), jc.IsFalse)
+	other.Tags = meta.Tags
+
+	// The first series is the default, so the order of series matters.
+	other.Series = []string{"jammy", "focal"}
+	c.Check(meta.Equal(other), jc.IsFalse)
+}
+
+func (s *MetaSuite) TestReadMetaWindowsLineEndings(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(
+		"\xef\xbb\xbfname: a\r\nsummary: b\r\ndescription: |\r\n  line one\r\n  line two\r\nseries:\r\n  - bionic\r\n",
+	))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.Name, gc.Equals, "a")
+	c.Assert(meta.Summary, gc.Equals, "b")
+	c.Assert(meta.Description, gc.Equals, "line one\nline two\n")
+	c.Assert(meta.Series, jc.DeepEquals, []string{"bionic"})
+}
+
 func (s *MetaSuite) TestCheckMismatchedRelationName(c *gc.C) {
 	// This  Check case cannot be covered by the above
 	// TestRelationsConstraints tests.
@@ -627,6 +955,51 @@ func (s *MetaSuite) TestCheckMismatchedRole(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `charm "foo" has mismatched relation name ""; expected "foo"`)
 }
 
+func (s *MetaSuite) TestValidateReportsAllProblems(c *gc.C) {
+	meta := charm.Meta{
+		Name:        "foo",
+		Subordinate: true,
+		Series:      []string{"focal", "Bad"},
+		Provides: map[string]charm.Relation{
+			"juju-info": {
+				Name:      "juju-info",
+				Role:      charm.RoleProvider,
+				Interface: "juju-info",
+				Scope:     charm.ScopeGlobal,
+			},
+		},
+		Storage: map[string]charm.Storage{
+			"data": {Name: "data", CountMax: 1},
+		},
+		CharmUser: "nobody",
+	}
+	err := meta.Validate()
+	errs, ok := err.(charm.ValidationErrors)
+	c.Assert(ok, jc.IsTrue)
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	c.Assert(messages, jc.DeepEquals, []string{
+		`charm "foo" using a reserved relation name: "juju-info"`,
+		`charm "foo" relation "juju-info" using a reserved interface: "juju-info"`,
+		`subordinate charm "foo" lacks "requires" relation with container scope`,
+		`charm "foo" declares invalid series: "Bad"`,
+		`charm "foo" storage "data": type must be specified`,
+		`charm "foo" has invalid charm-user "nobody"`,
+	})
+	c.Assert(err, gc.ErrorMatches, `charm "foo" using a reserved relation name: "juju-info"; .*`)
+
+	// Check returns the first of them.
+	c.Assert(meta.Check(), gc.ErrorMatches, messages[0])
+}
+
+func (s *MetaSuite) TestValidateValidMeta(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(dummyMetadata))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.Validate(), jc.ErrorIsNil)
+}
+
 func (s *MetaSuite) TestCheckMismatchedExtraBindingName(c *gc.C) {
 	meta := charm.Meta{
 		Name: "foo",
@@ -750,6 +1123,206 @@ func (s *MetaSuite) TestMetaHooks(c *gc.C) {
 	c.Assert(hooks, jc.DeepEquals, expectedHooks)
 }
 
+func (s *MetaSuite) TestReadMetaRaw(c *gc.C) {
+	data := `
+name: a
+summary: b
+description: c
+subordinate: true
+requires:
+  info:
+    interface: juju-info
+    scope: container
+x-custom:
+  nested: [1, 2]
+`
+	meta, fields, err := charm.ReadMetaRaw(strings.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	expect, err := charm.ReadMeta(strings.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta, jc.DeepEquals, expect)
+
+	c.Check(fields["name"], gc.Equals, "a")
+	c.Check(fields["subordinate"], gc.Equals, true)
+	c.Check(fields["x-custom"], jc.DeepEquals, map[string]interface{}{
+		"nested": []interface{}{1, 2},
+	})
+	requires, ok := fields["requires"].(map[string]interface{})
+	c.Assert(ok, jc.IsTrue, gc.Commentf("%#v", fields["requires"]))
+	c.Check(requires["info"], gc.NotNil)
+	_, ok = fields["series"]
+	c.Check(ok, jc.IsFalse)
+
+	_, _, err = charm.ReadMetaRaw(strings.NewReader("name: a\nsummary: b\ndescription: c\nsubordinate: maybe\n"))
+	c.Assert(err, gc.ErrorMatches, `metadata: subordinate: expected bool, got string\("maybe"\)`)
+}
+
+func (s *MetaSuite) TestCheckName(c *gc.C) {
+	for _, name := range []string{"a", "wordpress", "postgresql-k8s", "mysql8", "a-b-c"} {
+		meta := charm.Meta{Name: name}
+		c.Check(meta.Check(), jc.ErrorIsNil, gc.Commentf("name %q", name))
+	}
+	for _, name := range []string{"Foo", "a_b", "-a", "a-", "a--b", "8ball", "mysql-8", "a b"} {
+		meta := charm.Meta{Name: name}
+		c.Check(meta.Check(), gc.ErrorMatches, fmt.Sprintf("invalid charm name %q", name))
+	}
+}
+
+func (s *MetaSuite) TestCheckInterface(c *gc.C) {
+	for _, ifce := range []string{"http", "juju-info", "postgresql_client", "k8s-service", strings.Repeat("a", 64)} {
+		c.Check(charm.IsValidInterface(ifce), jc.IsTrue, gc.Commentf("interface %q", ifce))
+	}
+	for _, ifce := range []string{"", "My SQL", "MySQL", "my sql", "-http", "http-", "http--x", "1http", "a/b", strings.Repeat("a", 65)} {
+		c.Check(charm.IsValidInterface(ifce), jc.IsFalse, gc.Commentf("interface %q", ifce))
+		meta := charm.Meta{
+			Name: "a",
+			Provides: map[string]charm.Relation{
+				"server": {Name: "server", Role: charm.RoleProvider, Interface: ifce, Scope: charm.ScopeGlobal},
+			},
+		}
+		err := meta.Check()
+		c.Check(err, gc.ErrorMatches, fmt.Sprintf(`charm "a" relation "server" has invalid interface %q`, ifce))
+		var fieldErr *charm.FieldError
+		c.Assert(stderrors.As(err, &fieldErr), jc.IsTrue)
+		c.Check(fieldErr.Path, gc.Equals, "provides.server.interface")
+	}
+}
+
+func (s *MetaSuite) TestInterfaceAliases(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(dummyMetadata + `
+provides:
+  db:
+    interface: postgresql
+    interface-aliases: [pgsql]
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	db := meta.Provides["db"]
+	c.Assert(db.InterfaceAliases, jc.DeepEquals, []string{"pgsql"})
+	c.Assert(db.Interfaces(), jc.DeepEquals, []string{"postgresql", "pgsql"})
+	c.Assert(db.HasInterface("pgsql"), jc.IsTrue)
+	c.Assert(db.HasInterface("mysql"), jc.IsFalse)
+
+	data, err := yaml.Marshal(meta)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.Contains, "interface-aliases:\n    - pgsql\n")
+	meta1, err := charm.ReadMeta(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta1.Provides["db"], jc.DeepEquals, db)
+
+	dir := c.MkDir()
+	err = ioutil.WriteFile(filepath.Join(dir, charm.MetadataFile), data, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	ch, err := charm.ReadCharmDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, ifce := range []string{"postgresql", "pgsql"} {
+		rel := charm.Relation{Name: "db", Role: charm.RoleProvider, Interface: ifce, Scope: charm.ScopeGlobal}
+		c.Check(rel.ImplementedBy(ch), jc.IsTrue, gc.Commentf("interface %q", ifce))
+		req := charm.Relation{Name: "db", Role: charm.RoleRequirer, Interface: ifce, Scope: charm.ScopeGlobal}
+		c.Check(db.CanRelateTo(req), jc.IsTrue, gc.Commentf("interface %q", ifce))
+	}
+	rel := charm.Relation{Name: "db", Role: charm.RoleProvider, Interface: "mysql", Scope: charm.ScopeGlobal}
+	c.Check(rel.ImplementedBy(ch), jc.IsFalse)
+}
+
+func (s *MetaSuite) TestCheckInterfaceAliases(c *gc.C) {
+	for i, test := range []struct {
+		section string
+		aliases string
+		err     string
+	}{{
+		section: "provides",
+		aliases: "[Pg SQL]",
+		err:     `charm "a" relation "db" has invalid interface alias "Pg SQL"`,
+	}, {
+		section: "provides",
+		aliases: "[pgsql, pgsql]",
+		err:     `charm "a" relation "db" has duplicated interface alias "pgsql"`,
+	}, {
+		section: "requires",
+		aliases: "[postgresql]",
+		err:     `charm "a" relation "db" has duplicated interface alias "postgresql"`,
+	}, {
+		section: "provides",
+		aliases: "[juju-db]",
+		err:     `charm "a" relation "db" using a reserved interface alias: "juju-db"`,
+	}, {
+		section: "peers",
+		aliases: "[pgsql]",
+		err:     `charm "a" peer relation "db" cannot have interface aliases`,
+	}} {
+		c.Logf("test %d: %s %s", i, test.section, test.aliases)
+		_, err := charm.ReadMeta(strings.NewReader(dummyMetadata + "\n" + test.section + ":\n  db:\n    interface: postgresql\n    interface-aliases: " + test.aliases + "\n"))
+		c.Check(err, gc.ErrorMatches, test.err)
+		var fieldErr *charm.FieldError
+		if c.Check(stderrors.As(err, &fieldErr), jc.IsTrue) {
+			c.Check(fieldErr.Path, gc.Equals, test.section+".db.interface-aliases")
+		}
+	}
+}
+
+func (s *MetaSuite) TestMetaAllHooks(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(`
+name: a
+summary: b
+description: c
+platforms: [kubernetes]
+systems:
+  - os: ubuntu
+    channel: 20.04/stable
+containers:
+  web:
+    resource: web-image
+resources:
+  web-image:
+    type: oci-image
+storage:
+  data:
+    type: filesystem
+provides:
+  db: mysql
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	actions := &charm.Actions{ActionSpecs: map[string]charm.ActionSpec{
+		"snapshot": {Description: "Take a snapshot."},
+	}}
+
+	expected := meta.Hooks()
+	c.Assert(expected["db-relation-joined"], jc.IsTrue)
+	c.Assert(expected["data-storage-attached"], jc.IsFalse)
+	expected["data-storage-attached"] = true
+	expected["data-storage-detaching"] = true
+	expected["web-pebble-ready"] = true
+	c.Assert(meta.AllHooks(nil), jc.DeepEquals, expected)
+
+	expected["snapshot"] = true
+	c.Assert(meta.AllHooks(actions), jc.DeepEquals, expected)
+}
+
+func (s *MetaSuite) TestParseRelationHook(c *gc.C) {
+	meta, err := charm.ReadMeta(repoMeta(c, "wordpress"))
+	c.Assert(err, gc.IsNil)
+	for i, test := range []struct {
+		hookName     string
+		relationName string
+		kind         hooks.Kind
+		ok           bool
+	}{
+		{"db-relation-changed", "db", hooks.RelationChanged, true},
+		{"logging-dir-relation-joined", "logging-dir", hooks.RelationJoined, true},
+		{"monitoring-port-relation-broken", "monitoring-port", hooks.RelationBroken, true},
+		{"unknown-relation-changed", "", "", false},
+		{"config-changed", "", "", false},
+		{"-relation-changed", "", "", false},
+		{"db-relation-foo", "", "", false},
+	} {
+		c.Logf("test %d: %q", i, test.hookName)
+		relationName, kind, ok := meta.ParseRelationHook(test.hookName)
+		c.Check(ok, gc.Equals, test.ok)
+		c.Check(relationName, gc.Equals, test.relationName)
+		c.Check(kind, gc.Equals, test.kind)
+	}
+}
+
 func (s *MetaSuite) TestCodecRoundTripEmpty(c *gc.C) {
 	for _, codec := range codecs {
 		c.Logf("codec %s", codec.Name)
@@ -765,7 +1338,7 @@ func (s *MetaSuite) TestCodecRoundTripEmpty(c *gc.C) {
 
 func (s *MetaSuite) TestCodecRoundTrip(c *gc.C) {
 	var input = charm.Meta{
-		Name:        "Foo",
+		Name:        "foo",
 		Summary:     "Bar",
 		Description: "Baz",
 		Subordinate: true,
@@ -830,7 +1403,7 @@ func (s *MetaSuite) TestCodecRoundTrip(c *gc.C) {
 
 func (s *MetaSuite) TestCodecRoundTripKubernetes(c *gc.C) {
 	var input = charm.Meta{
-		Name:        "Foo",
+		Name:        "foo",
 		Summary:     "Bar",
 		Description: "Baz",
 		Subordinate: true,
@@ -944,6 +1517,7 @@ var implementedByTests = []struct {
 	{"juju-info", "blah", charm.RoleProvider, charm.ScopeGlobal, false, false},
 	{"juju-info", "juju-info", charm.RoleRequirer, charm.ScopeGlobal, false, false},
 	{"juju-info", "juju-info", charm.RoleProvider, charm.ScopeContainer, true, true},
+	{"juju-info", "juju-info", charm.RoleRequirer, charm.ScopeContainer, false, true},
 
 	{"ifce-req", "req", charm.RoleRequirer, charm.ScopeGlobal, true, false},
 	{"blah", "req", charm.RoleRequirer, charm.ScopeGlobal, false, false},
@@ -978,6 +1552,156 @@ func (s *MetaSuite) TestImplementedBy(c *gc.C) {
 	}
 }
 
+func (s *MetaSuite) TestImplementedByMalformed(c *gc.C) {
+	for i, test := range []struct {
+		rel charm.Relation
+		err string
+	}{{
+		rel: charm.Relation{Name: "pro", Interface: "ifce-pro", Role: "bogus", Scope: charm.ScopeGlobal},
+		err: `relation role "bogus" not valid`,
+	}, {
+		rel: charm.Relation{Name: "pro", Interface: "ifce-pro", Role: charm.RoleProvider, Scope: "bogus"},
+		err: `relation scope "bogus" not valid`,
+	}, {
+		rel: charm.Relation{Name: "pro", Interface: "ifce-pro", Role: charm.RoleProvider},
+		err: `relation scope "" not valid`,
+	}} {
+		c.Logf("test %d", i)
+		c.Check(test.rel.ImplementedBy(&dummyCharm{}), jc.IsFalse)
+		ok, err := test.rel.CheckImplementedBy(&dummyCharm{})
+		c.Check(ok, jc.IsFalse)
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+	ok, err := charm.Relation{Name: "pro", Interface: "ifce-pro", Role: charm.RoleProvider, Scope: charm.ScopeGlobal}.CheckImplementedBy(&dummyCharm{})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(ok, jc.IsTrue)
+}
+
+func (s *MetaSuite) TestImplementedByLimit(c *gc.C) {
+	wordpress := readCharmDir(c, "wordpress")
+	for i, test := range []struct {
+		name  string
+		limit int
+		match bool
+	}{
+		{"cache", 0, true},
+		{"cache", 1, true},
+		{"cache", 2, true},
+		{"cache", 3, false},
+		{"db", 1, true},
+		{"db", 2, false},
+	} {
+		c.Logf("test %d: %s limit %d", i, test.name, test.limit)
+		rel := wordpress.Meta().Requires[test.name]
+		rel.Limit = test.limit
+		c.Check(rel.ImplementedBy(wordpress), gc.Equals, test.match)
+	}
+
+	rel := wordpress.Meta().Requires["db"]
+	rel.Limit = -1
+	ok, err := rel.CheckImplementedBy(wordpress)
+	c.Check(ok, jc.IsFalse)
+	c.Check(err, gc.ErrorMatches, `relation limit -1 not valid`)
+}
+
+func (s *MetaSuite) TestCheckNegativeLimit(c *gc.C) {
+	_, err := charm.ReadMeta(strings.NewReader(dummyMetadata + `
+provides:
+  website:
+    interface: http
+    limit: -2
+`))
+	c.Check(err, gc.ErrorMatches, `charm "a" relation "website" has negative limit -2`)
+}
+
+func (s *MetaSuite) TestImplicitRelations(c *gc.C) {
+	provider := charm.Relation{
+		Name:      "juju-info",
+		Role:      charm.RoleProvider,
+		Interface: "juju-info",
+		Scope:     charm.ScopeGlobal,
+	}
+	requirer := charm.Relation{
+		Name:      "juju-info",
+		Role:      charm.RoleRequirer,
+		Interface: "juju-info",
+		Scope:     charm.ScopeContainer,
+	}
+	meta := readCharmDir(c, "wordpress").Meta()
+	c.Assert(meta.ImplicitRelations(), jc.DeepEquals, []charm.Relation{provider})
+
+	meta = readCharmDir(c, "logging").Meta()
+	c.Assert(meta.ImplicitRelations(), jc.DeepEquals, []charm.Relation{provider, requirer})
+	for _, rel := range meta.ImplicitRelations() {
+		c.Check(rel.IsImplicit(), jc.IsTrue)
+		c.Check(rel.ImplementedBy(readCharmDir(c, "logging")), jc.IsTrue)
+	}
+
+	meta.Requires["juju-info"] = requirer
+	c.Assert(meta.ImplicitRelations(), jc.DeepEquals, []charm.Relation{provider})
+}
+
+var canRelateToTests = []struct {
+	role0, role1   charm.RelationRole
+	ifce0, ifce1   string
+	scope0, scope1 charm.RelationScope
+	match          bool
+}{
+	{charm.RoleProvider, charm.RoleRequirer, "http", "http", charm.ScopeGlobal, charm.ScopeGlobal, true},
+	{charm.RoleRequirer, charm.RoleProvider, "http", "http", charm.ScopeGlobal, charm.ScopeContainer, true},
+	{charm.RoleProvider, charm.RoleRequirer, "http", "mysql", charm.ScopeGlobal, charm.ScopeGlobal, false},
+	{charm.RoleProvider, charm.RoleProvider, "http", "http", charm.ScopeGlobal, charm.ScopeGlobal, false},
+	{charm.RoleRequirer, charm.RoleRequirer, "http", "http", charm.ScopeGlobal, charm.ScopeGlobal, false},
+	{charm.RolePeer, charm.RolePeer, "http", "http", charm.ScopeGlobal, charm.ScopeGlobal, false},
+	{charm.RoleProvider, charm.RolePeer, "http", "http", charm.ScopeGlobal, charm.ScopeGlobal, false},
+	{charm.RoleProvider, charm.RoleRequirer, "http", "http", "", charm.ScopeGlobal, false},
+	{charm.RoleProvider, charm.RoleRequirer, "http", "http", charm.ScopeGlobal, "blah", false},
+}
+
+func (s *MetaSuite) TestCanRelateTo(c *gc.C) {
+	for i, t := range canRelateToTests {
+		c.Logf("test %d", i)
+		r0 := charm.Relation{Name: "a", Role: t.role0, Interface: t.ifce0, Scope: t.scope0}
+		r1 := charm.Relation{Name: "b", Role: t.role1, Interface: t.ifce1, Scope: t.scope1}
+		c.Check(r0.CanRelateTo(r1), gc.Equals, t.match)
+		c.Check(r1.CanRelateTo(r0), gc.Equals, t.match)
+	}
+}
+
+func (s *MetaSuite) TestCanRelate(c *gc.C) {
+	wordpress := readCharmDir(c, "wordpress").Meta()
+	mysql := readCharmDir(c, "mysql").Meta()
+	logging := readCharmDir(c, "logging").Meta()
+
+	for i, test := range []struct {
+		metaA     *charm.Meta
+		endpointA string
+		metaB     *charm.Meta
+		endpointB string
+		match     bool
+	}{
+		{wordpress, "db", mysql, "server", true},
+		{mysql, "server", wordpress, "db", true},
+		{wordpress, "url", mysql, "server", false},
+		{wordpress, "missing", mysql, "server", false},
+		{wordpress, "db", nil, "server", false},
+		{logging, "info", wordpress, "juju-info", true},
+		{logging, "logging-directory", wordpress, "logging-dir", true},
+		{wordpress, "juju-info", mysql, "juju-info", false},
+		{logging, "juju-info", wordpress, "juju-info", true},
+		{wordpress, "juju-info", logging, "juju-info", true},
+	} {
+		c.Logf("test %d: %s %s", i, test.endpointA, test.endpointB)
+		c.Check(charm.CanRelate(test.metaA, test.endpointA, test.metaB, test.endpointB), gc.Equals, test.match)
+	}
+
+	// A container-scoped relation needs a subordinate.
+	principal := *logging
+	principal.Subordinate = false
+	c.Check(charm.CanRelate(&principal, "info", wordpress, "juju-info"), jc.IsFalse)
+}
+
 var metaYAMLMarshalTests = []struct {
 	about string
 	yaml  string
@@ -1029,6 +1753,104 @@ resources:
         filename: 'y.tgz'
         type: file
 `,
+}, {
+	about: "charm user",
+	yaml: `
+name: minimal
+description: d
+summary: s
+charm-user: non-root
+charm-user-group: operators
+`,
+}, {
+	about: "project links",
+	yaml: `
+name: minimal
+description: d
+summary: s
+website: https://example.com
+docs:
+  - https://example.com/docs
+  - https://docs.example.com
+issues: https://example.com/issues
+`,
+}, {
+	about: "deprecation",
+	yaml: `
+name: minimal
+description: d
+summary: s
+deprecated:
+  date: 2026-04-01
+  message: No longer maintained.
+superseded-by: maximal
+`,
+}, {
+	about: "translations",
+	yaml: `
+name: minimal
+description: d
+summary: s
+summary-i18n:
+  fr: s en français
+description-i18n:
+  fr: d en français
+  pt_BR: d em português
+`,
+}, {
+	about: "deprecation flag",
+	yaml: `
+name: minimal
+description: d
+summary: s
+deprecated: true
+`,
+}, {
+	about: "relation ports",
+	yaml: `
+name: minimal
+description: d
+summary: s
+provides:
+  website:
+    interface: http
+    ports: [80/tcp, 8000-8010/udp, icmp]
+`,
+}, {
+	about: "relation description",
+	yaml: `
+name: minimal
+description: d
+summary: s
+requires:
+  db:
+    interface: mysql
+    description: The database holding the application state.
+`,
+}, {
+	about: "storage",
+	yaml: `
+name: minimal
+description: d
+summary: s
+storage:
+  data:
+    type: filesystem
+    description: Application data.
+    shared: true
+    read-only: true
+    multiple:
+      range: 2-
+    minimum-size: 10G
+    location: /srv/data
+    properties: [transient]
+  cache:
+    type: block
+    multiple:
+      range: 3
+  scratch:
+    type: block
+`,
 }}
 
 func (s *MetaSuite) TestYAMLMarshal(c *gc.C) {
@@ -1219,6 +2041,14 @@ deployment:
 		desc: "missing series",
 		yaml: "        service: cluster",
 		err:  `charm with deployment metadata must declare at least one series`,
+	}, {
+		desc: "invalid min-version",
+		yaml: "        min-version: one.fifteen\nseries:\n        - kubernetes",
+		err:  `deployment min-version "one.fifteen" not valid`,
+	}, {
+		desc: "too many min-version components",
+		yaml: "        min-version: 1.15.2.1\nseries:\n        - kubernetes",
+		err:  `deployment min-version "1.15.2.1" not valid`,
 	}}
 
 	testErrors(c, prefix, tests)
@@ -1301,6 +2131,129 @@ storage:
 	})
 }
 
+func (s *MetaSuite) TestExtraFieldsRoundTrip(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(`
+name: a
+summary: b
+description: c
+maintainer: Someone <someone@example.com>
+future-field:
+  enabled: true
+  items: [1, {x: z}]
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.Extra, jc.DeepEquals, map[string]interface{}{
+		"maintainer": "Someone <someone@example.com>",
+		"future-field": map[string]interface{}{
+			"enabled": true,
+			"items":   []interface{}{1, map[string]interface{}{"x": "z"}},
+		},
+	})
+
+	data, err := yaml.Marshal(meta)
+	c.Assert(err, jc.ErrorIsNil)
+	meta1, err := charm.ReadMeta(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta1, jc.DeepEquals, meta)
+
+	_, err = json.Marshal(meta)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MetaSuite) TestExtraFieldsClash(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(dummyMetadata))
+	c.Assert(err, jc.ErrorIsNil)
+	meta.Extra = map[string]interface{}{"summary": "other"}
+	_, err = yaml.Marshal(meta)
+	c.Assert(err, gc.ErrorMatches, `extra metadata field "summary" clashing with a known field not valid`)
+}
+
+func (s *MetaSuite) TestStorageMinimumSizeRoundTrip(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(`
+name: a
+summary: b
+description: c
+storage:
+  store0:
+    type: block
+    minimum-size: 10G
+  store1:
+    type: block
+    minimum-size: 512M
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.Storage["store0"].MinimumSize, gc.Equals, uint64(10*1024))
+	c.Check(meta.Storage["store0"].MinimumSizeBytes(), gc.Equals, uint64(10*1024*1024*1024))
+	c.Check(meta.Storage["store1"].MinimumSizeBytes(), gc.Equals, uint64(512*1024*1024))
+
+	data, err := yaml.Marshal(meta)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), jc.Contains, "minimum-size: 10240M")
+	roundTripped, err := charm.ReadMeta(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(roundTripped.Storage, jc.DeepEquals, meta.Storage)
+}
+
+func (s *MetaSuite) TestStorageZeroCountRoundTrip(c *gc.C) {
+	meta := charm.Meta{
+		Name:        "a",
+		Summary:     "b",
+		Description: "c",
+		Storage: map[string]charm.Storage{
+			"data": {Name: "data", Type: charm.StorageFilesystem},
+		},
+	}
+	data, err := yaml.Marshal(meta)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Not(jc.Contains), "multiple")
+	roundTripped, err := charm.ReadMeta(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(roundTripped.Storage["data"].CountMin, gc.Equals, 1)
+	c.Check(roundTripped.Storage["data"].CountMax, gc.Equals, 1)
+}
+
+func (s *MetaSuite) TestCheckStorageMinimumSizeTooLarge(c *gc.C) {
+	meta := charm.Meta{
+		Name: "a",
+		Storage: map[string]charm.Storage{
+			"data": {
+				Name:        "data",
+				Type:        charm.StorageBlock,
+				CountMin:    1,
+				CountMax:    1,
+				MinimumSize: 1 << 44,
+			},
+		},
+	}
+	c.Assert(meta.Check(), gc.ErrorMatches, `charm "a" storage "data": minimum size 17592186044416M too large`)
+
+	store := meta.Storage["data"]
+	store.MinimumSize = 1<<44 - 1
+	meta.Storage["data"] = store
+	c.Assert(meta.Check(), jc.ErrorIsNil)
+}
+
+func (s *MetaSuite) TestCheckStorageProperties(c *gc.C) {
+	meta := charm.Meta{
+		Name: "a",
+		Storage: map[string]charm.Storage{
+			"data": {
+				Name:       "data",
+				Type:       charm.StorageFilesystem,
+				CountMin:   1,
+				CountMax:   1,
+				Properties: []string{charm.StoragePropertyTransient},
+			},
+		},
+	}
+	c.Assert(meta.Check(), jc.ErrorIsNil)
+
+	store := meta.Storage["data"]
+	store.Properties = []string{"fast"}
+	meta.Storage["data"] = store
+	c.Assert(meta.Check(), gc.ErrorMatches, `charm "a" storage "data": unknown property "fast"`)
+}
+
 func (s *MetaSuite) TestStorageErrors(c *gc.C) {
 	prefix := `
 name: a
@@ -1346,11 +2299,80 @@ storage:
 		desc: "properties must contain valid values",
 		yaml: "  type: block\n  properties: [transient, foo]",
 		err:  `metadata: .* unexpected value "foo"`,
+	}, {
+		desc: "location must be absolute",
+		yaml: "  type: filesystem\n  location: srv/data",
+		err:  `charm "a" storage "store-bad": invalid location "srv/data": path must be absolute`,
+	}, {
+		desc: "location must be clean",
+		yaml: "  type: filesystem\n  location: /srv/../data",
+		err:  `charm "a" storage "store-bad": invalid location "/srv/../data": path must be clean \(expected "/data"\)`,
+	}, {
+		desc: "location may not be a reserved mount point",
+		yaml: "  type: filesystem\n  location: /usr/",
+		err:  `charm "a" storage "store-bad": invalid location "/usr/": "/usr" is a reserved mount point`,
+	}, {
+		desc: "location may not be within the juju data directory",
+		yaml: "  type: filesystem\n  location: /var/lib/juju/agents/data",
+		err:  `charm "a" storage "store-bad": invalid location "/var/lib/juju/agents/data": "/var/lib/juju" is reserved`,
 	}}
 
 	testErrors(c, prefix, tests)
 }
 
+func (s *MetaSuite) TestStorageReservedNames(c *gc.C) {
+	for i, test := range []struct {
+		yaml string
+		err  string
+	}{{
+		yaml: "storage:\n  juju-data:\n    type: filesystem\n",
+		err:  `charm "a" using a reserved storage name: "juju-data" \(the "juju-" prefix is reserved\)`,
+	}, {
+		yaml: "storage:\n  juju:\n    type: filesystem\n",
+		err:  `charm "a" using a reserved storage name: "juju" \("juju" is a reserved name\)`,
+	}, {
+		yaml: "provides:\n  data: http\nstorage:\n  data:\n    type: filesystem\n",
+		err:  `charm "a" storage "data": name clashes with a relation`,
+	}, {
+		yaml: "peers:\n  cluster: db\nstorage:\n  cluster:\n    type: block\n",
+		err:  `charm "a" storage "cluster": name clashes with a relation`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := charm.ReadMeta(strings.NewReader("name: a\nsummary: b\ndescription: c\n" + test.yaml))
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *MetaSuite) TestStorageLocationOverlap(c *gc.C) {
+	_, err := charm.ReadMeta(strings.NewReader(`
+name: a
+summary: b
+description: c
+storage:
+    data:
+        type: filesystem
+        location: /srv/data
+    logs:
+        type: filesystem
+        location: /srv/logs/
+`))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = charm.ReadMeta(strings.NewReader(`
+name: a
+summary: b
+description: c
+storage:
+    data:
+        type: filesystem
+        location: /srv/data
+    index:
+        type: filesystem
+        location: /srv/data/index
+`))
+	c.Assert(err, gc.ErrorMatches, `charm "a" storage "data": location "/srv/data" overlaps storage "index" location "/srv/data/index"`)
+}
+
 func (s *MetaSuite) TestStorageCount(c *gc.C) {
 	testStorageCount := func(count string, min, max int) {
 		meta, err := charm.ReadMeta(strings.NewReader(fmt.Sprintf(`
@@ -1377,6 +2399,37 @@ storage:
 	testStorageCount("1-", 1, -1)
 }
 
+func (s *MetaSuite) TestStorageCountRangeString(c *gc.C) {
+	for i, test := range []struct {
+		min, max int
+		expect   string
+	}{
+		{1, 1, "1"},
+		{0, 3, "0-3"},
+		{2, -1, "2-"},
+		{0, -1, "0-"},
+	} {
+		c.Logf("test %d: %d-%d", i, test.min, test.max)
+		store := charm.Storage{CountMin: test.min, CountMax: test.max}
+		c.Check(store.CountRangeString(), gc.Equals, test.expect)
+
+		// The string form must parse back to the same range.
+		meta, err := charm.ReadMeta(strings.NewReader(fmt.Sprintf(`
+name: a
+summary: b
+description: c
+storage:
+    store0:
+        type: filesystem
+        multiple:
+            range: %s
+`, test.expect)))
+		c.Assert(err, gc.IsNil)
+		c.Check(meta.Storage["store0"].CountMin, gc.Equals, test.min)
+		c.Check(meta.Storage["store0"].CountMax, gc.Equals, test.max)
+	}
+}
+
 func (s *MetaSuite) TestStorageLocation(c *gc.C) {
 	meta, err := charm.ReadMeta(strings.NewReader(`
 name: a
@@ -1706,6 +2759,65 @@ systems:
 	c.Assert(meta.ComputedSeries(), jc.DeepEquals, []string{"bionic"})
 }
 
+func (s *MetaSuite) TestBases(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(dummyMetadata + `
+bases:
+  - name: ubuntu
+    channel: 22.04/stable
+    architectures: [amd64, arm64]
+  - name: ubuntu
+    channel: "20.04"
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.Format(), gc.Equals, charm.Format(charm.FormatV2))
+	c.Assert(meta.Bases, jc.DeepEquals, []charm.Base{
+		charm.MustParseBase("ubuntu@22.04", charm.AMD64, charm.ARM64),
+		charm.MustParseBase("ubuntu@20.04"),
+	})
+	c.Assert(meta.ComputedSeries(), jc.DeepEquals, []string{"jammy", "focal"})
+	c.Assert(meta.ComputedBases(), jc.DeepEquals, meta.Bases)
+
+	data, err := yaml.Marshal(meta)
+	c.Assert(err, jc.ErrorIsNil)
+	meta1, err := charm.ReadMeta(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta1.Bases, jc.DeepEquals, meta.Bases)
+}
+
+func (s *MetaSuite) TestBasesErrors(c *gc.C) {
+	for i, test := range []struct {
+		bases string
+		err   string
+	}{{
+		bases: "[{name: beos, channel: '5'}]",
+		err:   `parsing bases: base os "beos" not valid`,
+	}, {
+		bases: "[{name: ubuntu, channel: 22.04/unstable}]",
+		err:   `parsing bases: parsing channel "22.04/unstable": .*`,
+	}, {
+		bases: "[{name: ubuntu, channel: '22.04', architectures: [m68k]}]",
+		err:   `parsing bases: base architecture "m68k" not valid`,
+	}, {
+		bases: "[{name: ubuntu}]",
+		err:   `metadata: bases\[0\]\.channel: expected string, got nothing`,
+	}} {
+		c.Logf("test %d: %s", i, test.bases)
+		_, err := charm.ReadMeta(strings.NewReader(dummyMetadata + "\nbases: " + test.bases + "\n"))
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *MetaSuite) TestFormatV2WithSeries(c *gc.C) {
+	for _, decl := range []string{
+		"bases: [{name: ubuntu, channel: '22.04'}]",
+		"systems: [{os: ubuntu, channel: '22.04'}]",
+	} {
+		_, err := charm.ReadMeta(strings.NewReader(dummyMetadata + "\nseries: [jammy]\n" + decl + "\n"))
+		c.Check(err, gc.ErrorMatches, `charm "a" declares series as well as bases or systems`)
+		c.Check(stderrors.Is(err, charm.ErrSeriesInFormatV2), jc.IsTrue)
+	}
+}
+
 func (s *MetaSuite) TestPlatform(c *gc.C) {
 	meta, err := charm.ReadMeta(strings.NewReader(`
 name: a
@@ -2000,3 +3112,205 @@ func (c *dummyCharm) Meta() *charm.Meta {
 		},
 	}
 }
+
+func (s *MetaSuite) assertYAMLRoundTrip(c *gc.C, doc string) {
+	meta, err := charm.ReadMeta(strings.NewReader(doc))
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := yaml.Marshal(meta)
+	c.Assert(err, jc.ErrorIsNil)
+	meta1, err := charm.ReadMeta(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil, gc.Commentf("data: %s", data))
+	c.Assert(meta1, jc.DeepEquals, meta, gc.Commentf("data: %s", data))
+}
+
+func (s *MetaSuite) TestYAMLRoundTripAllFields(c *gc.C) {
+	s.assertYAMLRoundTrip(c, `
+name: everything
+summary: a charm using every field
+description: |
+  It has a long description.
+subordinate: true
+provides:
+  website: http
+  metrics:
+    interface: prometheus
+    limit: 3
+    optional: true
+    description: Metrics endpoint.
+    ports: [9100/tcp]
+requires:
+  db:
+    interface: mysql
+    limit: 1
+  logs:
+    interface: syslog
+    scope: container
+    renamed-from: logging
+peers:
+  cluster: cluster
+extra-bindings:
+  admin-api:
+  public:
+categories: [databases]
+tags: [databases, monitoring]
+series: [focal, jammy]
+storage:
+  data:
+    type: filesystem
+    description: Data files.
+    shared: true
+    read-only: true
+    location: /srv/data
+    minimum-size: 2G
+    properties: [transient]
+  disks:
+    type: block
+    multiple:
+      range: 2-
+  logdir:
+    type: filesystem
+    multiple:
+      range: 1-4
+  fixed:
+    type: block
+    multiple:
+      range: 3
+payloads:
+  monitor:
+    type: docker
+resources:
+  blob:
+    type: file
+    filename: blob.tgz
+    description: A blob.
+terms: [term/1]
+min-juju-version: 2.9.1
+charm-user: non-root
+charm-user-group: daemon
+website: https://example.com
+docs: [https://example.com/docs]
+issues: [https://example.com/issues]
+x-custom: value
+`)
+}
+
+func (s *MetaSuite) TestYAMLRoundTripDeployment(c *gc.C) {
+	s.assertYAMLRoundTrip(c, `
+name: k8s
+summary: a podspec charm
+description: It runs in Kubernetes.
+series: [kubernetes]
+devices:
+  gpu:
+    type: gpu
+    description: A GPU.
+    countmin: 1
+    countmax: 2
+  tpu:
+    type: nvidia.com/gpu
+deployment:
+  type: stateful
+  mode: operator
+  service: loadbalancer
+  min-version: "1.15"
+`)
+}
+
+func (s *MetaSuite) TestYAMLRoundTripKubernetesFields(c *gc.C) {
+	s.assertYAMLRoundTrip(c, `
+name: k8s
+summary: a sidecar charm
+description: It runs in Kubernetes.
+platforms: [kubernetes]
+architectures: [amd64, arm64]
+systems:
+  - os: ubuntu
+    channel: "20.04/stable"
+containers:
+  app:
+    systems:
+      - resource: app-image
+    mounts:
+      - storage: data
+        location: /data
+resources:
+  app-image:
+    type: oci-image
+    description: The application image.
+storage:
+  data:
+    type: filesystem
+`)
+}
+
+func (s *MetaSuite) TestYAMLRoundTripTestCharms(c *gc.C) {
+	dirs, err := ioutil.ReadDir(filepath.Join("internal", "test-charm-repo", "quantal"))
+	c.Assert(err, jc.ErrorIsNil)
+	for _, dir := range dirs {
+		data, err := ioutil.ReadFile(filepath.Join("internal", "test-charm-repo", "quantal", dir.Name(), "metadata.yaml"))
+		if err != nil {
+			continue
+		}
+		if _, err := charm.ReadMeta(bytes.NewReader(data)); err != nil {
+			continue
+		}
+		c.Logf("charm %s", dir.Name())
+		s.assertYAMLRoundTrip(c, string(data))
+	}
+}
+
+func (s *MetaSuite) TestCopy(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(`
+name: a
+summary: b
+description: c
+provides:
+  metrics:
+    interface: prometheus
+    ports: [9100/tcp]
+extra-bindings:
+  admin-api:
+tags: [databases]
+series: [kubernetes]
+storage:
+  data:
+    type: filesystem
+    properties: [transient]
+devices:
+  gpu:
+    type: nvidia.com/gpu
+deployment:
+  type: stateful
+  service: loadbalancer
+future-field:
+  items: [1, {x: z}]
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	copied := meta.Copy()
+	c.Assert(copied, jc.DeepEquals, meta)
+
+	copied.Provides["metrics"].Ports[0].FromPort = 9200
+	copied.ExtraBindings["other"] = charm.ExtraBinding{Name: "other"}
+	copied.Tags[0] = "other"
+	copied.Series = append(copied.Series[:0], "other")
+	copied.Storage["data"].Properties[0] = "other"
+	copied.Devices["gpu"] = charm.Device{Name: "gpu"}
+	copied.Deployment.ServiceType = charm.ServiceCluster
+	copied.Extra["future-field"].(map[string]interface{})["items"].([]interface{})[1].(map[string]interface{})["x"] = "y"
+
+	c.Check(meta.Provides["metrics"].Ports[0].FromPort, gc.Equals, 9100)
+	c.Check(meta.ExtraBindings, gc.HasLen, 1)
+	c.Check(meta.Tags, jc.DeepEquals, []string{"databases"})
+	c.Check(meta.Series, jc.DeepEquals, []string{"kubernetes"})
+	c.Check(meta.Storage["data"].Properties, jc.DeepEquals, []string{"transient"})
+	c.Check(meta.Devices["gpu"].Type, gc.Equals, charm.DeviceType("nvidia.com/gpu"))
+	c.Check(meta.Deployment.ServiceType, gc.Equals, charm.ServiceLoadBalancer)
+	c.Check(meta.Extra["future-field"], jc.DeepEquals, map[string]interface{}{
+		"items": []interface{}{1, map[string]interface{}{"x": "z"}},
+	})
+}
+
+func (s *MetaSuite) TestCopyNil(c *gc.C) {
+	var meta *charm.Meta
+	c.Assert(meta.Copy(), gc.IsNil)
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"github.com/juju/charm/v8/resource"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// noMethodsMeta is Meta without its marshaling methods, so that it may
+// be marshaled to and unmarshaled from BSON as usual.
+type noMethodsMeta Meta
+
+// GetBSON implements bson.Getter. The metadata is stored with the names
+// of its relations, stores and other named entries matching the keys
+// they are held under, as described for SetBSON.
+func (m Meta) GetBSON() (interface{}, error) {
+	return noMethodsMeta(normalizedMeta(m)), nil
+}
+
+// SetBSON implements bson.Setter. Entries of the metadata that are held
+// in maps, such as relations and stores, also record their names, and
+// relations record their role; as such fields may be missing or stale
+// in stored documents, they are restored from the keys and sections
+// that the entries are held under, so that the metadata is the same as
+// that returned by ReadMeta.
+func (m *Meta) SetBSON(raw bson.Raw) error {
+	var meta *noMethodsMeta
+	if err := raw.Unmarshal(&meta); err != nil {
+		return err
+	}
+	if meta == nil {
+		return bson.SetZero
+	}
+	*m = normalizedMeta(Meta(*meta))
+	return nil
+}
+
+// normalizedMeta returns a copy of m in which the names of the entries
+// held in maps, and the roles of its relations, match the keys and
+// sections they are held under, and in which the extra fields hold
+// only plain maps.
+func normalizedMeta(m Meta) Meta {
+	m.Provides = normalizedRelations(m.Provides, RoleProvider)
+	m.Requires = normalizedRelations(m.Requires, RoleRequirer)
+	m.Peers = normalizedRelations(m.Peers, RolePeer)
+	if m.ExtraBindings != nil {
+		bindings := make(map[string]ExtraBinding, len(m.ExtraBindings))
+		for name := range m.ExtraBindings {
+			bindings[name] = ExtraBinding{Name: name}
+		}
+		m.ExtraBindings = bindings
+	}
+	if m.Storage != nil {
+		stores := make(map[string]Storage, len(m.Storage))
+		for name, store := range m.Storage {
+			store.Name = name
+			stores[name] = store
+		}
+		m.Storage = stores
+	}
+	if m.Devices != nil {
+		devices := make(map[string]Device, len(m.Devices))
+		for name, device := range m.Devices {
+			device.Name = name
+			devices[name] = device
+		}
+		m.Devices = devices
+	}
+	if m.PayloadClasses != nil {
+		classes := make(map[string]PayloadClass, len(m.PayloadClasses))
+		for name, class := range m.PayloadClasses {
+			class.Name = name
+			classes[name] = class
+		}
+		m.PayloadClasses = classes
+	}
+	if m.Resources != nil {
+		resources := make(map[string]resource.Meta, len(m.Resources))
+		for name, res := range m.Resources {
+			res.Name = name
+			resources[name] = res
+		}
+		m.Resources = resources
+	}
+	if m.Extra != nil {
+		extra := make(map[string]interface{}, len(m.Extra))
+		for name, value := range m.Extra {
+			extra[name] = plainBSONValue(value)
+		}
+		m.Extra = extra
+	}
+	return m
+}
+
+func normalizedRelations(relations map[string]Relation, role RelationRole) map[string]Relation {
+	if relations == nil {
+		return nil
+	}
+	result := make(map[string]Relation, len(relations))
+	for name, relation := range relations {
+		relation.Name = name
+		relation.Role = role
+		result[name] = relation
+	}
+	return result
+}
+
+// plainBSONValue returns v with any bson.M documents that it holds,
+// as unmarshaled by the bson package, converted to the
+// map[string]interface{} values held by metadata read by ReadMeta.
+func plainBSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case bson.M:
+		return plainBSONValue(map[string]interface{}(v))
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			result[key] = plainBSONValue(value)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, value := range v {
+			result[i] = plainBSONValue(value)
+		}
+		return result
+	}
+	return v
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/charm/v8"
+)
+
+type MetaBSONSuite struct{}
+
+var _ = gc.Suite(&MetaBSONSuite{})
+
+func (s *MetaBSONSuite) TestRoundTripTestCharms(c *gc.C) {
+	dirs, err := ioutil.ReadDir(filepath.Join("internal", "test-charm-repo", "quantal"))
+	c.Assert(err, jc.ErrorIsNil)
+	for _, dir := range dirs {
+		data, err := ioutil.ReadFile(filepath.Join("internal", "test-charm-repo", "quantal", dir.Name(), "metadata.yaml"))
+		if err != nil {
+			continue
+		}
+		meta, err := charm.ReadMeta(bytes.NewReader(data))
+		if err != nil {
+			continue
+		}
+		c.Logf("charm %s", dir.Name())
+		data, err = bson.Marshal(meta)
+		c.Assert(err, jc.ErrorIsNil)
+		var meta1 charm.Meta
+		err = bson.Unmarshal(data, &meta1)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(&meta1, jc.DeepEquals, meta)
+	}
+}
+
+func (s *MetaBSONSuite) TestRoundTripExtra(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(`
+name: a
+summary: b
+description: c
+future-field:
+  enabled: true
+  items: [1, {x: z}]
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := bson.Marshal(meta)
+	c.Assert(err, jc.ErrorIsNil)
+	var meta1 charm.Meta
+	err = bson.Unmarshal(data, &meta1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(&meta1, jc.DeepEquals, meta)
+}
+
+func (s *MetaBSONSuite) TestSetBSONRestoresNames(c *gc.C) {
+	data, err := bson.Marshal(bson.M{
+		"name":        "a",
+		"summary":     "b",
+		"description": "c",
+		"provides": bson.M{
+			"website": bson.M{"interface": "http", "scope": "global"},
+		},
+		"requires": bson.M{
+			"db": bson.M{"name": "old-db", "role": "provider", "interface": "mysql", "scope": "global"},
+		},
+		"storage": bson.M{
+			"data": bson.M{"name": "old-data", "type": "filesystem", "countmin": 1, "countmax": 1},
+		},
+		"devices": bson.M{
+			"gpu": bson.M{"type": "nvidia.com/gpu", "countmin": 1, "countmax": 1},
+		},
+		"extra-bindings": bson.M{
+			"admin": bson.M{},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	var meta charm.Meta
+	err = bson.Unmarshal(data, &meta)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.Provides["website"].Name, gc.Equals, "website")
+	c.Check(meta.Provides["website"].Role, gc.Equals, charm.RoleProvider)
+	c.Check(meta.Requires["db"].Name, gc.Equals, "db")
+	c.Check(meta.Requires["db"].Role, gc.Equals, charm.RoleRequirer)
+	c.Check(meta.Storage["data"].Name, gc.Equals, "data")
+	c.Check(meta.Devices["gpu"].Name, gc.Equals, "gpu")
+	c.Check(meta.ExtraBindings["admin"].Name, gc.Equals, "admin")
+}
+
+func (s *MetaBSONSuite) TestGetBSONDoesNotChangeMeta(c *gc.C) {
+	meta := charm.Meta{
+		Name: "a",
+		Provides: map[string]charm.Relation{
+			"website": {Name: "stale", Interface: "http"},
+		},
+	}
+	_, err := bson.Marshal(meta)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.Provides["website"].Name, gc.Equals, "stale")
+}
+
+func (s *MetaBSONSuite) TestNilMeta(c *gc.C) {
+	data, err := bson.Marshal(bson.M{"meta": nil})
+	c.Assert(err, jc.ErrorIsNil)
+	var doc struct {
+		Meta *charm.Meta `bson:"meta"`
+	}
+	err = bson.Unmarshal(data, &doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc.Meta, gc.IsNil)
+}
@@ -0,0 +1,230 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// MetaChangeKind describes how a field differs between two versions of
+// charm metadata.
+type MetaChangeKind string
+
+// The kinds of MetaChange.
+const (
+	MetaFieldAdded   MetaChangeKind = "added"
+	MetaFieldRemoved MetaChangeKind = "removed"
+	MetaFieldChanged MetaChangeKind = "changed"
+)
+
+// MetaChange describes a field that differs between two versions of
+// charm metadata.
+type MetaChange struct {
+	// Kind holds how the field differs.
+	Kind MetaChangeKind
+
+	// Path holds the path of the field, in the form used by
+	// FieldError. Fields of metadata.yaml that hold named entries,
+	// such as relations and stores, are compared entry by entry,
+	// so their changes have paths such as "provides.db" or
+	// "storage.data"; other fields are compared as a whole.
+	Path string
+
+	// Old holds the value of the field before the change, as it is
+	// written in metadata.yaml. It is nil if the field was added.
+	Old interface{}
+
+	// New holds the value of the field after the change, as it is
+	// written in metadata.yaml. It is nil if the field was removed.
+	New interface{}
+}
+
+// String returns a line describing the change, in the style of a
+// unified diff.
+func (c MetaChange) String() string {
+	switch c.Kind {
+	case MetaFieldAdded:
+		return fmt.Sprintf("+ %s: %s", c.Path, diffValueString(c.New))
+	case MetaFieldRemoved:
+		return fmt.Sprintf("- %s: %s", c.Path, diffValueString(c.Old))
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", c.Path, diffValueString(c.Old), diffValueString(c.New))
+}
+
+// MetaDiff holds the differences between two versions of charm
+// metadata, as returned by DiffMeta.
+type MetaDiff struct {
+	// Changes holds the fields that differ, with top level fields
+	// in the order they are written by Meta.MarshalYAML and named
+	// entries in alphabetical order.
+	Changes []MetaChange
+}
+
+// Empty reports whether there are no differences.
+func (d *MetaDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// String returns a human-readable rendering of the differences, with
+// one line for each change.
+func (d *MetaDiff) String() string {
+	var buf strings.Builder
+	for _, change := range d.Changes {
+		buf.WriteString(change.String())
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// namedEntryFields holds the fields of metadata.yaml that hold named
+// entries, which DiffMeta compares entry by entry.
+var namedEntryFields = map[string]bool{
+	"provides":       true,
+	"requires":       true,
+	"peers":          true,
+	"extra-bindings": true,
+	"storage":        true,
+	"devices":        true,
+	"payloads":       true,
+	"resources":      true,
+	"containers":     true,
+}
+
+// DiffMeta returns the differences between the metadata a and b, such
+// as two published revisions of a charm. The metadata is compared as
+// it would be written to metadata.yaml, so that differences that
+// metadata.yaml cannot express, such as between relations written in
+// full and in shorthand, are not reported.
+func DiffMeta(a, b *Meta) (*MetaDiff, error) {
+	fieldsA, err := metaDiffFields(a)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	fieldsB, err := metaDiffFields(b)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	valuesB := make(map[string]interface{}, len(fieldsB))
+	for _, item := range fieldsB {
+		valuesB[fmt.Sprint(item.Key)] = item.Value
+	}
+
+	diff := &MetaDiff{}
+	seen := make(map[string]bool)
+	for _, item := range fieldsA {
+		name := fmt.Sprint(item.Key)
+		seen[name] = true
+		diff.add(name, item.Value, valuesB[name])
+	}
+	for _, item := range fieldsB {
+		if name := fmt.Sprint(item.Key); !seen[name] {
+			diff.add(name, nil, item.Value)
+		}
+	}
+	return diff, nil
+}
+
+// metaDiffFields returns the top level fields of meta as they would be
+// written to metadata.yaml.
+func metaDiffFields(meta *Meta) (yaml.MapSlice, error) {
+	if meta == nil {
+		return nil, errors.NotValidf("nil metadata")
+	}
+	data, err := yaml.Marshal(meta)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var fields yaml.MapSlice
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return nil, errors.Trace(err)
+	}
+	for i, item := range fields {
+		fields[i].Value = unorderedYAMLValue(item.Value)
+	}
+	return fields, nil
+}
+
+// unorderedYAMLValue returns v with the yaml.MapSlice values that it
+// holds converted to maps, so that values are equal regardless of the
+// order of their keys.
+func unorderedYAMLValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case yaml.MapSlice:
+		result := make(map[interface{}]interface{}, len(v))
+		for _, item := range v {
+			result[item.Key] = unorderedYAMLValue(item.Value)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, value := range v {
+			result[i] = unorderedYAMLValue(value)
+		}
+		return result
+	}
+	return v
+}
+
+// add adds the changes between the values a and b of the field at
+// path, either of which may be nil if the field is not present.
+func (d *MetaDiff) add(path string, a, b interface{}) {
+	entriesA, okA := a.(map[interface{}]interface{})
+	entriesB, okB := b.(map[interface{}]interface{})
+	if namedEntryFields[path] && (okA || a == nil) && (okB || b == nil) {
+		keys := make(map[interface{}]interface{})
+		for key := range entriesA {
+			keys[key] = nil
+		}
+		for key := range entriesB {
+			keys[key] = nil
+		}
+		for _, key := range sortedKeys(keys) {
+			d.addValue(fmt.Sprintf("%s.%v", path, key), entriesA[key], entriesB[key], hasKey(entriesA, key), hasKey(entriesB, key))
+		}
+		return
+	}
+	d.addValue(path, a, b, a != nil, b != nil)
+}
+
+// addValue adds the change, if any, between the values a and b of the
+// field at path. The present flags report whether the field is present
+// in each version, as an entry may be present with a nil value.
+func (d *MetaDiff) addValue(path string, a, b interface{}, presentA, presentB bool) {
+	change := MetaChange{
+		Path: path,
+		Old:  stringKeyedValue(a),
+		New:  stringKeyedValue(b),
+	}
+	switch {
+	case presentA && !presentB:
+		change.Kind = MetaFieldRemoved
+	case !presentA && presentB:
+		change.Kind = MetaFieldAdded
+	case presentA && presentB && !reflect.DeepEqual(a, b):
+		change.Kind = MetaFieldChanged
+	default:
+		return
+	}
+	d.Changes = append(d.Changes, change)
+}
+
+func hasKey(m map[interface{}]interface{}, key interface{}) bool {
+	_, ok := m[key]
+	return ok
+}
+
+// diffValueString returns v formatted compactly for MetaChange.String.
+func diffValueString(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
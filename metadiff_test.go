@@ -0,0 +1,123 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type MetaDiffSuite struct{}
+
+var _ = gc.Suite(&MetaDiffSuite{})
+
+func (s *MetaDiffSuite) readMeta(c *gc.C, data string) *charm.Meta {
+	meta, err := charm.ReadMeta(strings.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	return meta
+}
+
+func (s *MetaDiffSuite) TestDiffMeta(c *gc.C) {
+	a := s.readMeta(c, `
+name: a
+summary: old summary
+description: c
+tags: [databases]
+provides:
+  website: http
+  metrics: prometheus
+requires:
+  db:
+    interface: mysql
+    limit: 1
+storage:
+  data:
+    type: filesystem
+`)
+	b := s.readMeta(c, `
+name: a
+summary: new summary
+description: c
+provides:
+  website: http
+  admin: http
+requires:
+  db:
+    interface: mysql
+storage:
+  data:
+    type: filesystem
+  logs:
+    type: filesystem
+    location: /var/log/a
+`)
+	diff, err := charm.DiffMeta(a, b)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(diff.Empty(), jc.IsFalse)
+	c.Assert(diff.Changes, jc.DeepEquals, []charm.MetaChange{{
+		Kind: charm.MetaFieldChanged,
+		Path: "summary",
+		Old:  "old summary",
+		New:  "new summary",
+	}, {
+		Kind: charm.MetaFieldAdded,
+		Path: "provides.admin",
+		New:  "http",
+	}, {
+		Kind: charm.MetaFieldRemoved,
+		Path: "provides.metrics",
+		Old:  "prometheus",
+	}, {
+		Kind: charm.MetaFieldChanged,
+		Path: "requires.db",
+		Old:  map[string]interface{}{"interface": "mysql", "limit": 1},
+		New:  "mysql",
+	}, {
+		Kind: charm.MetaFieldRemoved,
+		Path: "tags",
+		Old:  []interface{}{"databases"},
+	}, {
+		Kind: charm.MetaFieldAdded,
+		Path: "storage.logs",
+		New:  map[string]interface{}{"type": "filesystem", "location": "/var/log/a"},
+	}})
+	c.Assert(diff.String(), gc.Equals, `
+~ summary: "old summary" -> "new summary"
++ provides.admin: "http"
+- provides.metrics: "prometheus"
+~ requires.db: {"interface":"mysql","limit":1} -> "mysql"
+- tags: ["databases"]
++ storage.logs: {"location":"/var/log/a","type":"filesystem"}
+`[1:])
+}
+
+func (s *MetaDiffSuite) TestDiffMetaSame(c *gc.C) {
+	a := s.readMeta(c, dummyMetadata)
+	b := s.readMeta(c, dummyMetadata)
+	diff, err := charm.DiffMeta(a, b)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(diff.Empty(), jc.IsTrue)
+	c.Assert(diff.String(), gc.Equals, "")
+}
+
+func (s *MetaDiffSuite) TestDiffMetaExtraBindings(c *gc.C) {
+	a := s.readMeta(c, "name: a\nsummary: b\ndescription: c\nextra-bindings:\n  admin:\n")
+	b := s.readMeta(c, "name: a\nsummary: b\ndescription: c\n")
+	diff, err := charm.DiffMeta(a, b)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(diff.Changes, jc.DeepEquals, []charm.MetaChange{{
+		Kind: charm.MetaFieldRemoved,
+		Path: "extra-bindings.admin",
+	}})
+}
+
+func (s *MetaDiffSuite) TestDiffMetaNil(c *gc.C) {
+	_, err := charm.DiffMeta(nil, s.readMeta(c, dummyMetadata))
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
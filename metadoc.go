@@ -0,0 +1,187 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"encoding/json"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// MetaDocVersion is the version of the persisted metadata documents
+// written by MarshalMetaBSON and MarshalMetaJSON. It must be increased,
+// and a migration added to metaDocMigrations, whenever a field of Meta
+// is renamed or changes meaning in a way that documents written before
+// the change would no longer load correctly.
+const MetaDocVersion = 2
+
+// metaDoc is the envelope that persisted metadata is stored in. Older
+// versions of Juju stored the metadata directly, without an envelope;
+// such documents have the same fields as version 1 ones.
+type metaDoc struct {
+	SchemaVersion int         `bson:"schema-version" json:"schema-version"`
+	Payload       interface{} `bson:"payload" json:"payload"`
+}
+
+// metaCodec describes one of the formats that metadata documents may be
+// persisted in.
+type metaCodec struct {
+	name      string
+	marshal   func(interface{}) ([]byte, error)
+	unmarshal func([]byte, interface{}) error
+}
+
+var (
+	bsonMetaCodec = metaCodec{"bson", bson.Marshal, bson.Unmarshal}
+	jsonMetaCodec = metaCodec{"json", json.Marshal, json.Unmarshal}
+)
+
+// metaDocMigrations holds the functions that migrate the fields of a
+// metadata document forward; the migration at index i turns a version
+// i+1 document into a version i+2 one. They are passed the name of the
+// codec, as the keys used differ between formats.
+var metaDocMigrations = []func(fields map[string]interface{}, codec string) error{
+	// Version 2 marshals Meta to JSON with the fields used in
+	// metadata.yaml.
+	migrateMetaDocJSONFields,
+}
+
+// legacyJSONMeta holds metadata in the JSON format used by version 1
+// documents, in which each field of Meta, Relation and Storage was
+// marshaled under its Go name unless tagged otherwise.
+type legacyJSONMeta struct {
+	*legacyMeta
+	Provides map[string]legacyRelation `json:"Provides,omitempty"`
+	Requires map[string]legacyRelation `json:"Requires,omitempty"`
+	Peers    map[string]legacyRelation `json:"Peers,omitempty"`
+	Storage  map[string]legacyStorage  `json:"Storage,omitempty"`
+}
+
+// The legacy types lack the JSON marshalers of the types they are
+// defined from.
+type (
+	legacyMeta     Meta
+	legacyRelation Relation
+	legacyStorage  Storage
+)
+
+// migrateMetaDocJSONFields migrates the fields of a version 1 JSON
+// document to those used by Meta.MarshalJSON. BSON documents are
+// unchanged.
+func migrateMetaDocJSONFields(fields map[string]interface{}, codec string) error {
+	if codec != jsonMetaCodec.name {
+		return nil
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	legacy := legacyJSONMeta{legacyMeta: &legacyMeta{}}
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return errors.Trace(err)
+	}
+	meta := Meta(*legacy.legacyMeta)
+	meta.Provides = legacyRelations(legacy.Provides)
+	meta.Requires = legacyRelations(legacy.Requires)
+	meta.Peers = legacyRelations(legacy.Peers)
+	if legacy.Storage != nil {
+		meta.Storage = make(map[string]Storage, len(legacy.Storage))
+		for name, store := range legacy.Storage {
+			meta.Storage[name] = Storage(store)
+		}
+	}
+	if data, err = json.Marshal(meta); err != nil {
+		return errors.Trace(err)
+	}
+	for name := range fields {
+		delete(fields, name)
+	}
+	return errors.Trace(json.Unmarshal(data, &fields))
+}
+
+func legacyRelations(relations map[string]legacyRelation) map[string]Relation {
+	if relations == nil {
+		return nil
+	}
+	result := make(map[string]Relation, len(relations))
+	for name, relation := range relations {
+		result[name] = Relation(relation)
+	}
+	return result
+}
+
+// MarshalMetaBSON returns the BSON document used to persist meta,
+// wrapped in a versioned envelope so that it can still be loaded by
+// LoadMetaBSON once Meta has changed.
+func MarshalMetaBSON(meta *Meta) ([]byte, error) {
+	return marshalMetaDoc(bsonMetaCodec, meta)
+}
+
+// MarshalMetaJSON is like MarshalMetaBSON but returns a JSON document.
+func MarshalMetaJSON(meta *Meta) ([]byte, error) {
+	return marshalMetaDoc(jsonMetaCodec, meta)
+}
+
+// LoadMetaBSON loads metadata persisted with MarshalMetaBSON, migrating
+// documents written by older versions forward. Documents without an
+// envelope, as stored by older versions of Juju, are also accepted.
+func LoadMetaBSON(data []byte) (*Meta, error) {
+	return loadMetaDoc(bsonMetaCodec, data)
+}
+
+// LoadMetaJSON is like LoadMetaBSON but for documents written by
+// MarshalMetaJSON.
+func LoadMetaJSON(data []byte) (*Meta, error) {
+	return loadMetaDoc(jsonMetaCodec, data)
+}
+
+func marshalMetaDoc(codec metaCodec, meta *Meta) ([]byte, error) {
+	data, err := codec.marshal(metaDoc{
+		SchemaVersion: MetaDocVersion,
+		Payload:       meta,
+	})
+	return data, errors.Annotatef(err, "marshaling %s metadata document", codec.name)
+}
+
+func loadMetaDoc(codec metaCodec, data []byte) (*Meta, error) {
+	var fields map[string]interface{}
+	if err := codec.unmarshal(data, &fields); err != nil {
+		return nil, errors.Annotatef(err, "unmarshaling %s metadata document", codec.name)
+	}
+	version := 1
+	if v, ok := fields["schema-version"]; ok {
+		var doc struct {
+			SchemaVersion int                    `bson:"schema-version" json:"schema-version"`
+			Payload       map[string]interface{} `bson:"payload" json:"payload"`
+		}
+		if err := codec.unmarshal(data, &doc); err != nil {
+			return nil, errors.Annotatef(err, "invalid metadata document schema version %v", v)
+		}
+		if doc.Payload == nil {
+			return nil, errors.NotValidf("metadata document without payload")
+		}
+		version, fields = doc.SchemaVersion, doc.Payload
+	}
+	if version < 1 || version > MetaDocVersion {
+		return nil, errors.NotSupportedf("metadata document version %d", version)
+	}
+	for ; version < MetaDocVersion; version++ {
+		if err := metaDocMigrations[version-1](fields, codec.name); err != nil {
+			return nil, errors.Annotatef(err, "migrating metadata document from version %d", version)
+		}
+	}
+
+	// Round-trip the migrated fields through the codec, so that
+	// fields with their own encoding are decoded as usual.
+	data, err := codec.marshal(fields)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var meta Meta
+	if err := codec.unmarshal(data, &meta); err != nil {
+		return nil, errors.Annotatef(err, "unmarshaling %s metadata", codec.name)
+	}
+	return &meta, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/charm/v8"
+)
+
+type MetaDocSuite struct{}
+
+var _ = gc.Suite(&MetaDocSuite{})
+
+const metaDocMetadata = `
+name: a
+summary: b
+description: c
+min-juju-version: 2.8.0
+series: [focal, bionic]
+provides:
+  website:
+    interface: http
+    ports: [80]
+storage:
+  data:
+    type: filesystem
+    location: /srv/data
+`
+
+func (s *MetaDocSuite) readMeta(c *gc.C) *charm.Meta {
+	meta, err := charm.ReadMeta(strings.NewReader(metaDocMetadata))
+	c.Assert(err, jc.ErrorIsNil)
+	return meta
+}
+
+func (s *MetaDocSuite) TestRoundTripBSON(c *gc.C) {
+	meta := s.readMeta(c)
+	data, err := charm.MarshalMetaBSON(meta)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var doc bson.M
+	err = bson.Unmarshal(data, &doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc["schema-version"], gc.Equals, charm.MetaDocVersion)
+
+	loaded, err := charm.LoadMetaBSON(data)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(loaded.Equal(meta), jc.IsTrue, gc.Commentf("%#v", loaded))
+}
+
+func (s *MetaDocSuite) TestRoundTripJSON(c *gc.C) {
+	meta := s.readMeta(c)
+	data, err := charm.MarshalMetaJSON(meta)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var doc map[string]interface{}
+	err = json.Unmarshal(data, &doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc["schema-version"], gc.Equals, float64(charm.MetaDocVersion))
+
+	loaded, err := charm.LoadMetaJSON(data)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(loaded.Equal(meta), jc.IsTrue, gc.Commentf("%#v", loaded))
+}
+
+func (s *MetaDocSuite) TestLoadUnversionedBSON(c *gc.C) {
+	meta := s.readMeta(c)
+	data, err := bson.Marshal(meta)
+	c.Assert(err, jc.ErrorIsNil)
+
+	loaded, err := charm.LoadMetaBSON(data)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(loaded.Equal(meta), jc.IsTrue, gc.Commentf("%#v", loaded))
+}
+
+func (s *MetaDocSuite) TestLoadUnversionedJSON(c *gc.C) {
+	data := []byte(`{"Name": "a", "Summary": "b", "Description": "c", "SupportedSeries": ["focal"]}`)
+	loaded, err := charm.LoadMetaJSON(data)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(loaded, jc.DeepEquals, &charm.Meta{
+		Name:        "a",
+		Summary:     "b",
+		Description: "c",
+		Series:      []string{"focal"},
+	})
+}
+
+func (s *MetaDocSuite) TestLoadFutureVersion(c *gc.C) {
+	data := []byte(`{"schema-version": 99, "payload": {"Name": "a"}}`)
+	_, err := charm.LoadMetaJSON(data)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, `metadata document version 99 not supported`)
+}
+
+func (s *MetaDocSuite) TestLoadInvalid(c *gc.C) {
+	_, err := charm.LoadMetaJSON([]byte(`[1, 2]`))
+	c.Assert(err, gc.ErrorMatches, `unmarshaling json metadata document: .*`)
+}
+
+func (s *MetaDocSuite) TestLoadMissingPayload(c *gc.C) {
+	for _, data := range []string{
+		`{"schema-version": 1}`,
+		`{"schema-version": 1, "payload": null}`,
+	} {
+		_, err := charm.LoadMetaJSON([]byte(data))
+		c.Check(err, jc.Satisfies, errors.IsNotValid, gc.Commentf("%s", data))
+		c.Check(err, gc.ErrorMatches, `metadata document without payload not valid`)
+	}
+
+	data, err := bson.Marshal(map[string]interface{}{"schema-version": 1})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = charm.LoadMetaBSON(data)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *MetaDocSuite) TestLoadVersionZero(c *gc.C) {
+	_, err := charm.LoadMetaJSON([]byte(`{"schema-version": 0, "payload": {"Name": "a"}}`))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *MetaDocSuite) TestLoadVersion1JSON(c *gc.C) {
+	data := []byte(`{"schema-version": 1, "payload": {
+		"Name": "a", "Summary": "b", "Description": "c",
+		"Provides": {"website": {"Name": "website", "Role": "provider", "Interface": "http", "Optional": false, "Limit": 0, "Scope": "global"}},
+		"Storage": {"data": {"Name": "data", "Type": "filesystem", "CountMin": 1, "CountMax": 1, "Location": "/srv/data"}}
+	}}`)
+	loaded, err := charm.LoadMetaJSON(data)
+	c.Assert(err, jc.ErrorIsNil)
+	meta, err := charm.ReadMeta(strings.NewReader(`
+name: a
+summary: b
+description: c
+provides:
+  website: http
+storage:
+  data:
+    type: filesystem
+    location: /srv/data
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(loaded, jc.DeepEquals, meta)
+}
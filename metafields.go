@@ -0,0 +1,91 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+)
+
+var validMetaFieldName = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+var (
+	metaFieldsMutex sync.RWMutex
+	metaFields      = map[string]schema.Checker{}
+)
+
+// RegisterMetaField adds a top level field, such as an "x-" prefixed
+// vendor extension, to those accepted in metadata.yaml. When the field
+// is present, ReadMeta coerces it with checker and fails if it does not
+// conform; the coerced value is held in Meta.Extra. ReadMetaStrict
+// accepts the field rather than rejecting it as unknown. It returns an
+// error if the name is not valid, is a field known to this package or
+// is already registered.
+func RegisterMetaField(name string, checker schema.Checker) error {
+	if !validMetaFieldName.MatchString(name) {
+		return errors.NotValidf("metadata field name %q", name)
+	}
+	if checker == nil {
+		return errors.NotValidf("nil checker for metadata field %q", name)
+	}
+	if _, ok := charmFields[name]; ok {
+		return errors.AlreadyExistsf("metadata field %q", name)
+	}
+	for _, informational := range informationalMetaFields {
+		if name == informational {
+			return errors.AlreadyExistsf("metadata field %q", name)
+		}
+	}
+	metaFieldsMutex.Lock()
+	defer metaFieldsMutex.Unlock()
+	if _, ok := metaFields[name]; ok {
+		return errors.AlreadyExistsf("metadata field %q", name)
+	}
+	metaFields[name] = checker
+	return nil
+}
+
+// RegisteredMetaFields returns the names of the fields added by
+// RegisterMetaField, sorted.
+func RegisteredMetaFields() []string {
+	metaFieldsMutex.RLock()
+	defer metaFieldsMutex.RUnlock()
+	names := make([]string, 0, len(metaFields))
+	for name := range metaFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResetMetaFields discards the fields added by RegisterMetaField. It is
+// meant for tests of code that registers fields.
+func ResetMetaFields() {
+	metaFieldsMutex.Lock()
+	defer metaFieldsMutex.Unlock()
+	metaFields = map[string]schema.Checker{}
+}
+
+// coerceRegisteredFields replaces each registered field held in extra
+// with its value as coerced by the field's checker, in name order.
+func coerceRegisteredFields(extra map[string]interface{}) error {
+	metaFieldsMutex.RLock()
+	defer metaFieldsMutex.RUnlock()
+	for _, name := range sortedNames(extra) {
+		checker, ok := metaFields[name]
+		if !ok {
+			continue
+		}
+		v, err := checker.Coerce(extra[name], []string{name})
+		if err != nil {
+			return errors.New("metadata: " + err.Error())
+		}
+		extra[name] = stringKeyedValue(v)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type MetaFieldsSuite struct{}
+
+var _ = gc.Suite(&MetaFieldsSuite{})
+
+func (s *MetaFieldsSuite) TearDownTest(c *gc.C) {
+	charm.ResetMetaFields()
+}
+
+var vendorFieldSchema = schema.FieldMap(
+	schema.Fields{
+		"team":  schema.String(),
+		"level": schema.Int(),
+	},
+	schema.Defaults{
+		"level": 1,
+	},
+)
+
+func (s *MetaFieldsSuite) TestRegisteredField(c *gc.C) {
+	err := charm.RegisterMetaField("x-vendor", vendorFieldSchema)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charm.RegisteredMetaFields(), jc.DeepEquals, []string{"x-vendor"})
+
+	metadata := dummyMetadata + "\nx-vendor:\n  team: storage\n"
+	meta, err := charm.ReadMeta(strings.NewReader(metadata))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.Extra, jc.DeepEquals, map[string]interface{}{
+		"x-vendor": map[string]interface{}{
+			"team":  "storage",
+			"level": int64(1),
+		},
+	})
+
+	_, err = charm.ReadMetaStrict(strings.NewReader(metadata))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = charm.ReadMeta(strings.NewReader(dummyMetadata + "\nx-vendor:\n  team: storage\n  level: high\n"))
+	c.Assert(err, gc.ErrorMatches, `metadata: x-vendor.level: expected int, got string\("high"\)`)
+}
+
+func (s *MetaFieldsSuite) TestUnregisteredField(c *gc.C) {
+	metadata := dummyMetadata + "\nx-vendor: [1, 2]\n"
+	meta, err := charm.ReadMeta(strings.NewReader(metadata))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.Extra, jc.DeepEquals, map[string]interface{}{
+		"x-vendor": []interface{}{1, 2},
+	})
+	_, err = charm.ReadMetaStrict(strings.NewReader(metadata))
+	c.Assert(err, gc.ErrorMatches, `metadata: unknown field\(s\): x-vendor`)
+}
+
+func (s *MetaFieldsSuite) TestRegisterErrors(c *gc.C) {
+	err := charm.RegisterMetaField("X_Vendor", schema.String())
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	err = charm.RegisterMetaField("x-vendor", nil)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	err = charm.RegisterMetaField("provides", schema.String())
+	c.Check(err, jc.Satisfies, errors.IsAlreadyExists)
+	err = charm.RegisterMetaField("maintainer", schema.String())
+	c.Check(err, jc.Satisfies, errors.IsAlreadyExists)
+
+	err = charm.RegisterMetaField("x-vendor", schema.String())
+	c.Assert(err, jc.ErrorIsNil)
+	err = charm.RegisterMetaField("x-vendor", schema.String())
+	c.Check(err, gc.ErrorMatches, `metadata field "x-vendor" already exists`)
+
+	charm.ResetMetaFields()
+	c.Check(charm.RegisteredMetaFields(), gc.HasLen, 0)
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"encoding/json"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// MarshalJSON implements json.Marshaler. The metadata is marshaled with
+// the same fields as metadata.yaml, and relations that hold only
+// default attributes are marshaled as their interface name, just as
+// they are by MarshalYAML.
+func (m Meta) MarshalJSON() ([]byte, error) {
+	return yamlValueAsJSON(m)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts the same fields
+// and shorthand as ReadMeta, and checks the metadata in the same way.
+func (m *Meta) UnmarshalJSON(data []byte) error {
+	yamlData, err := jsonAsYAML(data)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var meta Meta
+	if err := yaml.Unmarshal(yamlData, &meta); err != nil {
+		return err
+	}
+	*m = meta
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The relation is marshaled as
+// it would be within the provides, requires or peers section of
+// metadata.yaml, so that its name and role are not included.
+func (r Relation) MarshalJSON() ([]byte, error) {
+	v, err := marshaledRelation(r).MarshalYAML()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return yamlValueAsJSON(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts the relation
+// in any of the forms allowed in metadata.yaml. As the name and role of
+// the relation are not held in the JSON, they are left unchanged.
+func (r *Relation) UnmarshalJSON(data []byte) error {
+	v, err := jsonAsYAMLValue(data)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	v, err = ifaceExpander(nil).Coerce(v, []string{"relation"})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	*r = parseRelations(map[string]interface{}{r.Name: v}, r.Role)[r.Name]
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The store is marshaled as it
+// would be within the storage section of metadata.yaml, so that its
+// name is not included.
+func (s Storage) MarshalJSON() ([]byte, error) {
+	v, err := marshaledStorage(s).MarshalYAML()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return yamlValueAsJSON(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts the store as
+// it is written in metadata.yaml. As the name of the store is not held
+// in the JSON, it is left unchanged.
+func (s *Storage) UnmarshalJSON(data []byte) error {
+	v, err := jsonAsYAMLValue(data)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	v, err = storageSchema.Coerce(v, []string{"storage"})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	*s = parseStorage(map[string]interface{}{s.Name: v})[s.Name]
+	return nil
+}
+
+// yamlValueAsJSON returns the JSON encoding of v as it would be
+// marshaled to YAML, so that the YAML field names and MarshalYAML
+// methods are used.
+func yamlValueAsJSON(v interface{}) ([]byte, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return json.Marshal(stringKeyedValue(raw))
+}
+
+// jsonAsYAMLValue returns the JSON document held in data as it would be
+// unmarshaled from YAML, so that it can be checked by the same schema
+// as metadata.yaml. In particular, whole numbers are held as integers.
+func jsonAsYAMLValue(data []byte) (interface{}, error) {
+	yamlData, err := jsonAsYAML(data)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var v interface{}
+	if err := yaml.Unmarshal(yamlData, &v); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return v, nil
+}
+
+// jsonAsYAML returns the JSON document held in data as YAML.
+func jsonAsYAML(data []byte) ([]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return yaml.Marshal(v)
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type MetaJSONSuite struct{}
+
+var _ = gc.Suite(&MetaJSONSuite{})
+
+const metaJSONMetadata = `
+name: a
+summary: b
+description: c
+provides:
+  website: http
+  admin:
+    interface: http
+    limit: 1
+    ports: [8080]
+requires:
+  db:
+    interface: mysql
+    optional: true
+storage:
+  data:
+    type: filesystem
+    location: /srv/data
+    multiple:
+      range: 1-3
+    minimum-size: 2G
+`
+
+func (s *MetaJSONSuite) TestMarshal(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(metaJSONMetadata))
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := json.Marshal(meta)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.JSONEquals, map[string]interface{}{
+		"name":        "a",
+		"summary":     "b",
+		"description": "c",
+		"provides": map[string]interface{}{
+			"website": "http",
+			"admin": map[string]interface{}{
+				"interface": "http",
+				"limit":     1,
+				"ports":     []string{"8080/tcp"},
+			},
+		},
+		"requires": map[string]interface{}{
+			"db": map[string]interface{}{
+				"interface": "mysql",
+				"optional":  true,
+			},
+		},
+		"storage": map[string]interface{}{
+			"data": map[string]interface{}{
+				"type":         "filesystem",
+				"location":     "/srv/data",
+				"multiple":     map[string]interface{}{"range": "1-3"},
+				"minimum-size": "2048M",
+			},
+		},
+	})
+}
+
+func (s *MetaJSONSuite) TestRoundTripTestCharms(c *gc.C) {
+	dirs, err := ioutil.ReadDir(filepath.Join("internal", "test-charm-repo", "quantal"))
+	c.Assert(err, jc.ErrorIsNil)
+	for _, dir := range dirs {
+		data, err := ioutil.ReadFile(filepath.Join("internal", "test-charm-repo", "quantal", dir.Name(), "metadata.yaml"))
+		if err != nil {
+			continue
+		}
+		meta, err := charm.ReadMeta(bytes.NewReader(data))
+		if err != nil {
+			continue
+		}
+		c.Logf("charm %s", dir.Name())
+		data, err = json.Marshal(meta)
+		c.Assert(err, jc.ErrorIsNil)
+		var meta1 charm.Meta
+		err = json.Unmarshal(data, &meta1)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(&meta1, jc.DeepEquals, meta)
+	}
+}
+
+func (s *MetaJSONSuite) TestUnmarshalShorthand(c *gc.C) {
+	var meta charm.Meta
+	err := json.Unmarshal([]byte(`{
+		"name": "a",
+		"summary": "b",
+		"description": "c",
+		"requires": {"db": "mysql"},
+		"peers": {"cluster": {"interface": "ring", "limit": 2}}
+	}`), &meta)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.Requires, jc.DeepEquals, map[string]charm.Relation{
+		"db": {
+			Name:      "db",
+			Role:      charm.RoleRequirer,
+			Interface: "mysql",
+			Scope:     charm.ScopeGlobal,
+		},
+	})
+	c.Assert(meta.Peers, jc.DeepEquals, map[string]charm.Relation{
+		"cluster": {
+			Name:      "cluster",
+			Role:      charm.RolePeer,
+			Interface: "ring",
+			Limit:     2,
+			Scope:     charm.ScopeGlobal,
+		},
+	})
+}
+
+func (s *MetaJSONSuite) TestUnmarshalInvalid(c *gc.C) {
+	var meta charm.Meta
+	err := json.Unmarshal([]byte(`{"name": "a", "summary": "b", "description": "c", "subordinate": true}`), &meta)
+	c.Assert(err, gc.ErrorMatches, `subordinate charm "a" lacks "requires" relation with container scope`)
+	err = json.Unmarshal([]byte(`{"name": "a", "summary": "b", "description": "c", "requires": {"db": 1}}`), &meta)
+	c.Assert(err, gc.ErrorMatches, `metadata: requires.db: .*`)
+}
+
+func (s *MetaJSONSuite) TestRelation(c *gc.C) {
+	relation := charm.Relation{
+		Name:      "db",
+		Role:      charm.RoleRequirer,
+		Interface: "mysql",
+		Scope:     charm.ScopeContainer,
+	}
+	data, err := json.Marshal(relation)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, `{"interface":"mysql","scope":"container"}`)
+
+	// The name and role are kept.
+	relation1 := charm.Relation{Name: "db", Role: charm.RoleRequirer}
+	err = json.Unmarshal(data, &relation1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(relation1, jc.DeepEquals, relation)
+
+	data, err = json.Marshal(charm.Relation{Interface: "http", Scope: charm.ScopeGlobal})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, `"http"`)
+	var relation2 charm.Relation
+	err = json.Unmarshal(data, &relation2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(relation2, jc.DeepEquals, charm.Relation{Interface: "http", Scope: charm.ScopeGlobal})
+
+	err = json.Unmarshal([]byte(`{"interface": "http", "scope": "galaxy"}`), &relation2)
+	c.Assert(err, gc.ErrorMatches, `relation.scope: .*`)
+}
+
+func (s *MetaJSONSuite) TestStorage(c *gc.C) {
+	var store charm.Storage
+	err := json.Unmarshal([]byte(`{"type": "block", "multiple": {"range": "2-"}, "minimum-size": "1G"}`), &store)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(store, jc.DeepEquals, charm.Storage{
+		Type:        charm.StorageBlock,
+		CountMin:    2,
+		CountMax:    -1,
+		MinimumSize: 1024,
+	})
+
+	data, err := json.Marshal(store)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.JSONEquals, map[string]interface{}{
+		"type":         "block",
+		"multiple":     map[string]interface{}{"range": "2-"},
+		"minimum-size": "1024M",
+	})
+
+	err = json.Unmarshal([]byte(`{"type": "tape"}`), &store)
+	c.Assert(err, gc.ErrorMatches, `storage.type: .*`)
+}
@@ -0,0 +1,377 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/juju/systems"
+
+	"github.com/juju/charm/v8/resource"
+)
+
+// MetaJSONSchemaID is the identifier used for the JSON Schema returned
+// by MetaJSONSchema.
+const MetaJSONSchemaID = "https://juju.is/schemas/charm-metadata.json"
+
+// MetaJSONSchema returns a JSON Schema (draft-07) document describing
+// valid metadata.yaml files. It mirrors the checkers used by ReadMeta,
+// so that editors using it (for example through yaml-language-server)
+// flag the same mistakes as this package does. Checks that ReadMeta
+// performs after the schema has been applied, such as those relating
+// fields to each other, are not expressed.
+func MetaJSONSchema() ([]byte, error) {
+	return json.MarshalIndent(metaJSONSchema(), "", "  ")
+}
+
+// ConfigJSONSchemaID is the identifier used for the JSON Schema
+// returned by ConfigJSONSchema.
+const ConfigJSONSchemaID = "https://juju.is/schemas/charm-config.json"
+
+// ConfigJSONSchema returns a JSON Schema (draft-07) document describing
+// valid config.yaml files, as read by ReadConfig. That a default value
+// suits the type of its option is not expressed.
+func ConfigJSONSchema() ([]byte, error) {
+	return json.MarshalIndent(configJSONSchema(), "", "  ")
+}
+
+// ActionsJSONSchemaID is the identifier used for the JSON Schema
+// returned by ActionsJSONSchema.
+const ActionsJSONSchemaID = "https://juju.is/schemas/charm-actions.json"
+
+// ActionsJSONSchema returns a JSON Schema (draft-07) document describing
+// valid actions.yaml files, as read by ReadActionsYaml. The parameters
+// of each action are themselves a JSON Schema, which is not checked
+// beyond being an object.
+func ActionsJSONSchema() ([]byte, error) {
+	return json.MarshalIndent(actionsJSONSchema(), "", "  ")
+}
+
+// jsonObject is a shorthand for the objects making up a JSON Schema.
+type jsonObject = map[string]interface{}
+
+func metaJSONSchema() jsonObject {
+	stringList := jsonList(jsonString())
+	return jsonObject{
+		"$schema":  "http://json-schema.org/draft-07/schema#",
+		"$id":      MetaJSONSchemaID,
+		"title":    "Charm metadata",
+		"type":     "object",
+		"required": []string{"name", "summary", "description"},
+		"properties": jsonObject{
+			"name":             jsonString(),
+			"summary":          jsonString(),
+			"description":      jsonString(),
+			"peers":            jsonStringMap(relationJSONSchema()),
+			"provides":         jsonStringMap(relationJSONSchema()),
+			"requires":         jsonStringMap(relationJSONSchema()),
+			"extra-bindings":   extraBindingsJSONSchema(),
+			"revision":         jsonObject{"type": "integer", "deprecated": true},
+			"format":           jsonObject{"type": "integer", "deprecated": true},
+			"subordinate":      jsonObject{"type": "boolean"},
+			"categories":       stringList,
+			"tags":             stringList,
+			"series":           stringList,
+			"storage":          jsonStringMap(storageJSONSchema()),
+			"devices":          jsonStringMap(deviceJSONSchema()),
+			"deployment":       deploymentJSONSchema(),
+			"payloads":         jsonStringMap(payloadClassJSONSchema()),
+			"resources":        jsonStringMap(resourceJSONSchema()),
+			"terms":            stringList,
+			"min-juju-version": jsonString(),
+			"charm-user": jsonEnum(
+				string(CharmUserRoot),
+				string(CharmUserSudoer),
+				string(CharmUserNonRoot),
+			),
+			"charm-user-group": jsonObject{
+				"type":    "string",
+				"pattern": validCharmUserGroup.String(),
+			},
+			"website":          urlListJSONSchema(),
+			"docs":             urlListJSONSchema(),
+			"issues":           urlListJSONSchema(),
+			"summary-i18n":     translationsJSONSchema(),
+			"description-i18n": translationsJSONSchema(),
+			"deprecated":       deprecationJSONSchema(),
+			"superseded-by":    jsonString(),
+			"platforms":        stringList,
+			"architectures":    stringList,
+			"systems":          jsonList(systemJSONSchema()),
+			"bases":            jsonList(baseJSONSchema()),
+			"containers":       jsonStringMap(containerJSONSchema()),
+		},
+	}
+}
+
+func configJSONSchema() jsonObject {
+	types := make([]string, 0, len(optionTypeCheckers))
+	for name := range optionTypeCheckers {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	return jsonObject{
+		"$schema":  "http://json-schema.org/draft-07/schema#",
+		"$id":      ConfigJSONSchemaID,
+		"title":    "Charm configuration",
+		"type":     "object",
+		"required": []string{"options"},
+		"properties": jsonObject{
+			"options": jsonStringMap(jsonFields(jsonObject{
+				// A missing type is taken to be "string".
+				"type":        jsonEnum(types...),
+				"description": jsonString(),
+				"default": jsonObject{
+					"type": []string{"string", "number", "boolean", "array", "null"},
+				},
+				"deprecated":  jsonObject{"type": "boolean"},
+				"replaced-by": jsonString(),
+				"allowed": jsonList(jsonObject{
+					"type": []string{"string", "integer"},
+				}),
+				"minimum":   jsonObject{"type": "number"},
+				"maximum":   jsonObject{"type": "number"},
+				"pattern":   jsonString(),
+				"sensitive": jsonObject{"type": "boolean"},
+			})),
+		},
+	}
+}
+
+func actionsJSONSchema() jsonObject {
+	return jsonObject{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"$id":     ActionsJSONSchemaID,
+		"title":   "Charm actions",
+		"type":    "object",
+		// See reservedName.
+		"propertyNames": jsonObject{
+			"pattern": actionNameRule.String(),
+			"not":     jsonObject{"pattern": "^juju(-.*)?$"},
+		},
+		"additionalProperties": jsonFields(jsonObject{
+			"description": jsonString(),
+			"title":       jsonString(),
+			"required":    jsonList(jsonString()),
+			"params":      jsonStringMap(jsonObject{"type": "object"}),
+		}),
+	}
+}
+
+// relationJSONSchema mirrors ifaceExpander: a relation is either the
+// name of its interface or a map describing it in full.
+func relationJSONSchema() jsonObject {
+	return jsonObject{
+		"oneOf": []interface{}{
+			jsonString(),
+			jsonFields(jsonObject{
+				"interface":         jsonString(),
+				"interface-aliases": jsonList(jsonString()),
+				"limit":             jsonObject{"type": []string{"integer", "null"}},
+				"scope":             jsonEnum(string(ScopeGlobal), string(ScopeContainer)),
+				"optional":          jsonObject{"type": "boolean"},
+				"description":       jsonString(),
+				"renamed-from":      jsonString(),
+				"ports": jsonList(jsonObject{
+					// See ParseEndpointPort.
+					"oneOf": []interface{}{
+						jsonObject{"type": "integer", "minimum": 1, "maximum": 65535},
+						jsonObject{"type": "string", "pattern": "^(icmp|" + strings.Trim(endpointPortRE.String(), "^$") + ")$"},
+					},
+				}),
+			}, "interface"),
+		},
+	}
+}
+
+// extraBindingsJSONSchema mirrors extraBindingsSchema: a map of binding
+// names to empty values.
+func extraBindingsJSONSchema() jsonObject {
+	return jsonObject{
+		"type":          "object",
+		"propertyNames": jsonObject{"minLength": 1},
+		"additionalProperties": jsonObject{
+			"type": "null",
+		},
+	}
+}
+
+// urlListJSONSchema mirrors urlListC: either a single URL or a list
+// of them.
+func urlListJSONSchema() jsonObject {
+	link := jsonObject{"type": "string", "format": "uri", "pattern": "^https?://"}
+	return jsonObject{
+		"oneOf": []interface{}{link, jsonList(link)},
+	}
+}
+
+// translationsJSONSchema describes the summary-i18n and
+// description-i18n sections: translations keyed by locale.
+func translationsJSONSchema() jsonObject {
+	schema := jsonStringMap(jsonString())
+	schema["propertyNames"] = jsonObject{"pattern": validLocale.String()}
+	return schema
+}
+
+// deprecationJSONSchema mirrors parseDeprecation: the deprecated
+// section is either a boolean or a map with an optional date and
+// message.
+func deprecationJSONSchema() jsonObject {
+	return jsonObject{
+		"oneOf": []interface{}{
+			jsonObject{"type": "boolean"},
+			jsonFields(jsonObject{
+				"date":    jsonObject{"type": "string", "format": "date"},
+				"message": jsonString(),
+			}),
+		},
+	}
+}
+
+func storageJSONSchema() jsonObject {
+	return jsonFields(jsonObject{
+		"type":      jsonEnum(string(StorageBlock), string(StorageFilesystem)),
+		"shared":    jsonObject{"type": "boolean"},
+		"read-only": jsonObject{"type": "boolean"},
+		"multiple": jsonFields(jsonObject{
+			// See storageCountC.
+			"range": jsonObject{
+				"oneOf": []interface{}{
+					jsonObject{"type": "integer", "minimum": 1},
+					jsonObject{"type": "string", "pattern": storageCountRE.String()},
+				},
+			},
+		}, "range"),
+		"minimum-size": jsonObject{
+			// See utils.ParseSize.
+			"type":    "string",
+			"pattern": `^[0-9]+(\.[0-9]+)?([MGTPEZY](i?B)?)?$`,
+		},
+		// See validateStorageLocation.
+		"location":    jsonObject{"type": "string", "pattern": "^/"},
+		"description": jsonString(),
+		"properties":  jsonList(jsonEnum(StoragePropertyTransient)),
+	}, "type")
+}
+
+func deviceJSONSchema() jsonObject {
+	count := jsonObject{"type": "integer", "minimum": 0}
+	return jsonFields(jsonObject{
+		"description": jsonString(),
+		"type":        jsonString(),
+		"countmin":    count,
+		"countmax":    count,
+	}, "type")
+}
+
+func deploymentJSONSchema() jsonObject {
+	return jsonFields(jsonObject{
+		"type": jsonEnum(
+			string(DeploymentStateful),
+			string(DeploymentStateless),
+			string(DeploymentDaemon),
+		),
+		"mode": jsonEnum(
+			string(ModeOperator),
+			string(ModeWorkload),
+		),
+		"service": jsonEnum(
+			string(ServiceCluster),
+			string(ServiceLoadBalancer),
+			string(ServiceExternal),
+			string(ServiceOmit),
+		),
+		"min-version": jsonObject{
+			"type":    "string",
+			"pattern": validDeploymentMinVersion.String(),
+		},
+	})
+}
+
+func payloadClassJSONSchema() jsonObject {
+	return jsonFields(jsonObject{
+		"type": jsonString(),
+	}, "type")
+}
+
+func resourceJSONSchema() jsonObject {
+	return jsonFields(jsonObject{
+		"type":        jsonEnum(resource.TypeFile.String(), resource.TypeContainerImage.String()),
+		"filename":    jsonString(),
+		"description": jsonString(),
+	})
+}
+
+func systemJSONSchema() jsonObject {
+	return jsonFields(jsonObject{
+		"os": jsonEnum(
+			systems.Ubuntu,
+			systems.Windows,
+			systems.CentOS,
+			systems.OpenSUSE,
+			systems.GenericLinux,
+			systems.OSX,
+		),
+		"channel":  jsonString(),
+		"resource": jsonString(),
+	})
+}
+
+func baseJSONSchema() jsonObject {
+	return jsonFields(jsonObject{
+		"name": jsonEnum(
+			systems.Ubuntu,
+			systems.Windows,
+			systems.CentOS,
+			systems.OpenSUSE,
+			systems.GenericLinux,
+			systems.OSX,
+		),
+		"channel": jsonString(),
+		"architectures": jsonList(jsonEnum(
+			string(AMD64),
+			string(ARM64),
+			string(PPC64EL),
+			string(S390X),
+		)),
+	}, "name", "channel")
+}
+
+func containerJSONSchema() jsonObject {
+	return jsonFields(jsonObject{
+		"systems": jsonList(systemJSONSchema()),
+		"mounts": jsonList(jsonFields(jsonObject{
+			"storage":  jsonString(),
+			"location": jsonString(),
+		})),
+	})
+}
+
+func jsonString() jsonObject {
+	return jsonObject{"type": "string"}
+}
+
+func jsonList(items jsonObject) jsonObject {
+	return jsonObject{"type": "array", "items": items}
+}
+
+func jsonStringMap(values jsonObject) jsonObject {
+	return jsonObject{"type": "object", "additionalProperties": values}
+}
+
+func jsonEnum(values ...string) jsonObject {
+	return jsonObject{"type": "string", "enum": values}
+}
+
+// jsonFields returns the schema for an object holding the given
+// properties, of which the named ones are required.
+func jsonFields(properties jsonObject, required ...string) jsonObject {
+	obj := jsonObject{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		obj["required"] = required
+	}
+	return obj
+}
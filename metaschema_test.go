@@ -0,0 +1,182 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type MetaSchemaSuite struct{}
+
+var _ = gc.Suite(&MetaSchemaSuite{})
+
+func (s *MetaSchemaSuite) readSchema(c *gc.C) map[string]interface{} {
+	return readJSONSchema(c, charm.MetaJSONSchema)
+}
+
+func readJSONSchema(c *gc.C, jsonSchema func() ([]byte, error)) map[string]interface{} {
+	data, err := jsonSchema()
+	c.Assert(err, jc.ErrorIsNil)
+	var doc map[string]interface{}
+	err = json.Unmarshal(data, &doc)
+	c.Assert(err, jc.ErrorIsNil)
+	return doc
+}
+
+func (s *MetaSchemaSuite) TestTopLevel(c *gc.C) {
+	doc := s.readSchema(c)
+	c.Assert(doc["$id"], gc.Equals, charm.MetaJSONSchemaID)
+	c.Assert(doc["type"], gc.Equals, "object")
+	c.Assert(doc["required"], jc.DeepEquals, []interface{}{"name", "summary", "description"})
+
+	var names []string
+	for name := range doc["properties"].(map[string]interface{}) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	c.Assert(names, jc.DeepEquals, []string{
+		"architectures",
+		"bases",
+		"categories",
+		"charm-user",
+		"charm-user-group",
+		"containers",
+		"deployment",
+		"deprecated",
+		"description",
+		"description-i18n",
+		"devices",
+		"docs",
+		"extra-bindings",
+		"format",
+		"issues",
+		"min-juju-version",
+		"name",
+		"payloads",
+		"peers",
+		"platforms",
+		"provides",
+		"requires",
+		"resources",
+		"revision",
+		"series",
+		"storage",
+		"subordinate",
+		"summary",
+		"summary-i18n",
+		"superseded-by",
+		"systems",
+		"tags",
+		"terms",
+		"website",
+	})
+}
+
+func (s *MetaSchemaSuite) TestRelation(c *gc.C) {
+	doc := s.readSchema(c)
+	provides := doc["properties"].(map[string]interface{})["provides"].(map[string]interface{})
+	relation := provides["additionalProperties"].(map[string]interface{})
+	oneOf := relation["oneOf"].([]interface{})
+	c.Assert(oneOf, gc.HasLen, 2)
+	full := oneOf[1].(map[string]interface{})
+	c.Assert(full["required"], jc.DeepEquals, []interface{}{"interface"})
+	scope := full["properties"].(map[string]interface{})["scope"].(map[string]interface{})
+	c.Assert(scope["enum"], jc.DeepEquals, []interface{}{"global", "container"})
+}
+
+func (s *MetaSchemaSuite) TestStoragePatterns(c *gc.C) {
+	doc := s.readSchema(c)
+	storage := doc["properties"].(map[string]interface{})["storage"].(map[string]interface{})
+	props := storage["additionalProperties"].(map[string]interface{})["properties"].(map[string]interface{})
+
+	size := regexp.MustCompile(props["minimum-size"].(map[string]interface{})["pattern"].(string))
+	for _, valid := range []string{"1", "10M", "1.5G", "2GB", "4GiB"} {
+		c.Check(size.MatchString(valid), jc.IsTrue, gc.Commentf("%q", valid))
+	}
+	for _, invalid := range []string{"", "B", "1X", "-1G"} {
+		c.Check(size.MatchString(invalid), jc.IsFalse, gc.Commentf("%q", invalid))
+	}
+
+	multiple := props["multiple"].(map[string]interface{})
+	rangeOneOf := multiple["properties"].(map[string]interface{})["range"].(map[string]interface{})["oneOf"].([]interface{})
+	count := regexp.MustCompile(rangeOneOf[1].(map[string]interface{})["pattern"].(string))
+	for _, valid := range []string{"1-", "1+", "1-10"} {
+		c.Check(count.MatchString(valid), jc.IsTrue, gc.Commentf("%q", valid))
+	}
+	c.Check(count.MatchString("x"), jc.IsFalse)
+}
+
+func (s *MetaSchemaSuite) TestPropertiesMatchCheckers(c *gc.C) {
+	doc := s.readSchema(c)
+	properties := func(obj interface{}) map[string]interface{} {
+		return obj.(map[string]interface{})["properties"].(map[string]interface{})
+	}
+	values := func(obj interface{}) interface{} {
+		return obj.(map[string]interface{})["additionalProperties"]
+	}
+	items := func(obj interface{}) interface{} {
+		return obj.(map[string]interface{})["items"]
+	}
+	charmProps := properties(doc)
+	containerProps := properties(values(charmProps["containers"]))
+	relation := values(charmProps["provides"]).(map[string]interface{})["oneOf"].([]interface{})[1]
+	schemaProperties := map[string]map[string]interface{}{
+		"charm":      charmProps,
+		"relation":   properties(relation),
+		"storage":    properties(values(charmProps["storage"])),
+		"device":     properties(values(charmProps["devices"])),
+		"deployment": properties(charmProps["deployment"]),
+		"payload":    properties(values(charmProps["payloads"])),
+		"resource":   properties(values(charmProps["resources"])),
+		"system":     properties(items(charmProps["systems"])),
+		"container":  containerProps,
+		"mount":      properties(items(containerProps["mounts"])),
+	}
+	c.Assert(schemaProperties, gc.HasLen, len(charm.MetaSchemaFields))
+	for part, fields := range charm.MetaSchemaFields {
+		var accepted, described []string
+		for name := range fields {
+			accepted = append(accepted, name)
+		}
+		for name := range schemaProperties[part] {
+			described = append(described, name)
+		}
+		sort.Strings(accepted)
+		sort.Strings(described)
+		c.Check(described, jc.DeepEquals, accepted, gc.Commentf("%s", part))
+	}
+}
+
+func (s *MetaSchemaSuite) TestConfigSchema(c *gc.C) {
+	doc := readJSONSchema(c, charm.ConfigJSONSchema)
+	c.Assert(doc["$id"], gc.Equals, charm.ConfigJSONSchemaID)
+	c.Assert(doc["required"], jc.DeepEquals, []interface{}{"options"})
+	options := doc["properties"].(map[string]interface{})["options"].(map[string]interface{})
+	option := options["additionalProperties"].(map[string]interface{})
+	optionType := option["properties"].(map[string]interface{})["type"].(map[string]interface{})
+	c.Assert(optionType["enum"], jc.DeepEquals, []interface{}{
+		"boolean", "duration", "float", "int", "list", "secret", "size", "string",
+	})
+}
+
+func (s *MetaSchemaSuite) TestActionsSchema(c *gc.C) {
+	doc := readJSONSchema(c, charm.ActionsJSONSchema)
+	c.Assert(doc["$id"], gc.Equals, charm.ActionsJSONSchemaID)
+	names := doc["propertyNames"].(map[string]interface{})
+	valid := regexp.MustCompile(names["pattern"].(string))
+	reserved := regexp.MustCompile(names["not"].(map[string]interface{})["pattern"].(string))
+	for _, name := range []string{"snapshot", "juju-run", "juju", "jujutsu", "Bad", "-bad"} {
+		c.Logf("action %q", name)
+		_, err := charm.ReadActionsYaml(strings.NewReader(name + ":\n  description: d\n"))
+		c.Check(valid.MatchString(name) && !reserved.MatchString(name), gc.Equals, err == nil)
+	}
+}
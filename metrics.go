@@ -6,7 +6,6 @@ package charm
 import (
 	"fmt"
 	"io"
-	"io/ioutil"
 	"strconv"
 	"strings"
 
@@ -75,11 +74,14 @@ type Metrics struct {
 }
 
 // ReadMetrics reads a MetricsDeclaration in YAML format.
-func ReadMetrics(r io.Reader) (*Metrics, error) {
-	data, err := ioutil.ReadAll(r)
+func ReadMetrics(r io.Reader) (_ *Metrics, err error) {
+	data, err := readYAMLInput(r)
 	if err != nil {
 		return nil, err
 	}
+	defer func() {
+		reportParseFailure(MetricsFile, data, err)
+	}()
 	var metrics Metrics
 	if err := goyaml.Unmarshal(data, &metrics); err != nil {
 		return nil, err
@@ -42,6 +42,14 @@ metrics:
 	c.Assert(metrics, gc.NotNil)
 }
 
+func (s *MetricsSuite) TestReadWindowsLineEndings(c *gc.C) {
+	metrics, err := charm.ReadMetrics(strings.NewReader(
+		"\xef\xbb\xbfmetrics:\r\n  blips:\r\n    type: absolute\r\n    description: An absolute metric.\r\n",
+	))
+	c.Assert(err, gc.IsNil)
+	c.Assert(metrics.Metrics["blips"].Description, gc.Equals, "An absolute metric.")
+}
+
 func (s *MetricsSuite) TestNoDescription(c *gc.C) {
 	metrics, err := charm.ReadMetrics(strings.NewReader(`
 metrics:
@@ -0,0 +1,216 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// MigrateEntry describes a single file or directory of a charm archive
+// as it is being copied by MigrateArchive. A Transform may change any
+// of its fields to alter what is written to the new archive.
+type MigrateEntry struct {
+	// Name holds the slash-separated path of the entry within the
+	// archive. Directory names end with a slash.
+	Name string
+
+	// Mode holds the file mode and permission bits of the entry.
+	Mode os.FileMode
+
+	// Content holds the contents of the entry. It is nil for
+	// directories. A Transform replacing the contents should
+	// set it to a reader producing the new ones.
+	Content io.Reader
+
+	// Skip causes the entry to be left out of the new archive.
+	Skip bool
+}
+
+// Transform alters an entry of a charm archive while it is migrated.
+type Transform func(entry *MigrateEntry) error
+
+// MigrateArchive copies the charm archive held in src, which must hold
+// size bytes, to dst, passing each entry through the given transforms
+// in order. Entries are streamed one at a time, so that only entries
+// whose contents are rewritten by a transform need be held in memory.
+//
+// If the metadata.yaml file is changed by the transforms, or another
+// entry is renamed to take its place, the result is checked with
+// ReadMeta before it is written. The transforms may not skip or rename
+// metadata.yaml itself, nor give two entries the same name.
+func MigrateArchive(src io.ReaderAt, size int64, dst io.Writer, transforms ...Transform) error {
+	zipr, err := newZipOpenerFromReader(src, size).openZip()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer zipr.Close()
+
+	zipw := zip.NewWriter(dst)
+	written := make(map[string]bool)
+	for _, fh := range zipr.File {
+		if err := migrateEntry(zipw, fh, transforms, written); err != nil {
+			return errors.Annotatef(err, "migrating %q", fh.Name)
+		}
+	}
+	return zipw.Close()
+}
+
+func migrateEntry(zipw *zip.Writer, fh *zip.File, transforms []Transform, written map[string]bool) error {
+	entry := &MigrateEntry{
+		Name: fh.Name,
+		Mode: fh.Mode(),
+	}
+	if !entry.Mode.IsDir() {
+		r, err := fh.Open()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		entry.Content = r
+	}
+	original := entry.Content
+	for _, transform := range transforms {
+		if err := transform(entry); err != nil {
+			return err
+		}
+		if entry.Skip {
+			break
+		}
+	}
+	if fh.Name == MetadataFile && (entry.Skip || entry.Name != MetadataFile) {
+		return errors.Errorf("cannot skip or rename %s", MetadataFile)
+	}
+	if entry.Skip {
+		return nil
+	}
+	if written[entry.Name] {
+		return errors.AlreadyExistsf("entry %q", entry.Name)
+	}
+	written[entry.Name] = true
+	if entry.Name == MetadataFile && (entry.Content != original || fh.Name != MetadataFile) {
+		data, err := ioutil.ReadAll(entry.Content)
+		if err != nil {
+			return err
+		}
+		if _, err := ReadMetaBytes(data, ReadMetaOptions{}); err != nil {
+			return errors.Annotate(err, "migrated metadata is invalid")
+		}
+		entry.Content = bytes.NewReader(data)
+	}
+
+	h := &zip.FileHeader{
+		Name:     entry.Name,
+		Method:   fh.Method,
+		Modified: fh.Modified,
+	}
+	h.SetMode(entry.Mode)
+	w, err := zipw.CreateHeader(h)
+	if err != nil || entry.Content == nil {
+		return err
+	}
+	_, err = io.Copy(w, entry.Content)
+	return err
+}
+
+// MetadataTransform returns a Transform that rewrites the charm's
+// metadata.yaml by applying f to its top level fields. The order of
+// the fields is preserved.
+func MetadataTransform(f func(fields yaml.MapSlice) (yaml.MapSlice, error)) Transform {
+	return func(entry *MigrateEntry) error {
+		if entry.Name != MetadataFile || entry.Content == nil {
+			return nil
+		}
+		data, err := readYAMLInput(entry.Content)
+		if err != nil {
+			return err
+		}
+		var fields yaml.MapSlice
+		if err := yaml.Unmarshal(data, &fields); err != nil {
+			return errors.Annotate(err, "parsing metadata.yaml")
+		}
+		if fields, err = f(fields); err != nil {
+			return err
+		}
+		if data, err = yaml.Marshal(fields); err != nil {
+			return err
+		}
+		entry.Content = bytes.NewReader(data)
+		return nil
+	}
+}
+
+// AddSeries returns a Transform that adds the given series to those
+// supported by the charm, unless they are listed already.
+func AddSeries(series ...string) Transform {
+	return MetadataTransform(func(fields yaml.MapSlice) (yaml.MapSlice, error) {
+		for i, item := range fields {
+			if item.Key != "series" {
+				continue
+			}
+			existing, ok := item.Value.([]interface{})
+			if !ok && item.Value != nil {
+				return nil, errors.Errorf("series is not a list")
+			}
+			fields[i].Value = appendSeries(existing, series)
+			return fields, nil
+		}
+		return append(fields, yaml.MapItem{
+			Key:   "series",
+			Value: appendSeries(nil, series),
+		}), nil
+	})
+}
+
+func appendSeries(existing []interface{}, series []string) []interface{} {
+	seen := make(map[interface{}]bool)
+	for _, s := range existing {
+		seen[s] = true
+	}
+	for _, s := range series {
+		if !seen[s] {
+			existing = append(existing, s)
+			seen[s] = true
+		}
+	}
+	return existing
+}
+
+// StripRevision returns a Transform that removes the obsolete revision
+// field from the charm's metadata.yaml.
+func StripRevision() Transform {
+	return MetadataTransform(func(fields yaml.MapSlice) (yaml.MapSlice, error) {
+		result := fields[:0]
+		for _, item := range fields {
+			if item.Key != "revision" {
+				result = append(result, item)
+			}
+		}
+		return result, nil
+	})
+}
+
+// NormalizePermissions returns a Transform that sets the permissions of
+// the archive entries the same way as CharmDir.ArchiveTo does:
+// directories and executable files become 0755, and other regular
+// files 0644. Symlinks are left unchanged.
+func NormalizePermissions() Transform {
+	return func(entry *MigrateEntry) error {
+		if entry.Mode&os.ModeSymlink != 0 {
+			return nil
+		}
+		perm := os.FileMode(0644)
+		if entry.Mode.IsDir() || entry.Mode&0100 != 0 {
+			perm = 0755
+		}
+		entry.Mode = entry.Mode&^os.ModePerm | perm
+		return nil
+	}
+}
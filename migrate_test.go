@@ -0,0 +1,166 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/charm/v8"
+)
+
+type MigrateSuite struct{}
+
+var _ = gc.Suite(&MigrateSuite{})
+
+const migrateMetadata = `
+name: migrated
+summary: A charm to migrate.
+description: Used by the migration tests.
+revision: 3
+series:
+  - bionic
+`
+
+func (s *MigrateSuite) makeArchive(c *gc.C) []byte {
+	var buf bytes.Buffer
+	zipw := zip.NewWriter(&buf)
+	for _, f := range []struct {
+		name    string
+		mode    os.FileMode
+		content string
+	}{
+		{"metadata.yaml", 0600, migrateMetadata},
+		{"hooks/", os.ModeDir | 0700, ""},
+		{"hooks/install", 0700, "#!/bin/sh\n"},
+		{"README.md", 0666, "readme"},
+	} {
+		h := &zip.FileHeader{Name: f.name, Method: zip.Deflate}
+		h.SetMode(f.mode)
+		w, err := zipw.CreateHeader(h)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = w.Write([]byte(f.content))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(zipw.Close(), jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func (s *MigrateSuite) migrate(c *gc.C, transforms ...charm.Transform) []byte {
+	data := s.makeArchive(c)
+	var out bytes.Buffer
+	err := charm.MigrateArchive(bytes.NewReader(data), int64(len(data)), &out, transforms...)
+	c.Assert(err, jc.ErrorIsNil)
+	return out.Bytes()
+}
+
+func (s *MigrateSuite) TestMigrateNoTransforms(c *gc.C) {
+	data := s.migrate(c)
+	archive, err := charm.ReadCharmArchiveBytes(data)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archive.Meta().Name, gc.Equals, "migrated")
+	c.Assert(archive.Meta().Series, jc.DeepEquals, []string{"bionic"})
+}
+
+func (s *MigrateSuite) TestAddSeriesAndStripRevision(c *gc.C) {
+	data := s.migrate(c, charm.AddSeries("bionic", "focal"), charm.StripRevision())
+	archive, err := charm.ReadCharmArchiveBytes(data)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archive.Meta().Series, jc.DeepEquals, []string{"bionic", "focal"})
+
+	zipr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zipr.File[0].Name, gc.Equals, "metadata.yaml")
+	r, err := zipr.File[0].Open()
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	metadata, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(metadata), gc.Not(jc.Contains), "revision")
+}
+
+func (s *MigrateSuite) TestNormalizePermissions(c *gc.C) {
+	data := s.migrate(c, charm.NormalizePermissions())
+	zipr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	modes := make(map[string]os.FileMode)
+	for _, f := range zipr.File {
+		modes[f.Name] = f.Mode()
+	}
+	c.Assert(modes, jc.DeepEquals, map[string]os.FileMode{
+		"metadata.yaml": 0644,
+		"hooks/":        os.ModeDir | 0755,
+		"hooks/install": 0755,
+		"README.md":     0644,
+	})
+}
+
+func (s *MigrateSuite) TestSkipEntry(c *gc.C) {
+	data := s.migrate(c, func(entry *charm.MigrateEntry) error {
+		entry.Skip = entry.Name == "README.md"
+		return nil
+	})
+	zipr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zipr.File, gc.HasLen, 3)
+	for _, f := range zipr.File {
+		c.Assert(f.Name, gc.Not(gc.Equals), "README.md")
+	}
+}
+
+func (s *MigrateSuite) TestInvalidMetadataRejected(c *gc.C) {
+	data := s.makeArchive(c)
+	dropName := charm.MetadataTransform(func(fields yaml.MapSlice) (yaml.MapSlice, error) {
+		return fields[1:], nil
+	})
+	var out bytes.Buffer
+	err := charm.MigrateArchive(bytes.NewReader(data), int64(len(data)), &out, dropName)
+	c.Assert(err, gc.ErrorMatches, `migrating "metadata.yaml": migrated metadata is invalid: .*`)
+}
+
+func (s *MigrateSuite) TestMetadataRenames(c *gc.C) {
+	data := s.makeArchive(c)
+	var out bytes.Buffer
+	err := charm.MigrateArchive(bytes.NewReader(data), int64(len(data)), &out,
+		func(entry *charm.MigrateEntry) error {
+			switch entry.Name {
+			case "metadata.yaml":
+				entry.Name = "metadata.yaml.orig"
+			case "README.md":
+				entry.Name = "metadata.yaml"
+			}
+			return nil
+		})
+	c.Assert(err, gc.ErrorMatches, `migrating "metadata.yaml": cannot skip or rename metadata.yaml`)
+
+	err = charm.MigrateArchive(bytes.NewReader(data), int64(len(data)), &out,
+		func(entry *charm.MigrateEntry) error {
+			if entry.Name == "README.md" {
+				entry.Name = "metadata.yaml"
+			}
+			return nil
+		})
+	c.Assert(err, gc.ErrorMatches, `migrating "README.md": entry "metadata.yaml" already exists`)
+}
+
+func (s *MigrateSuite) TestDuplicateNamesRejected(c *gc.C) {
+	data := s.makeArchive(c)
+	var out bytes.Buffer
+	err := charm.MigrateArchive(bytes.NewReader(data), int64(len(data)), &out,
+		func(entry *charm.MigrateEntry) error {
+			if entry.Name == "README.md" {
+				entry.Name = "hooks/install"
+			}
+			return nil
+		})
+	c.Assert(err, gc.ErrorMatches, `migrating "README.md": entry "hooks/install" already exists`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+}
@@ -0,0 +1,456 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"archive/zip"
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/charm/v8/resource"
+)
+
+// PackIndexFile is the name of the file holding the index of a pack.
+const PackIndexFile = "pack.yaml"
+
+// PackSignatureFile is the name of the file holding the signature of
+// the index of a pack, if it is signed.
+const PackSignatureFile = "pack.yaml.sig"
+
+// packFormat is the version of the pack layout written by PackWriter.
+const packFormat = 1
+
+// PackMemberKind is the kind of archive held by a pack member.
+type PackMemberKind string
+
+// The kinds of archive a pack may hold.
+const (
+	PackCharm  PackMemberKind = "charm"
+	PackBundle PackMemberKind = "bundle"
+)
+
+// PackMember describes one of the archives held in a pack.
+type PackMember struct {
+	// Name identifies the member within the pack.
+	Name string `yaml:"name"`
+
+	// Kind holds the kind of archive the member is.
+	Kind PackMemberKind `yaml:"kind"`
+
+	// Path holds the path of the archive within the pack.
+	Path string `yaml:"path"`
+
+	// Size holds the size of the archive in bytes.
+	Size int64 `yaml:"size"`
+
+	// SHA384 holds the hex-encoded SHA-384 digest of the archive, if
+	// it was added by a PackWriter using SHA-384.
+	SHA384 string `yaml:"sha384,omitempty"`
+
+	// Digest holds the digest of the archive in the form
+	// "algorithm:hex", if it was added by a PackWriter using another
+	// algorithm.
+	Digest string `yaml:"digest,omitempty"`
+
+	// URL holds the URL of the charm or bundle the member was exported
+	// from, if known.
+	URL string `yaml:"url,omitempty"`
+}
+
+// packIndex is the layout of PackIndexFile.
+type packIndex struct {
+	Format  int          `yaml:"format"`
+	Members []PackMember `yaml:"members"`
+}
+
+// PackWriter writes a pack: a zip file holding several charm and bundle
+// archives, and an index describing them, so that sets of charms can
+// be moved around, for instance into air-gapped environments, as a
+// single file. The archives are stored uncompressed, so that a pack
+// member can be read in place by ReadPack.
+type PackWriter struct {
+	zipw    *zip.Writer
+	members []PackMember
+	names   map[string]bool
+
+	// algorithm holds the algorithm the digests of the members are
+	// computed with.
+	algorithm resource.HashAlgorithm
+
+	// signingKey holds the key used to sign the index, if any.
+	signingKey ed25519.PrivateKey
+}
+
+// NewPackWriter returns a PackWriter writing a pack to w, recording
+// SHA-384 digests of its members. The pack is only complete once Close
+// has been called.
+func NewPackWriter(w io.Writer) *PackWriter {
+	return &PackWriter{
+		zipw:      zip.NewWriter(w),
+		names:     make(map[string]bool),
+		algorithm: resource.SHA384,
+	}
+}
+
+// NewPackWriterUsing is like NewPackWriter but records digests computed
+// with the given algorithm, which must be registered with the resource
+// package.
+func NewPackWriterUsing(w io.Writer, algorithm resource.HashAlgorithm) (*PackWriter, error) {
+	if err := algorithm.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	pw := NewPackWriter(w)
+	pw.algorithm = algorithm
+	return pw, nil
+}
+
+// Add copies the archive read from r into the pack, as the member with
+// the given name and kind. The archive is streamed, so it is not held
+// in memory.
+func (pw *PackWriter) Add(name string, kind PackMemberKind, r io.Reader) error {
+	return pw.add(name, kind, "", r)
+}
+
+func (pw *PackWriter) add(name string, kind PackMemberKind, url string, r io.Reader) error {
+	if !IsValidName(name) {
+		return errors.NotValidf("pack member name %q", name)
+	}
+	if kind != PackCharm && kind != PackBundle {
+		return errors.NotValidf("pack member kind %q", kind)
+	}
+	if pw.names[name] {
+		return errors.AlreadyExistsf("pack member %q", name)
+	}
+	member := PackMember{
+		Name: name,
+		Kind: kind,
+		Path: fmt.Sprintf("%ss/%s.%s", kind, name, kind),
+		URL:  url,
+	}
+	w, err := pw.zipw.CreateHeader(&zip.FileHeader{
+		Name:   member.Path,
+		Method: zip.Store,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	h, err := resource.NewFingerprintHashUsing(pw.algorithm)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if member.Size, err = io.Copy(io.MultiWriter(w, h), r); err != nil {
+		return errors.Annotatef(err, "adding pack member %q", name)
+	}
+	if pw.algorithm == resource.SHA384 {
+		member.SHA384 = h.Fingerprint().Hex()
+	} else {
+		member.Digest = resource.QualifiedFingerprint{
+			Algorithm:   pw.algorithm,
+			Fingerprint: h.Fingerprint(),
+		}.String()
+	}
+	pw.names[name] = true
+	pw.members = append(pw.members, member)
+	return nil
+}
+
+// Close writes the index of the pack and finishes writing it. It does
+// not close the underlying writer.
+func (pw *PackWriter) Close() error {
+	data, err := yaml.Marshal(packIndex{
+		Format:  packFormat,
+		Members: pw.members,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := pw.writeFile(PackIndexFile, data); err != nil {
+		return errors.Trace(err)
+	}
+	if pw.signingKey != nil {
+		signature := ed25519.Sign(pw.signingKey, data)
+		if err := pw.writeFile(PackSignatureFile, signature); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return pw.zipw.Close()
+}
+
+func (pw *PackWriter) writeFile(name string, data []byte) error {
+	w, err := pw.zipw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Pack gives access to the members of a pack written by PackWriter.
+type Pack struct {
+	r       io.ReaderAt
+	members []PackMember
+	files   map[string]*zip.File
+
+	// index and signature hold the raw contents of PackIndexFile and
+	// PackSignatureFile.
+	index     []byte
+	signature []byte
+}
+
+// ReadPack reads the index of the pack held in r, which must hold
+// size bytes. The members themselves are only read when asked for.
+func ReadPack(r io.ReaderAt, size int64) (*Pack, error) {
+	zipr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading pack")
+	}
+	files := make(map[string]*zip.File)
+	for _, f := range zipr.File {
+		files[f.Name] = f
+	}
+	indexFile, ok := files[PackIndexFile]
+	if !ok {
+		return nil, errors.NotValidf("pack without %s", PackIndexFile)
+	}
+	data, err := readPackFile(indexFile)
+	if err != nil {
+		return nil, errors.Annotatef(err, "reading %s", PackIndexFile)
+	}
+	var index packIndex
+	if err := yaml.Unmarshal(data, &index); err != nil {
+		return nil, errors.Annotatef(err, "parsing %s", PackIndexFile)
+	}
+	if index.Format != packFormat {
+		return nil, errors.NotSupportedf("pack format %d", index.Format)
+	}
+	p := &Pack{
+		r:     r,
+		files: make(map[string]*zip.File),
+		index: data,
+	}
+	if signatureFile, ok := files[PackSignatureFile]; ok {
+		if p.signature, err = readPackFile(signatureFile); err != nil {
+			return nil, errors.Annotatef(err, "reading %s", PackSignatureFile)
+		}
+	}
+	for _, member := range index.Members {
+		f, ok := files[member.Path]
+		if !ok {
+			return nil, errors.NotFoundf("pack member %q archive %q", member.Name, member.Path)
+		}
+		if f.Method != zip.Store || int64(f.UncompressedSize64) != member.Size {
+			return nil, errors.NotValidf("pack member %q archive %q", member.Name, member.Path)
+		}
+		if _, ok := p.files[member.Name]; ok {
+			return nil, errors.NotValidf("pack with duplicate member %q", member.Name)
+		}
+		if _, err := member.newHash(); err != nil {
+			return nil, errors.Annotatef(err, "pack member %q", member.Name)
+		}
+		p.files[member.Name] = f
+		p.members = append(p.members, member)
+	}
+	return p, nil
+}
+
+func readPackFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(rc, MaxDocumentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > MaxDocumentSize {
+		return nil, &documentTooLargeError{MaxDocumentSize}
+	}
+	return data, nil
+}
+
+// VerifySignature checks that the index of the pack, and so the
+// digests of its members, was signed with the private key matching
+// key.
+func (p *Pack) VerifySignature(key ed25519.PublicKey) error {
+	if p.signature == nil {
+		return errors.NotValidf("pack without %s", PackSignatureFile)
+	}
+	if !ed25519.Verify(key, p.index, p.signature) {
+		return errors.NotValidf("pack signature")
+	}
+	return nil
+}
+
+// Members returns the members of the pack, in the order they were
+// added.
+func (p *Pack) Members() []PackMember {
+	return append([]PackMember(nil), p.members...)
+}
+
+// Member returns the named member of the pack.
+func (p *Pack) Member(name string) (PackMember, error) {
+	for _, member := range p.members {
+		if member.Name == name {
+			return member, nil
+		}
+	}
+	return PackMember{}, errors.NotFoundf("pack member %q", name)
+}
+
+// Open returns a reader for the archive of the named member. Reading
+// it to the end returns an error if the archive does not match the
+// digest recorded in the index.
+func (p *Pack) Open(name string) (io.ReadCloser, error) {
+	member, err := p.Member(name)
+	if err != nil {
+		return nil, err
+	}
+	h, err := member.newHash()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rc, err := p.files[name].Open()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &packMemberReader{
+		ReadCloser: rc,
+		member:     member,
+		hash:       h,
+	}, nil
+}
+
+// ReadCharm returns the charm archive held by the named member, after
+// checking its digest. The archive is read in place.
+func (p *Pack) ReadCharm(name string) (*CharmArchive, error) {
+	r, size, err := p.section(name, PackCharm)
+	if err != nil {
+		return nil, err
+	}
+	return ReadCharmArchiveFromReader(r, size)
+}
+
+// ReadBundle returns the bundle archive held by the named member,
+// after checking its digest. The archive is read in place.
+func (p *Pack) ReadBundle(name string) (*BundleArchive, error) {
+	r, size, err := p.section(name, PackBundle)
+	if err != nil {
+		return nil, err
+	}
+	return ReadBundleArchiveFromReader(r, size)
+}
+
+// ExtractTo writes the archive of the named member to the file at
+// path. The file is removed if the archive does not match its digest.
+func (p *Pack) ExtractTo(name, path string) (err error) {
+	rc, err := p.Open(name)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = errors.Trace(closeErr)
+		}
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+	_, err = io.Copy(f, rc)
+	return errors.Annotatef(err, "extracting pack member %q", name)
+}
+
+// section returns a reader for the archive of the named member, which
+// must be of the given kind, after checking its digest.
+func (p *Pack) section(name string, kind PackMemberKind) (*io.SectionReader, int64, error) {
+	member, err := p.Member(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	if member.Kind != kind {
+		return nil, 0, errors.NotValidf("pack member %q of kind %q as a %s", name, member.Kind, kind)
+	}
+	offset, err := p.files[name].DataOffset()
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	r := io.NewSectionReader(p.r, offset, member.Size)
+	h, err := member.newHash()
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	if err := member.verify(h); err != nil {
+		return nil, 0, err
+	}
+	return io.NewSectionReader(p.r, offset, member.Size), member.Size, nil
+}
+
+// newHash returns a hash computing digests with the algorithm the
+// digest of the member was recorded with.
+func (member PackMember) newHash() (hash.Hash, error) {
+	switch {
+	case member.Digest != "" && member.SHA384 != "":
+		return nil, errors.NotValidf("both sha384 and digest")
+	case member.Digest != "":
+		fp, err := resource.ParseQualifiedFingerprint(member.Digest)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return resource.NewFingerprintHashUsing(fp.Algorithm)
+	case member.SHA384 != "":
+		return resource.NewFingerprintHash(), nil
+	}
+	return nil, errors.NotValidf("missing digest")
+}
+
+// verify checks that h holds the digest recorded for the member.
+func (member PackMember) verify(h hash.Hash) error {
+	sum := hex.EncodeToString(h.Sum(nil))
+	expected := member.SHA384
+	if member.Digest != "" {
+		fp, err := resource.ParseQualifiedFingerprint(member.Digest)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		expected = fp.Hex()
+	}
+	if sum != expected {
+		return errors.NotValidf("pack member %q with digest %s", member.Name, sum)
+	}
+	return nil
+}
+
+// packMemberReader checks the digest of a pack member as it is read.
+type packMemberReader struct {
+	io.ReadCloser
+	member PackMember
+	hash   hash.Hash
+}
+
+func (r *packMemberReader) Read(buf []byte) (int, error) {
+	n, err := r.ReadCloser.Read(buf)
+	r.hash.Write(buf[:n])
+	if err == io.EOF {
+		if verifyErr := r.member.verify(r.hash); verifyErr != nil {
+			return n, verifyErr
+		}
+	}
+	return n, err
+}
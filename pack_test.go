@@ -0,0 +1,172 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+	"github.com/juju/charm/v8/resource"
+)
+
+type PackSuite struct{}
+
+var _ = gc.Suite(&PackSuite{})
+
+func (s *PackSuite) archive(c *gc.C, a ArchiverTo) []byte {
+	var buf bytes.Buffer
+	err := a.ArchiveTo(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func (s *PackSuite) writePack(c *gc.C) []byte {
+	var buf bytes.Buffer
+	pw := charm.NewPackWriter(&buf)
+	err := pw.Add("dummy", charm.PackCharm, bytes.NewReader(s.archive(c, readCharmDir(c, "dummy"))))
+	c.Assert(err, jc.ErrorIsNil)
+	err = pw.Add("wordpress-simple", charm.PackBundle, bytes.NewReader(s.archive(c, readBundleDir(c, "wordpress-simple"))))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pw.Close(), jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func (s *PackSuite) TestRoundTrip(c *gc.C) {
+	data := s.writePack(c)
+	pack, err := charm.ReadPack(bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+
+	members := pack.Members()
+	c.Assert(members, gc.HasLen, 2)
+	c.Check(members[0].Name, gc.Equals, "dummy")
+	c.Check(members[0].Kind, gc.Equals, charm.PackCharm)
+	c.Check(members[0].Path, gc.Equals, "charms/dummy.charm")
+	c.Check(members[0].SHA384, gc.HasLen, 96)
+	c.Check(members[0].Digest, gc.Equals, "")
+	c.Check(members[1].Name, gc.Equals, "wordpress-simple")
+	c.Check(members[1].Kind, gc.Equals, charm.PackBundle)
+
+	ch, err := pack.ReadCharm("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ch.Meta().Name, gc.Equals, "dummy")
+
+	bundle, err := pack.ReadBundle("wordpress-simple")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(bundle.Data().Applications, gc.HasLen, 2)
+
+	_, err = pack.ReadBundle("dummy")
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	_, err = pack.ReadCharm("missing")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *PackSuite) TestOpenAndExtract(c *gc.C) {
+	data := s.writePack(c)
+	pack, err := charm.ReadPack(bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+
+	rc, err := pack.Open("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	archive, err := ioutil.ReadAll(rc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rc.Close(), jc.ErrorIsNil)
+	member, err := pack.Member("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(int64(len(archive)), gc.Equals, member.Size)
+
+	path := filepath.Join(c.MkDir(), "dummy.charm")
+	err = pack.ExtractTo("dummy", path)
+	c.Assert(err, jc.ErrorIsNil)
+	ch, err := charm.ReadCharmArchive(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ch.Meta().Name, gc.Equals, "dummy")
+}
+
+func (s *PackSuite) TestCorruptMember(c *gc.C) {
+	data := s.writePack(c)
+	pack, err := charm.ReadPack(bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	member, err := pack.Member("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Flip a byte of the stored charm archive, which starts with the
+	// local file header signature.
+	offset := bytes.Index(data, []byte("PK\x03\x04"))
+	offset = bytes.Index(data[offset+4:], []byte("PK\x03\x04")) + offset + 4
+	corrupt := append([]byte(nil), data...)
+	corrupt[offset+int(member.Size)/2] ^= 0xff
+	pack, err = charm.ReadPack(bytes.NewReader(corrupt), int64(len(corrupt)))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = pack.ReadCharm("dummy")
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `pack member "dummy" with digest [0-9a-f]+ not valid`)
+
+	path := filepath.Join(c.MkDir(), "dummy.charm")
+	err = pack.ExtractTo("dummy", path)
+	c.Check(err, gc.ErrorMatches, `extracting pack member "dummy": .*`)
+	_, err = os.Stat(path)
+	c.Check(os.IsNotExist(err), jc.IsTrue)
+}
+
+func (s *PackSuite) TestHashAlgorithm(c *gc.C) {
+	var buf bytes.Buffer
+	pw, err := charm.NewPackWriterUsing(&buf, resource.SHA512)
+	c.Assert(err, jc.ErrorIsNil)
+	err = pw.Add("dummy", charm.PackCharm, bytes.NewReader(s.archive(c, readCharmDir(c, "dummy"))))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pw.Close(), jc.ErrorIsNil)
+
+	pack, err := charm.ReadPack(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	c.Assert(err, jc.ErrorIsNil)
+	member, err := pack.Member("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(member.SHA384, gc.Equals, "")
+	fp, err := resource.ParseQualifiedFingerprint(member.Digest)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fp.Algorithm, gc.Equals, resource.SHA512)
+
+	ch, err := pack.ReadCharm("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ch.Meta().Name, gc.Equals, "dummy")
+	rc, err := pack.Open("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = ioutil.ReadAll(rc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rc.Close(), jc.ErrorIsNil)
+
+	_, err = charm.NewPackWriterUsing(&buf, "md5")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *PackSuite) TestAddErrors(c *gc.C) {
+	pw := charm.NewPackWriter(ioutil.Discard)
+	err := pw.Add("Bad Name", charm.PackCharm, strings.NewReader(""))
+	c.Check(err, gc.ErrorMatches, `pack member name "Bad Name" not valid`)
+	err = pw.Add("a", "resource", strings.NewReader(""))
+	c.Check(err, gc.ErrorMatches, `pack member kind "resource" not valid`)
+	err = pw.Add("a", charm.PackCharm, strings.NewReader(""))
+	c.Assert(err, jc.ErrorIsNil)
+	err = pw.Add("a", charm.PackBundle, strings.NewReader(""))
+	c.Check(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *PackSuite) TestReadPackWithoutIndex(c *gc.C) {
+	var buf bytes.Buffer
+	zipw := zip.NewWriter(&buf)
+	_, err := zipw.Create("charms/a.charm")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zipw.Close(), jc.ErrorIsNil)
+	_, err = charm.ReadPack(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	c.Assert(err, gc.ErrorMatches, `pack without pack.yaml not valid`)
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"sync"
+)
+
+// ParseFailureHook is called with each document that one of the
+// document readers of this package read but failed to parse. The file
+// names the kind of document, as in MetadataFile or "bundle.yaml"; data
+// holds the document, with any byte order mark removed and line
+// endings normalized, which fails in the same way; and err holds the
+// error returned by the reader. The hook must not modify data.
+type ParseFailureHook func(file string, data []byte, err error)
+
+var (
+	parseFailureHookMutex sync.RWMutex
+	parseFailureHook      ParseFailureHook
+)
+
+// SetParseFailureHook sets the function called whenever ReadMeta,
+// ReadMetaWithOptions, ReadMetaRaw, ReadConfig, ReadActionsYaml, ReadMetrics,
+// ReadLXDProfile, ReadChangelog or one of the bundle readers fails to
+// parse or validate a document, including one read from a charm or
+// bundle archive or directory, and returns the previous hook. Documents
+// that cannot be read at all, for instance because they are too
+// large, are not reported. A nil hook disables reporting, which is
+// the default.
+//
+// Hosting services may use it to collect failing inputs, with the
+// consent of their users, to grow regression corpora. The hook is
+// called synchronously by the failing reader, so it should return
+// quickly.
+func SetParseFailureHook(hook ParseFailureHook) ParseFailureHook {
+	parseFailureHookMutex.Lock()
+	defer parseFailureHookMutex.Unlock()
+	previous := parseFailureHook
+	parseFailureHook = hook
+	return previous
+}
+
+// reportParseFailure calls the parse failure hook, if one is set, if
+// err is not nil.
+func reportParseFailure(file string, data []byte, err error) {
+	if err == nil {
+		return
+	}
+	parseFailureHookMutex.RLock()
+	hook := parseFailureHook
+	parseFailureHookMutex.RUnlock()
+	if hook != nil {
+		hook(file, data, err)
+	}
+}
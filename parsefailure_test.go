@@ -0,0 +1,134 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type ParseFailureSuite struct {
+	failures []parseFailure
+}
+
+var _ = gc.Suite(&ParseFailureSuite{})
+
+type parseFailure struct {
+	file string
+	data string
+	err  error
+}
+
+func (s *ParseFailureSuite) SetUpTest(c *gc.C) {
+	s.failures = nil
+	previous := charm.SetParseFailureHook(func(file string, data []byte, err error) {
+		s.failures = append(s.failures, parseFailure{file, string(data), err})
+	})
+	c.Assert(previous, gc.IsNil)
+}
+
+func (s *ParseFailureSuite) TearDownTest(c *gc.C) {
+	charm.SetParseFailureHook(nil)
+}
+
+func (s *ParseFailureSuite) TestReaders(c *gc.C) {
+	for i, test := range []struct {
+		file string
+		read func(string) error
+	}{{
+		file: charm.MetadataFile,
+		read: func(data string) error {
+			_, err := charm.ReadMeta(strings.NewReader(data))
+			return err
+		},
+	}, {
+		file: charm.MetadataFile,
+		read: func(data string) error {
+			_, err := charm.ReadMetaStrict(strings.NewReader(data))
+			return err
+		},
+	}, {
+		file: charm.ConfigFile,
+		read: func(data string) error {
+			_, err := charm.ReadConfig(strings.NewReader(data))
+			return err
+		},
+	}, {
+		file: charm.ActionsFile,
+		read: func(data string) error {
+			_, err := charm.ReadActionsYaml(strings.NewReader(data))
+			return err
+		},
+	}, {
+		file: charm.MetricsFile,
+		read: func(data string) error {
+			_, err := charm.ReadMetrics(strings.NewReader(data))
+			return err
+		},
+	}, {
+		file: charm.LXDProfileFile,
+		read: func(data string) error {
+			_, err := charm.ReadLXDProfile(strings.NewReader(data))
+			return err
+		},
+	}, {
+		file: charm.ChangelogFile,
+		read: func(data string) error {
+			_, err := charm.ReadChangelog(strings.NewReader(data))
+			return err
+		},
+	}, {
+		file: "bundle.yaml",
+		read: func(data string) error {
+			_, err := charm.ReadBundleData(strings.NewReader(data))
+			return err
+		},
+	}} {
+		c.Logf("test %d: %s", i, test.file)
+		s.failures = nil
+		err := test.read("a: [")
+		c.Assert(err, gc.NotNil)
+		c.Assert(s.failures, gc.HasLen, 1)
+		c.Check(s.failures[0].file, gc.Equals, test.file)
+		c.Check(s.failures[0].data, gc.Equals, "a: [")
+		c.Check(s.failures[0].err, gc.Equals, err)
+	}
+}
+
+func (s *ParseFailureSuite) TestValidationFailure(c *gc.C) {
+	data := "name: a\nsummary: b\ndescription: c\nsubordinate: true\n"
+	_, err := charm.ReadMeta(strings.NewReader(data))
+	c.Assert(err, gc.NotNil)
+	c.Assert(s.failures, jc.DeepEquals, []parseFailure{{charm.MetadataFile, data, err}})
+}
+
+func (s *ParseFailureSuite) TestSuccessNotReported(c *gc.C) {
+	_, err := charm.ReadMeta(strings.NewReader(dummyMetadata))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.failures, gc.HasLen, 0)
+}
+
+func (s *ParseFailureSuite) TestCharmDir(c *gc.C) {
+	dir := cloneDir(c, charmDirPath(c, "dummy"))
+	err := ioutil.WriteFile(filepath.Join(dir, charm.ConfigFile), []byte("options: ["), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = charm.ReadCharmDir(dir)
+	c.Assert(err, gc.NotNil)
+	c.Assert(s.failures, gc.HasLen, 1)
+	c.Check(s.failures[0].file, gc.Equals, charm.ConfigFile)
+	c.Check(s.failures[0].data, gc.Equals, "options: [")
+}
+
+func (s *ParseFailureSuite) TestSetHookReturnsPrevious(c *gc.C) {
+	hook := func(string, []byte, error) {}
+	previous := charm.SetParseFailureHook(hook)
+	c.Assert(previous, gc.NotNil)
+	c.Assert(charm.SetParseFailureHook(previous), gc.NotNil)
+}
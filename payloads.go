@@ -10,10 +10,12 @@ import (
 	"github.com/juju/names/v4"
 )
 
+var payloadClassFields = schema.Fields{
+	"type": schema.String(),
+}
+
 var payloadClassSchema = schema.FieldMap(
-	schema.Fields{
-		"type": schema.String(),
-	},
+	payloadClassFields,
 	schema.Defaults{},
 )
 
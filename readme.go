@@ -0,0 +1,214 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// ReadmeSummary holds the text extracted from the start of a charm's
+// README by ParseReadme. It may be used in place of the summary and
+// description of charms whose metadata leaves them thin.
+type ReadmeSummary struct {
+	// Heading holds the text of the first heading.
+	Heading string
+
+	// Paragraph holds the text of the first paragraph, with its lines
+	// joined by single spaces.
+	Paragraph string
+}
+
+// readmeFiles holds the names of the README files looked for by
+// CharmDir.ReadmeSummary and CharmArchive.ReadmeSummary, in order of
+// preference.
+var readmeFiles = []string{"README.md", "README.rst", "README.txt", "README"}
+
+// ReadmeSummary returns the summary of the first README file found in
+// the charm directory. It returns a NotFound error if there is none.
+func (dir *CharmDir) ReadmeSummary() (ReadmeSummary, error) {
+	for _, name := range readmeFiles {
+		data, err := ioutil.ReadFile(dir.join(name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return ReadmeSummary{}, errors.Annotatef(err, "issue reading %q file", name)
+		}
+		return ParseReadme(name, data), nil
+	}
+	return ReadmeSummary{}, errors.NotFoundf("README file")
+}
+
+// ReadmeSummary returns the summary of the first README file found in
+// the charm archive. It returns a NotFound error if there is none.
+func (a *CharmArchive) ReadmeSummary() (ReadmeSummary, error) {
+	zipr, err := a.zopen.openZip()
+	if err != nil {
+		return ReadmeSummary{}, errors.Trace(err)
+	}
+	defer zipr.Close()
+	for _, name := range readmeFiles {
+		reader, err := zipOpenFile(zipr, name)
+		if _, ok := err.(*noCharmArchiveFile); ok {
+			continue
+		}
+		if err != nil {
+			return ReadmeSummary{}, errors.Annotatef(err, "issue reading %q file", name)
+		}
+		data, err := ioutil.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return ReadmeSummary{}, errors.Annotatef(err, "issue reading %q file", name)
+		}
+		return ParseReadme(name, data), nil
+	}
+	return ReadmeSummary{}, errors.NotFoundf("README file")
+}
+
+// ParseReadme extracts the first heading and the first paragraph of a
+// README. Files whose name ends in ".rst" are parsed as
+// reStructuredText, and all others as Markdown. Front matter, badges,
+// HTML, lists, code blocks and directives are skipped.
+func ParseReadme(filename string, data []byte) ReadmeSummary {
+	data = bytes.Replace(data, []byte("\r\n"), []byte("\n"), -1)
+	lines := strings.Split(string(data), "\n")
+	if strings.EqualFold(filepath.Ext(filename), ".rst") {
+		return parseReST(lines)
+	}
+	return parseMarkdown(lines)
+}
+
+var (
+	mdATXHeading      = regexp.MustCompile(`^ {0,3}#{1,6}(?:\s+(.*?))?(?:\s+#+)?\s*$`)
+	mdSetextUnderline = regexp.MustCompile(`^ {0,3}(=+|-+)\s*$`)
+	mdBadge           = regexp.MustCompile(`^(\s*\[?!\[[^\]]*\]\([^)]*\)(\]\([^)]*\))?)+\s*$`)
+	mdNonParagraph    = regexp.MustCompile(`^(\s*([-*+>|<]|[0-9]+[.)])(\s|$)|\s*<|\s{4}|\t)`)
+	rstNonParagraph   = regexp.MustCompile(`^(\.\. |:[^:]+:|\s|[-*+] |[0-9]+[.)] )`)
+)
+
+func parseMarkdown(lines []string) ReadmeSummary {
+	var summary ReadmeSummary
+	var paragraph []string
+	inFence, inFrontMatter := false, false
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case i == 0 && trimmed == "---":
+			inFrontMatter = true
+			continue
+		case inFrontMatter:
+			inFrontMatter = trimmed != "---" && trimmed != "..."
+			continue
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			inFence = !inFence
+			continue
+		case inFence:
+			continue
+		}
+		if trimmed == "" {
+			if len(paragraph) > 0 && summary.Paragraph == "" {
+				summary.Paragraph = strings.Join(paragraph, " ")
+			}
+			paragraph = nil
+			if summary.Heading != "" && summary.Paragraph != "" {
+				break
+			}
+			continue
+		}
+		if m := mdATXHeading.FindStringSubmatch(line); m != nil {
+			if summary.Heading == "" {
+				summary.Heading = m[1]
+			}
+			paragraph = nil
+			continue
+		}
+		if len(paragraph) == 0 && i+1 < len(lines) && mdSetextUnderline.MatchString(lines[i+1]) && !mdNonParagraph.MatchString(line) {
+			if summary.Heading == "" {
+				summary.Heading = trimmed
+			}
+			i++
+			continue
+		}
+		if len(paragraph) == 0 && (mdBadge.MatchString(line) || mdNonParagraph.MatchString(line) || mdSetextUnderline.MatchString(line)) {
+			continue
+		}
+		paragraph = append(paragraph, trimmed)
+	}
+	if len(paragraph) > 0 && summary.Paragraph == "" {
+		summary.Paragraph = strings.Join(paragraph, " ")
+	}
+	return summary
+}
+
+// isReSTAdornment reports whether line is a section title underline
+// or overline: a repeated punctuation character.
+func isReSTAdornment(line string) bool {
+	line = strings.TrimRight(line, " \t")
+	if len(line) < 2 || !strings.ContainsRune("=-`:'\"~^_*+#<>.", rune(line[0])) {
+		return false
+	}
+	return strings.Count(line, line[:1]) == len(line)
+}
+
+func parseReST(lines []string) ReadmeSummary {
+	var summary ReadmeSummary
+	var paragraph []string
+	inDirective := false
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			if len(paragraph) > 0 && summary.Paragraph == "" {
+				summary.Paragraph = strings.Join(paragraph, " ")
+			}
+			paragraph = nil
+			if summary.Heading != "" && summary.Paragraph != "" {
+				break
+			}
+			continue
+		}
+		if inDirective {
+			// The body of a directive is indented below it.
+			if line[0] == ' ' || line[0] == '\t' {
+				continue
+			}
+			inDirective = false
+		}
+		if len(paragraph) == 0 && isReSTAdornment(line) {
+			// An overline: the title follows, and is underlined.
+			if i+2 < len(lines) && isReSTAdornment(lines[i+2]) {
+				if summary.Heading == "" {
+					summary.Heading = strings.TrimSpace(lines[i+1])
+				}
+				i += 2
+			}
+			continue
+		}
+		if len(paragraph) == 0 && i+1 < len(lines) && isReSTAdornment(lines[i+1]) &&
+			len(strings.TrimSpace(lines[i+1])) >= len(trimmed) {
+			if summary.Heading == "" {
+				summary.Heading = trimmed
+			}
+			i++
+			continue
+		}
+		if len(paragraph) == 0 && rstNonParagraph.MatchString(line) {
+			inDirective = strings.HasPrefix(line, ".. ")
+			continue
+		}
+		paragraph = append(paragraph, trimmed)
+	}
+	if len(paragraph) > 0 && summary.Paragraph == "" {
+		summary.Paragraph = strings.Join(paragraph, " ")
+	}
+	return summary
+}
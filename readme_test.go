@@ -0,0 +1,165 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type ReadmeSuite struct{}
+
+var _ = gc.Suite(&ReadmeSuite{})
+
+var parseReadmeTests = []struct {
+	about    string
+	filename string
+	readme   string
+	expect   charm.ReadmeSummary
+}{{
+	about:    "markdown with atx heading",
+	filename: "README.md",
+	readme: `
+# Wordpress #
+
+Wordpress is a blogging
+platform.
+
+It is written in PHP.
+`,
+	expect: charm.ReadmeSummary{
+		Heading:   "Wordpress",
+		Paragraph: "Wordpress is a blogging platform.",
+	},
+}, {
+	about:    "markdown with setext heading, front matter and badges",
+	filename: "README.md",
+	readme: `---
+title: ignored
+---
+[![Build](https://example.com/badge.svg)](https://example.com/build) ![Docs](https://example.com/docs.svg)
+
+MySQL
+=====
+
+<!-- A comment -->
+* a list item
+
+` + "```" + `
+code block
+` + "```" + `
+
+The MySQL charm deploys
+a database.
+`,
+	expect: charm.ReadmeSummary{
+		Heading:   "MySQL",
+		Paragraph: "The MySQL charm deploys a database.",
+	},
+}, {
+	about:    "markdown without heading",
+	filename: "README",
+	readme:   "Just some text\r\nover two lines.\r\n",
+	expect: charm.ReadmeSummary{
+		Paragraph: "Just some text over two lines.",
+	},
+}, {
+	about:    "restructured text with overline",
+	filename: "README.rst",
+	readme: `
+=========
+ Postgres
+=========
+
+.. image:: https://example.com/badge.svg
+   :target: https://example.com
+
+:Author: Someone
+
+Postgres is a relational
+database.
+
+Usage
+-----
+`,
+	expect: charm.ReadmeSummary{
+		Heading:   "Postgres",
+		Paragraph: "Postgres is a relational database.",
+	},
+}, {
+	about:    "restructured text with underline",
+	filename: "readme.RST",
+	readme: `Redis
+~~~~~
+
+Redis is a key-value store.
+`,
+	expect: charm.ReadmeSummary{
+		Heading:   "Redis",
+		Paragraph: "Redis is a key-value store.",
+	},
+}, {
+	about:    "empty",
+	filename: "README.md",
+	readme:   "",
+}}
+
+func (s *ReadmeSuite) TestParseReadme(c *gc.C) {
+	for i, test := range parseReadmeTests {
+		c.Logf("test %d: %s", i, test.about)
+		summary := charm.ParseReadme(test.filename, []byte(test.readme))
+		c.Check(summary, jc.DeepEquals, test.expect)
+	}
+}
+
+func (s *ReadmeSuite) TestCharmDirReadmeSummary(c *gc.C) {
+	path := cloneDir(c, charmDirPath(c, "dummy"))
+	dir, err := charm.ReadCharmDir(path)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = dir.ReadmeSummary()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = ioutil.WriteFile(filepath.Join(path, "README.rst"), []byte("Dummy\n=====\n\nA dummy charm.\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	summary, err := dir.ReadmeSummary()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(summary, jc.DeepEquals, charm.ReadmeSummary{
+		Heading:   "Dummy",
+		Paragraph: "A dummy charm.",
+	})
+}
+
+func (s *ReadmeSuite) TestCharmArchiveReadmeSummary(c *gc.C) {
+	path := cloneDir(c, charmDirPath(c, "dummy"))
+	dir, err := charm.ReadCharmDir(path)
+	c.Assert(err, jc.ErrorIsNil)
+	var buf bytes.Buffer
+	err = dir.ArchiveTo(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+	archive, err := charm.ReadCharmArchiveBytes(buf.Bytes())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = archive.ReadmeSummary()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = ioutil.WriteFile(filepath.Join(path, "README.md"), []byte("# Dummy\n\nA dummy charm.\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	buf.Reset()
+	err = dir.ArchiveTo(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+	archive, err = charm.ReadCharmArchiveBytes(buf.Bytes())
+	c.Assert(err, jc.ErrorIsNil)
+	summary, err := archive.ReadmeSummary()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(summary, jc.DeepEquals, charm.ReadmeSummary{
+		Heading:   "Dummy",
+		Paragraph: "A dummy charm.",
+	})
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// ReadMetaOptions holds options for ReadMetaWithOptions.
+type ReadMetaOptions struct {
+	// MaxSize holds the largest number of bytes to read. If it is
+	// zero, MaxDocumentSize is used; if it is negative, there is no
+	// limit.
+	MaxSize int64
+
+	// Strict causes fields that are not recognized to be rejected,
+	// as described for ReadMetaStrict.
+	Strict bool
+
+	// Warn, if not nil, is called with a warning for each field that
+	// is not recognized, when Strict is false.
+	Warn func(warning string)
+}
+
+// ReadMetaWithOptions is like ReadMeta, but reads the metadata as
+// configured by opts.
+func ReadMetaWithOptions(r io.Reader, opts ReadMetaOptions) (_ *Meta, err error) {
+	limit := opts.MaxSize
+	if limit == 0 {
+		limit = MaxDocumentSize
+	}
+	data, err := readYAMLInputLimit(r, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		reportParseFailure(MetadataFile, data, err)
+	}()
+	if opts.Strict || opts.Warn != nil {
+		// Look for unknown fields first, as a misspelt field is
+		// the most likely cause of any error found when parsing
+		// the metadata.
+		unknown, err := unknownMetaFields(data)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Strict && len(unknown) > 0 {
+			return nil, locateMetaError(data, fieldErrorf(unknown[0], ErrUnknownField,
+				"metadata: unknown field(s): %s", strings.Join(unknown, ", ")))
+		}
+		for _, field := range unknown {
+			opts.Warn(fmt.Sprintf("unknown field %q", field))
+		}
+	}
+	var meta Meta
+	if err := yaml.Unmarshal(data, &meta); err != nil {
+		return nil, locateMetaError(data, err)
+	}
+	return &meta, nil
+}
+
+// ReadMetaBytes is like ReadMetaWithOptions, but reads the metadata
+// held in data.
+func ReadMetaBytes(data []byte, opts ReadMetaOptions) (*Meta, error) {
+	return ReadMetaWithOptions(bytes.NewReader(data), opts)
+}
+
+// ReadMetaFile is like ReadMetaWithOptions, but reads the metadata from
+// the file at path. Errors are prefixed with the path; a *FieldError is
+// returned as such, with its File field set, and the cause of other
+// errors, such as one satisfying os.IsNotExist, is preserved.
+func ReadMetaFile(path string, opts ReadMetaOptions) (*Meta, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Annotate(err, path)
+	}
+	defer f.Close()
+	meta, err := ReadMetaWithOptions(f, opts)
+	if fieldErr, ok := err.(*FieldError); ok {
+		fieldErr.File = path
+		return nil, fieldErr
+	}
+	if err != nil {
+		return nil, errors.Annotate(err, path)
+	}
+	return meta, nil
+}
+
+// ReadMetaStrict is like ReadMeta, but returns an error if the metadata
+// holds fields that are not recognized, at the top level or within
+// any section, so that typos such as "provids" are caught. ReadMeta
+// ignores such fields so that charms written for newer versions of
+// Juju can still be read; ReadMetaStrict is meant for tools used while
+// writing and packing charms.
+func ReadMetaStrict(r io.Reader) (*Meta, error) {
+	return ReadMetaWithOptions(r, ReadMetaOptions{Strict: true})
+}
+
+// informationalMetaFields holds top level fields that ReadMeta does
+// not interpret, but that charms commonly declare for the benefit of
+// their readers and that ReadMetaStrict therefore accepts, as it does
+// the fields added by RegisterMetaField.
+var informationalMetaFields = []string{"maintainer", "maintainers"}
+
+// unknownMetaFields returns the paths of the fields of the
+// metadata.yaml document held in data that are not recognized.
+func unknownMetaFields(data []byte) ([]string, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The JSON schema mirrors the checkers used by ReadMeta, and
+	// describes the fields that may appear in each section.
+	s := metaJSONSchema()
+	properties := s["properties"].(jsonObject)
+	for _, name := range informationalMetaFields {
+		properties[name] = jsonObject{}
+	}
+	for _, name := range RegisteredMetaFields() {
+		properties[name] = jsonObject{}
+	}
+	unknown, err := unknownFields(s, raw, "")
+	return unknown, errors.Annotate(err, "metadata")
+}
+
+// unknownFields returns the paths of the fields found in v that are not
+// described by the JSON schema s.
+func unknownFields(s jsonObject, v interface{}, path string) ([]string, error) {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		s, err := schemaOfType(s, "object", path)
+		if err != nil {
+			return nil, err
+		}
+		var unknown []string
+		for _, key := range sortedKeys(v) {
+			keyPath := fmt.Sprintf("%s%v", path, key)
+			var field jsonObject
+			if properties, ok := s["properties"].(jsonObject); ok {
+				if field, ok = properties[fmt.Sprint(key)].(jsonObject); !ok {
+					unknown = append(unknown, keyPath)
+					continue
+				}
+			} else if field, ok = s["additionalProperties"].(jsonObject); !ok {
+				continue
+			}
+			fieldUnknown, err := unknownFields(field, v[key], keyPath+".")
+			if err != nil {
+				return nil, err
+			}
+			unknown = append(unknown, fieldUnknown...)
+		}
+		return unknown, nil
+	case []interface{}:
+		s, err := schemaOfType(s, "array", path)
+		if err != nil {
+			return nil, err
+		}
+		items, _ := s["items"].(jsonObject)
+		var unknown []string
+		for i, item := range v {
+			itemUnknown, err := unknownFields(items, item, fmt.Sprintf("%s[%d].", strings.TrimSuffix(path, "."), i))
+			if err != nil {
+				return nil, err
+			}
+			unknown = append(unknown, itemUnknown...)
+		}
+		return unknown, nil
+	}
+	return nil, nil
+}
+
+// schemaOfType returns the alternative of the JSON schema s that
+// describes values of the given type, or s itself if it has no
+// alternatives. It returns an error if none of the alternatives
+// describe such values.
+func schemaOfType(s jsonObject, t, path string) (jsonObject, error) {
+	alternatives, ok := s["oneOf"].([]interface{})
+	if !ok {
+		return s, nil
+	}
+	for _, alternative := range alternatives {
+		if alternative, ok := alternative.(jsonObject); ok && alternative["type"] == t {
+			return alternative, nil
+		}
+	}
+	return nil, errors.Errorf("%s: unexpected %s", strings.TrimSuffix(path, "."), t)
+}
+
+func sortedKeys(m map[interface{}]interface{}) []interface{} {
+	keys := make([]interface{}, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	return keys
+}
@@ -0,0 +1,236 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type ReadMetaSuite struct{}
+
+var _ = gc.Suite(&ReadMetaSuite{})
+
+func (s *ReadMetaSuite) TestRepoCharms(c *gc.C) {
+	// All the valid charms in the test repository only use known
+	// fields.
+	paths, err := filepath.Glob("internal/test-charm-repo/quantal/*/metadata.yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(paths, gc.Not(gc.HasLen), 0)
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		c.Assert(err, jc.ErrorIsNil)
+		if _, err := charm.ReadMeta(strings.NewReader(string(data))); err != nil {
+			continue
+		}
+		c.Logf("%s", path)
+		_, err = charm.ReadMetaBytes(data, charm.ReadMetaOptions{Strict: true})
+		c.Check(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *ReadMetaSuite) TestReadMetaFile(c *gc.C) {
+	dir := c.MkDir()
+	path := filepath.Join(dir, "metadata.yaml")
+	err := ioutil.WriteFile(path, []byte("name: a\nsummary: b\ndescription: c\nprovids: {}\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	meta, err := charm.ReadMetaFile(path, charm.ReadMetaOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.Name, gc.Equals, "a")
+
+	_, err = charm.ReadMetaFile(path, charm.ReadMetaOptions{Strict: true})
+	c.Assert(err, gc.ErrorMatches, regexp.QuoteMeta(path)+`: metadata: unknown field\(s\): provids`)
+	fieldErr, ok := err.(*charm.FieldError)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(fieldErr.File, gc.Equals, path)
+	c.Assert(fieldErr.Position.Line, gc.Equals, 4)
+
+	_, err = charm.ReadMetaFile(path, charm.ReadMetaOptions{MaxSize: 10})
+	c.Assert(err, gc.ErrorMatches, regexp.QuoteMeta(path)+`: .*`)
+
+	_, err = charm.ReadMetaFile(filepath.Join(dir, "missing.yaml"), charm.ReadMetaOptions{})
+	c.Assert(err, gc.ErrorMatches, regexp.QuoteMeta(filepath.Join(dir, "missing.yaml"))+`: .*`)
+	c.Assert(errors.Cause(err), jc.Satisfies, os.IsNotExist)
+}
+
+var readMetaStrictTests = []struct {
+	about string
+	yaml  string
+	err   string
+}{{
+	about: "all known fields",
+	yaml: `
+provides:
+  website:
+    interface: http
+    limit: 1
+    ports: [80]
+extra-bindings:
+  admin:
+storage:
+  data:
+    type: filesystem
+    multiple:
+      range: 1-2
+resources:
+  image:
+    type: oci-image
+    description: An image.
+website: https://example.com
+maintainer: Someone <someone@example.com>
+`,
+}, {
+	about: "unknown top level field",
+	yaml:  "provids:\n  website: http\n",
+	err:   `metadata: unknown field\(s\): provids`,
+}, {
+	about: "unknown nested fields",
+	yaml: `
+provides:
+  website:
+    interface: http
+    scpoe: container
+storage:
+  data:
+    type: filesystem
+    sise: 1G
+`,
+	err: `metadata: unknown field\(s\): provides.website.scpoe, storage.data.sise`,
+}, {
+	about: "unknown field in list",
+	yaml: `
+platforms: [kubernetes]
+systems:
+  - os: ubuntu
+    channel: 20.04/stable
+    chanel: 20.04/stable
+`,
+	err: `metadata: unknown field\(s\): systems\[0\].chanel`,
+}}
+
+func (s *ReadMetaSuite) TestUnknownFieldsReportedFirst(c *gc.C) {
+	// The misspelt field also leaves out a required one, but it is the
+	// unknown field that is reported.
+	_, err := charm.ReadMetaStrict(strings.NewReader(`
+name: a
+summary: b
+description: c
+provides:
+  website:
+    interfce: http
+`))
+	c.Assert(err, gc.ErrorMatches, `metadata: unknown field\(s\): provides.website.interfce`)
+}
+
+func (s *ReadMetaSuite) TestUnexpectedType(c *gc.C) {
+	_, err := charm.ReadMetaStrict(strings.NewReader(`
+name: a
+summary: b
+description: c
+provides:
+  website: [http]
+`))
+	c.Assert(err, gc.ErrorMatches, `metadata: provides.website: unexpected array`)
+}
+
+func (s *ReadMetaSuite) TestReadMetaStrict(c *gc.C) {
+	for i, test := range readMetaStrictTests {
+		c.Logf("test %d: %s", i, test.about)
+		yaml := "name: a\nsummary: b\ndescription: c\n" + strings.TrimPrefix(test.yaml, "\n")
+		_, err := charm.ReadMeta(strings.NewReader(yaml))
+		c.Check(err, jc.ErrorIsNil)
+		_, err = charm.ReadMetaStrict(strings.NewReader(yaml))
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *ReadMetaSuite) TestMaxSize(c *gc.C) {
+	restore := charm.MaxDocumentSize
+	defer func() { charm.MaxDocumentSize = restore }()
+	charm.MaxDocumentSize = 8
+
+	// A zero MaxSize uses MaxDocumentSize.
+	_, err := charm.ReadMetaWithOptions(strings.NewReader(dummyMetadata), charm.ReadMetaOptions{})
+	c.Assert(charm.IsDocumentTooLargeError(err), jc.IsTrue)
+
+	_, err = charm.ReadMetaWithOptions(strings.NewReader(dummyMetadata), charm.ReadMetaOptions{
+		MaxSize: int64(len(dummyMetadata) - 1),
+	})
+	c.Assert(err, gc.ErrorMatches, `document exceeds maximum size of \d+ bytes`)
+	c.Assert(charm.IsDocumentTooLargeError(err), jc.IsTrue)
+
+	meta, err := charm.ReadMetaWithOptions(strings.NewReader(dummyMetadata), charm.ReadMetaOptions{
+		MaxSize: int64(len(dummyMetadata)),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.Name, gc.Equals, "a")
+
+	// A negative MaxSize disables the limit.
+	meta, err = charm.ReadMetaWithOptions(strings.NewReader(dummyMetadata), charm.ReadMetaOptions{
+		MaxSize: -1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.Name, gc.Equals, "a")
+}
+
+const unknownFieldsMetadata = `
+name: a
+summary: b
+description: c
+provids:
+  website: http
+storage:
+  data:
+    type: filesystem
+    sise: 1G
+`
+
+func (s *ReadMetaSuite) TestStrict(c *gc.C) {
+	_, err := charm.ReadMetaWithOptions(strings.NewReader(unknownFieldsMetadata), charm.ReadMetaOptions{
+		Strict: true,
+	})
+	c.Assert(err, gc.ErrorMatches, `metadata: unknown field\(s\): provids, storage.data.sise`)
+
+	meta, err := charm.ReadMetaWithOptions(strings.NewReader(unknownFieldsMetadata), charm.ReadMetaOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.Name, gc.Equals, "a")
+}
+
+func (s *ReadMetaSuite) TestWarn(c *gc.C) {
+	var warnings []string
+	meta, err := charm.ReadMetaWithOptions(strings.NewReader(unknownFieldsMetadata), charm.ReadMetaOptions{
+		Warn: func(warning string) {
+			warnings = append(warnings, warning)
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.Name, gc.Equals, "a")
+	c.Assert(warnings, jc.DeepEquals, []string{
+		`unknown field "provids"`,
+		`unknown field "storage.data.sise"`,
+	})
+
+	warnings = nil
+	_, err = charm.ReadMetaWithOptions(strings.NewReader(dummyMetadata), charm.ReadMetaOptions{
+		Warn: func(warning string) {
+			warnings = append(warnings, warning)
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(warnings, gc.HasLen, 0)
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/juju/errors"
+)
+
+// RelatedEndpoint describes a relation of an application to another
+// application.
+type RelatedEndpoint struct {
+	// Endpoint holds the name of the endpoint of the application
+	// through which it is related.
+	Endpoint string
+
+	// Application holds the name of the related application.
+	Application string
+}
+
+// RelationLimitError is returned by CheckRelationLimits when an
+// endpoint would take part in more relations than its limit allows.
+type RelationLimitError struct {
+	// Endpoint holds the name of the endpoint.
+	Endpoint string
+
+	// Limit holds the limit of the endpoint.
+	Limit int
+
+	// Relations holds the number of relations the endpoint would take
+	// part in.
+	Relations int
+}
+
+// Error implements error.
+func (e *RelationLimitError) Error() string {
+	return fmt.Sprintf("endpoint %q would take part in %d relations, exceeding its limit of %d", e.Endpoint, e.Relations, e.Limit)
+}
+
+// IsRelationLimitError reports whether the cause of err is a
+// *RelationLimitError.
+func IsRelationLimitError(err error) bool {
+	_, ok := errors.Cause(err).(*RelationLimitError)
+	return ok
+}
+
+// RelationCounts returns the number of relations that each endpoint of
+// an application using the charm with metadata meta would take part in
+// with the given number of units and related applications. Each related
+// application counts once per endpoint, however often it is listed. A
+// peer endpoint takes part in one relation once the application has
+// more than one unit. Endpoints taking part in no relation are omitted.
+func RelationCounts(meta *Meta, units int, related []RelatedEndpoint) (map[string]int, error) {
+	if units < 0 {
+		return nil, errors.NotValidf("unit count %d", units)
+	}
+	counts := make(map[string]int)
+	seen := make(map[RelatedEndpoint]bool)
+	for _, rel := range related {
+		if len(relatableEndpoints(meta, rel.Endpoint)) == 0 {
+			return nil, errors.NotFoundf("endpoint %q of charm %q", rel.Endpoint, meta.Name)
+		}
+		if seen[rel] {
+			continue
+		}
+		seen[rel] = true
+		counts[rel.Endpoint]++
+	}
+	if units > 1 {
+		for name := range meta.Peers {
+			counts[name] = 1
+		}
+	}
+	return counts, nil
+}
+
+// CheckRelationLimits checks, before an application using the charm
+// with metadata meta is scaled to the given number of units, that none
+// of its endpoints would take part in more relations with the related
+// applications than the limit in its metadata allows. If one would, a
+// *RelationLimitError naming the first such endpoint, in name order, is
+// returned. Endpoints without a limit, including the implicit juju-info
+// endpoints, are not checked.
+func CheckRelationLimits(meta *Meta, units int, related []RelatedEndpoint) error {
+	counts, err := RelationCounts(meta, units, related)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	relations := meta.CombinedRelations()
+	for _, name := range names {
+		rel, ok := relations[name]
+		if !ok || rel.Limit <= 0 {
+			continue
+		}
+		if counts[name] > rel.Limit {
+			return &RelationLimitError{
+				Endpoint:  name,
+				Limit:     rel.Limit,
+				Relations: counts[name],
+			}
+		}
+	}
+	return nil
+}
+
+// LimitWarnings returns a warning for each peer relation of the charm
+// with a limit above one. A peer endpoint only ever takes part in the
+// single relation between the units of its application, so such a limit
+// has no effect and usually reflects a misunderstanding of what it
+// counts. They are warnings rather than errors as published charms
+// declare such limits.
+func (m Meta) LimitWarnings() []string {
+	var warnings []string
+	for _, name := range sortedRelationNames(m.Peers) {
+		if limit := m.Peers[name].Limit; limit > 1 {
+			warnings = append(warnings, fmt.Sprintf(
+				"charm %q peer relation %q has limit %d, but a peer endpoint takes part in one relation only",
+				m.Name, name, limit))
+		}
+	}
+	return warnings
+}
+
+// OptionalWarnings returns a warning for each provided or peer relation
+// of the charm marked as optional. Only required relations can be
+// optional, so the flag has no effect on them.
+func (m Meta) OptionalWarnings() []string {
+	var warnings []string
+	for _, rel := range m.SortedEndpoints() {
+		if rel.Optional && rel.Role != RoleRequirer {
+			warnings = append(warnings, fmt.Sprintf(
+				"charm %q %s relation %q is marked optional, but only required relations can be optional",
+				m.Name, rel.Role, rel.Name))
+		}
+	}
+	return warnings
+}
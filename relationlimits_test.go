@@ -0,0 +1,76 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type RelationLimitsSuite struct{}
+
+var _ = gc.Suite(&RelationLimitsSuite{})
+
+func (s *RelationLimitsSuite) TestRelationCounts(c *gc.C) {
+	meta, err := charm.ReadMeta(strings.NewReader(dummyMetadata + `
+requires:
+  db:
+    interface: mysql
+    limit: 1
+peers:
+  cluster: wordpress-cluster
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	related := []charm.RelatedEndpoint{
+		{Endpoint: "db", Application: "mysql"},
+		{Endpoint: "db", Application: "mysql"},
+		{Endpoint: "juju-info", Application: "logging"},
+	}
+	counts, err := charm.RelationCounts(meta, 1, related)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(counts, jc.DeepEquals, map[string]int{"db": 1, "juju-info": 1})
+
+	counts, err = charm.RelationCounts(meta, 3, related)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(counts, jc.DeepEquals, map[string]int{"db": 1, "juju-info": 1, "cluster": 1})
+
+	_, err = charm.RelationCounts(meta, -1, nil)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	_, err = charm.RelationCounts(meta, 1, []charm.RelatedEndpoint{{Endpoint: "cluster", Application: "other"}})
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(err, gc.ErrorMatches, `endpoint "cluster" of charm "a" not found`)
+}
+
+func (s *RelationLimitsSuite) TestCheckRelationLimits(c *gc.C) {
+	meta := readCharmDir(c, "wordpress").Meta()
+	err := charm.CheckRelationLimits(meta, 3, []charm.RelatedEndpoint{
+		{Endpoint: "db", Application: "mysql"},
+		{Endpoint: "cache", Application: "varnish1"},
+		{Endpoint: "cache", Application: "varnish2"},
+		{Endpoint: "url", Application: "haproxy1"},
+		{Endpoint: "url", Application: "haproxy2"},
+		{Endpoint: "url", Application: "haproxy3"},
+	})
+	c.Check(err, jc.ErrorIsNil)
+
+	err = charm.CheckRelationLimits(meta, 3, []charm.RelatedEndpoint{
+		{Endpoint: "db", Application: "mysql"},
+		{Endpoint: "db", Application: "mariadb"},
+		{Endpoint: "cache", Application: "varnish1"},
+		{Endpoint: "cache", Application: "varnish2"},
+		{Endpoint: "cache", Application: "varnish3"},
+	})
+	c.Check(err, gc.ErrorMatches, `endpoint "cache" would take part in 3 relations, exceeding its limit of 2`)
+	c.Check(err, jc.Satisfies, charm.IsRelationLimitError)
+	c.Check(errors.Cause(err), jc.DeepEquals, &charm.RelationLimitError{
+		Endpoint:  "cache",
+		Limit:     2,
+		Relations: 3,
+	})
+}
@@ -6,13 +6,12 @@ package resource
 import (
 	stdhash "hash"
 	"io"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/v2/hash"
 )
 
-var newHash, validateSum = hash.SHA384()
-
 // Fingerprint represents the unique fingerprint value of a resource's data.
 type Fingerprint struct {
 	hash.Fingerprint
@@ -21,30 +20,90 @@ type Fingerprint struct {
 // NewFingerprint returns wraps the provided raw fingerprint bytes.
 // This function roundtrips with Fingerprint.Bytes().
 func NewFingerprint(raw []byte) (Fingerprint, error) {
-	fp, err := hash.NewFingerprint(raw, validateSum)
-	if err != nil {
-		return Fingerprint{}, errors.Trace(err)
-	}
-	return Fingerprint{fp}, nil
+	fp, err := NewFingerprintUsing(DefaultHashAlgorithm, raw)
+	return fp.Fingerprint, err
 }
 
 // ParseFingerprint returns wraps the provided raw fingerprint string.
 // This function roundtrips with Fingerprint.String().
 func ParseFingerprint(raw string) (Fingerprint, error) {
+	fp, err := ParseFingerprintUsing(DefaultHashAlgorithm, raw)
+	return fp.Fingerprint, err
+}
+
+// GenerateFingerprint returns the fingerprint for the provided data.
+func GenerateFingerprint(reader io.Reader) (Fingerprint, error) {
+	fp, err := GenerateFingerprintUsing(DefaultHashAlgorithm, reader)
+	return fp.Fingerprint, err
+}
+
+// QualifiedFingerprint holds a fingerprint along with the algorithm it
+// was computed with, for fingerprints that need not have been computed
+// with DefaultHashAlgorithm.
+type QualifiedFingerprint struct {
+	// Algorithm holds the algorithm the fingerprint was computed with.
+	Algorithm HashAlgorithm
+
+	Fingerprint
+}
+
+// NewFingerprintUsing is like NewFingerprint but for fingerprints
+// computed with the given algorithm.
+func NewFingerprintUsing(algorithm HashAlgorithm, raw []byte) (QualifiedFingerprint, error) {
+	_, validateSum, err := algorithm.funcs()
+	if err != nil {
+		return QualifiedFingerprint{}, errors.Trace(err)
+	}
+	fp, err := hash.NewFingerprint(raw, validateSum)
+	if err != nil {
+		return QualifiedFingerprint{}, errors.Trace(err)
+	}
+	return QualifiedFingerprint{algorithm, Fingerprint{fp}}, nil
+}
+
+// ParseFingerprintUsing is like ParseFingerprint but for fingerprints
+// computed with the given algorithm.
+func ParseFingerprintUsing(algorithm HashAlgorithm, raw string) (QualifiedFingerprint, error) {
+	_, validateSum, err := algorithm.funcs()
+	if err != nil {
+		return QualifiedFingerprint{}, errors.Trace(err)
+	}
 	fp, err := hash.ParseHexFingerprint(raw, validateSum)
 	if err != nil {
-		return Fingerprint{}, errors.Trace(err)
+		return QualifiedFingerprint{}, errors.Trace(err)
 	}
-	return Fingerprint{fp}, nil
+	return QualifiedFingerprint{algorithm, Fingerprint{fp}}, nil
 }
 
-// GenerateFingerprint returns the fingerprint for the provided data.
-func GenerateFingerprint(reader io.Reader) (Fingerprint, error) {
+// ParseQualifiedFingerprint parses a fingerprint in the form
+// "algorithm:hex", as returned by QualifiedFingerprint.String.
+func ParseQualifiedFingerprint(raw string) (QualifiedFingerprint, error) {
+	i := strings.Index(raw, ":")
+	if i < 0 {
+		return QualifiedFingerprint{}, errors.NotValidf("fingerprint %q without algorithm", raw)
+	}
+	return ParseFingerprintUsing(HashAlgorithm(raw[:i]), raw[i+1:])
+}
+
+// GenerateFingerprintUsing returns the fingerprint for the provided
+// data, computed with the given algorithm.
+func GenerateFingerprintUsing(algorithm HashAlgorithm, reader io.Reader) (QualifiedFingerprint, error) {
+	newHash, _, err := algorithm.funcs()
+	if err != nil {
+		return QualifiedFingerprint{}, errors.Trace(err)
+	}
 	fp, err := hash.GenerateFingerprint(reader, newHash)
 	if err != nil {
-		return Fingerprint{}, errors.Trace(err)
+		return QualifiedFingerprint{}, errors.Trace(err)
 	}
-	return Fingerprint{fp}, nil
+	return QualifiedFingerprint{algorithm, Fingerprint{fp}}, nil
+}
+
+// String returns the hex-encoded fingerprint prefixed with the name of
+// its algorithm, as in "sha384:1234...". This function roundtrips with
+// ParseQualifiedFingerprint.
+func (fp QualifiedFingerprint) String() string {
+	return fp.Algorithm.String() + ":" + fp.Hex()
 }
 
 // Fingerprint is a hash that may be used to generate fingerprints.
@@ -52,11 +111,27 @@ type FingerprintHash struct {
 	stdhash.Hash
 }
 
-// NewFingerprintHash returns a hash that may be used to create fingerprints.
+// NewFingerprintHash returns a hash that may be used to create
+// fingerprints with DefaultHashAlgorithm.
 func NewFingerprintHash() *FingerprintHash {
+	fph, err := NewFingerprintHashUsing(DefaultHashAlgorithm)
+	if err != nil {
+		// The default algorithm is always registered.
+		panic(err)
+	}
+	return fph
+}
+
+// NewFingerprintHashUsing returns a hash that may be used to create
+// fingerprints with the given algorithm.
+func NewFingerprintHashUsing(algorithm HashAlgorithm) (*FingerprintHash, error) {
+	newHash, _, err := algorithm.funcs()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	return &FingerprintHash{
 		Hash: newHash(),
-	}
+	}, nil
 }
 
 // Fingerprint returns the current fingerprint of the hash.
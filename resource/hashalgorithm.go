@@ -0,0 +1,125 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package resource
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	stdhash "hash"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/juju/errors"
+)
+
+// HashAlgorithm identifies the algorithm used to compute a fingerprint.
+type HashAlgorithm string
+
+// The hash algorithms registered by default.
+const (
+	SHA256 HashAlgorithm = "sha256"
+	SHA384 HashAlgorithm = "sha384"
+	SHA512 HashAlgorithm = "sha512"
+)
+
+// DefaultHashAlgorithm is the algorithm used by the functions that
+// do not take one, such as GenerateFingerprint.
+const DefaultHashAlgorithm = SHA384
+
+var validHashAlgorithm = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+var builtinHashAlgorithms = map[HashAlgorithm]func() stdhash.Hash{
+	SHA256: sha256.New,
+	SHA384: sha512.New384,
+	SHA512: sha512.New,
+}
+
+var (
+	hashAlgorithmsMu sync.RWMutex
+	hashAlgorithms   = copyHashAlgorithms(builtinHashAlgorithms)
+)
+
+// RegisterHashAlgorithm makes the named hash algorithm available for
+// computing fingerprints. It returns an error if the name is not valid
+// or is already registered.
+func RegisterHashAlgorithm(name HashAlgorithm, newHash func() stdhash.Hash) error {
+	if !validHashAlgorithm.MatchString(string(name)) {
+		return errors.NotValidf("hash algorithm name %q", name)
+	}
+	if newHash == nil {
+		return errors.NotValidf("nil hash constructor for %q", name)
+	}
+	hashAlgorithmsMu.Lock()
+	defer hashAlgorithmsMu.Unlock()
+	if _, ok := hashAlgorithms[name]; ok {
+		return errors.AlreadyExistsf("hash algorithm %q", name)
+	}
+	hashAlgorithms[name] = newHash
+	return nil
+}
+
+// ResetHashAlgorithms discards the algorithms added by
+// RegisterHashAlgorithm, restoring those built into this package. It
+// is meant for tests of code that registers algorithms at runtime.
+func ResetHashAlgorithms() {
+	hashAlgorithmsMu.Lock()
+	defer hashAlgorithmsMu.Unlock()
+	hashAlgorithms = copyHashAlgorithms(builtinHashAlgorithms)
+}
+
+func copyHashAlgorithms(algorithms map[HashAlgorithm]func() stdhash.Hash) map[HashAlgorithm]func() stdhash.Hash {
+	result := make(map[HashAlgorithm]func() stdhash.Hash, len(algorithms))
+	for name, newHash := range algorithms {
+		result[name] = newHash
+	}
+	return result
+}
+
+// HashAlgorithms returns the registered hash algorithms, sorted by name.
+func HashAlgorithms() []HashAlgorithm {
+	hashAlgorithmsMu.RLock()
+	defer hashAlgorithmsMu.RUnlock()
+	result := make([]HashAlgorithm, 0, len(hashAlgorithms))
+	for name := range hashAlgorithms {
+		result = append(result, name)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+	return result
+}
+
+// Validate checks that the algorithm is registered.
+func (a HashAlgorithm) Validate() error {
+	_, _, err := a.funcs()
+	return errors.Trace(err)
+}
+
+// String returns the name of the algorithm.
+func (a HashAlgorithm) String() string {
+	return string(a)
+}
+
+// funcs returns the newHash and validate functions for the algorithm,
+// in the same form as those returned by hash.SHA384.
+func (a HashAlgorithm) funcs() (func() stdhash.Hash, func([]byte) error, error) {
+	hashAlgorithmsMu.RLock()
+	newHash, ok := hashAlgorithms[a]
+	hashAlgorithmsMu.RUnlock()
+	if !ok {
+		return nil, nil, errors.NotFoundf("hash algorithm %q", a)
+	}
+	size := newHash().Size()
+	validate := func(sum []byte) error {
+		if len(sum) < size {
+			return errors.NewNotValid(nil, "invalid fingerprint (too small)")
+		}
+		if len(sum) > size {
+			return errors.NewNotValid(nil, "invalid fingerprint (too big)")
+		}
+		return nil
+	}
+	return newHash, validate, nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package resource_test
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	stdhash "hash"
+	"hash/crc32"
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8/resource"
+)
+
+var _ = gc.Suite(&HashAlgorithmSuite{})
+
+type HashAlgorithmSuite struct{}
+
+func (s *HashAlgorithmSuite) TearDownTest(c *gc.C) {
+	resource.ResetHashAlgorithms()
+}
+
+func (s *HashAlgorithmSuite) TestBuiltins(c *gc.C) {
+	registered := make(map[resource.HashAlgorithm]bool)
+	for _, name := range resource.HashAlgorithms() {
+		registered[name] = true
+	}
+	for _, name := range []resource.HashAlgorithm{resource.SHA256, resource.SHA384, resource.SHA512} {
+		c.Check(registered[name], jc.IsTrue, gc.Commentf("%s", name))
+		c.Check(name.Validate(), jc.ErrorIsNil)
+	}
+}
+
+func (s *HashAlgorithmSuite) TestGenerateFingerprintUsing(c *gc.C) {
+	for alg, h := range map[resource.HashAlgorithm]stdhash.Hash{
+		resource.SHA256: sha256.New(),
+		resource.SHA384: sha512.New384(),
+		resource.SHA512: sha512.New(),
+	} {
+		h.Write([]byte("spamspamspam"))
+		expected := hex.EncodeToString(h.Sum(nil))
+
+		fp, err := resource.GenerateFingerprintUsing(alg, strings.NewReader("spamspamspam"))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(fp.Algorithm, gc.Equals, alg)
+		c.Check(fp.Hex(), gc.Equals, expected)
+		c.Check(fp.String(), gc.Equals, string(alg)+":"+expected)
+
+		parsed, err := resource.ParseQualifiedFingerprint(fp.String())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(parsed, jc.DeepEquals, fp)
+
+		fph, err := resource.NewFingerprintHashUsing(alg)
+		c.Assert(err, jc.ErrorIsNil)
+		fph.Write([]byte("spamspamspam"))
+		c.Check(fph.Fingerprint(), jc.DeepEquals, fp.Fingerprint)
+	}
+}
+
+func (s *HashAlgorithmSuite) TestDefaultAlgorithm(c *gc.C) {
+	fp, err := resource.GenerateFingerprint(strings.NewReader("spamspamspam"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(resource.DefaultHashAlgorithm, gc.Equals, resource.SHA384)
+
+	explicit, err := resource.GenerateFingerprintUsing(resource.SHA384, strings.NewReader("spamspamspam"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(explicit.Fingerprint, jc.DeepEquals, fp)
+	c.Check(resource.Fingerprint{Fingerprint: fp.Fingerprint}, jc.DeepEquals, fp)
+	fph := resource.NewFingerprintHash()
+	fph.Write([]byte("spamspamspam"))
+	c.Check(fph.Fingerprint(), jc.DeepEquals, fp)
+}
+
+func (s *HashAlgorithmSuite) TestSizeIsValidated(c *gc.C) {
+	fp, err := resource.GenerateFingerprintUsing(resource.SHA256, strings.NewReader("spamspamspam"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = resource.NewFingerprintUsing(resource.SHA512, fp.Bytes())
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `.*too small.*`)
+	_, err = resource.ParseFingerprint(fp.Hex())
+	c.Check(err, gc.ErrorMatches, `.*too small.*`)
+}
+
+func (s *HashAlgorithmSuite) TestUnknownAlgorithm(c *gc.C) {
+	_, err := resource.GenerateFingerprintUsing("md5", strings.NewReader("spam"))
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(err, gc.ErrorMatches, `hash algorithm "md5" not found`)
+
+	_, err = resource.NewFingerprintHashUsing("md5")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = resource.ParseQualifiedFingerprint("abcdef")
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *HashAlgorithmSuite) TestRegisterHashAlgorithm(c *gc.C) {
+	err := resource.RegisterHashAlgorithm("crc32-test", func() stdhash.Hash {
+		return crc32.NewIEEE()
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	fph, err := resource.NewFingerprintHashUsing("crc32-test")
+	c.Assert(err, jc.ErrorIsNil)
+	fph.Write([]byte("spam"))
+	fp := fph.Fingerprint()
+	c.Check(fp.Bytes(), gc.HasLen, 4)
+	qfp, err := resource.ParseQualifiedFingerprint("crc32-test:" + fp.Hex())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(qfp.Fingerprint, jc.DeepEquals, fp)
+
+	err = resource.RegisterHashAlgorithm("crc32-test", sha256.New)
+	c.Check(err, jc.Satisfies, errors.IsAlreadyExists)
+	err = resource.RegisterHashAlgorithm(resource.SHA256, sha256.New)
+	c.Check(err, jc.Satisfies, errors.IsAlreadyExists)
+	err = resource.RegisterHashAlgorithm("Bad Name", sha256.New)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *HashAlgorithmSuite) TestResetHashAlgorithms(c *gc.C) {
+	err := resource.RegisterHashAlgorithm("crc32-test", func() stdhash.Hash {
+		return crc32.NewIEEE()
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(resource.HashAlgorithm("crc32-test").Validate(), jc.ErrorIsNil)
+
+	resource.ResetHashAlgorithms()
+	c.Check(resource.HashAlgorithm("crc32-test").Validate(), jc.Satisfies, errors.IsNotFound)
+	c.Check(resource.HashAlgorithms(), jc.DeepEquals, []resource.HashAlgorithm{
+		resource.SHA256, resource.SHA384, resource.SHA512,
+	})
+}
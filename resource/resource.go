@@ -19,7 +19,8 @@ type Resource struct {
 	// Revision is the charm store revision of the resource.
 	Revision int
 
-	// Fingerprint is the SHA-384 checksum for the resource blob.
+	// Fingerprint is the checksum for the resource blob. It is
+	// computed with SHA-384 unless it records another algorithm.
 	Fingerprint Fingerprint
 
 	// Size is the size of the resource, in bytes.
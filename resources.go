@@ -12,12 +12,14 @@ import (
 	"github.com/juju/charm/v8/resource"
 )
 
+var resourceFields = schema.Fields{
+	"type":        schema.String(),
+	"filename":    schema.String(), // TODO(ericsnow) Change to "path"?
+	"description": schema.String(),
+}
+
 var resourceSchema = schema.FieldMap(
-	schema.Fields{
-		"type":        schema.String(),
-		"filename":    schema.String(), // TODO(ericsnow) Change to "path"?
-		"description": schema.String(),
-	},
+	resourceFields,
 	schema.Defaults{
 		"type":        resource.TypeFile.String(),
 		"filename":    "",
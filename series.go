@@ -0,0 +1,288 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/os/series"
+)
+
+// SeriesStatus describes the support status of a series at a given time.
+type SeriesStatus string
+
+// Support statuses a series may be in.
+const (
+	// SeriesSupported is the status of series receiving standard
+	// support.
+	SeriesSupported SeriesStatus = "supported"
+
+	// SeriesESM is the status of series past their standard support
+	// but still covered by extended security maintenance.
+	SeriesESM SeriesStatus = "esm"
+
+	// SeriesEOL is the status of series that are no longer
+	// supported at all.
+	SeriesEOL SeriesStatus = "eol"
+)
+
+// SeriesInfo describes a release of an operating system that charms
+// may declare in the series section of metadata.yaml.
+type SeriesInfo struct {
+	// Name is the series name, such as "focal".
+	Name string
+
+	// OS is the name of the operating system, such as "ubuntu".
+	OS string
+
+	// Version is the version of the release, such as "20.04".
+	Version string
+
+	// LTS reports whether the release is a long term support release.
+	LTS bool
+
+	// EOL is the date at which standard support for the release ends.
+	EOL time.Time
+
+	// ESMEOL is the date at which extended security maintenance for
+	// the release ends. It is zero for releases without ESM.
+	ESMEOL time.Time
+}
+
+// Status returns the support status of the series at the given time.
+func (s SeriesInfo) Status(now time.Time) SeriesStatus {
+	switch {
+	case now.Before(s.EOL):
+		return SeriesSupported
+	case now.Before(s.ESMEOL):
+		return SeriesESM
+	}
+	return SeriesEOL
+}
+
+func seriesDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+var knownSeriesMutex sync.RWMutex
+
+// knownSeries holds the series this package knows the support
+// lifecycle of, keyed by series name. It starts as a copy of
+// builtinSeries and is guarded by knownSeriesMutex.
+var knownSeries = copySeriesInfo(builtinSeries)
+
+// builtinSeries holds the series whose support lifecycle was known when
+// this package was released. It is never modified.
+var builtinSeries = map[string]SeriesInfo{
+	"precise": {
+		Name: "precise", OS: "ubuntu", Version: "12.04", LTS: true,
+		EOL:    seriesDate(2017, time.April, 28),
+		ESMEOL: seriesDate(2019, time.April, 30),
+	},
+	"trusty": {
+		Name: "trusty", OS: "ubuntu", Version: "14.04", LTS: true,
+		EOL:    seriesDate(2019, time.April, 25),
+		ESMEOL: seriesDate(2024, time.April, 25),
+	},
+	"xenial": {
+		Name: "xenial", OS: "ubuntu", Version: "16.04", LTS: true,
+		EOL:    seriesDate(2021, time.April, 30),
+		ESMEOL: seriesDate(2026, time.April, 30),
+	},
+	"bionic": {
+		Name: "bionic", OS: "ubuntu", Version: "18.04", LTS: true,
+		EOL:    seriesDate(2023, time.May, 31),
+		ESMEOL: seriesDate(2028, time.April, 30),
+	},
+	"cosmic": {
+		Name: "cosmic", OS: "ubuntu", Version: "18.10",
+		EOL: seriesDate(2019, time.July, 18),
+	},
+	"disco": {
+		Name: "disco", OS: "ubuntu", Version: "19.04",
+		EOL: seriesDate(2020, time.January, 23),
+	},
+	"eoan": {
+		Name: "eoan", OS: "ubuntu", Version: "19.10",
+		EOL: seriesDate(2020, time.July, 17),
+	},
+	"focal": {
+		Name: "focal", OS: "ubuntu", Version: "20.04", LTS: true,
+		EOL:    seriesDate(2025, time.May, 29),
+		ESMEOL: seriesDate(2030, time.April, 30),
+	},
+	"groovy": {
+		Name: "groovy", OS: "ubuntu", Version: "20.10",
+		EOL: seriesDate(2021, time.July, 22),
+	},
+	"hirsute": {
+		Name: "hirsute", OS: "ubuntu", Version: "21.04",
+		EOL: seriesDate(2022, time.January, 20),
+	},
+	"impish": {
+		Name: "impish", OS: "ubuntu", Version: "21.10",
+		EOL: seriesDate(2022, time.July, 14),
+	},
+	"jammy": {
+		Name: "jammy", OS: "ubuntu", Version: "22.04", LTS: true,
+		EOL:    seriesDate(2027, time.June, 1),
+		ESMEOL: seriesDate(2032, time.April, 9),
+	},
+	"kinetic": {
+		Name: "kinetic", OS: "ubuntu", Version: "22.10",
+		EOL: seriesDate(2023, time.July, 20),
+	},
+	"lunar": {
+		Name: "lunar", OS: "ubuntu", Version: "23.04",
+		EOL: seriesDate(2024, time.January, 25),
+	},
+	"mantic": {
+		Name: "mantic", OS: "ubuntu", Version: "23.10",
+		EOL: seriesDate(2024, time.July, 11),
+	},
+	"noble": {
+		Name: "noble", OS: "ubuntu", Version: "24.04", LTS: true,
+		EOL:    seriesDate(2029, time.May, 31),
+		ESMEOL: seriesDate(2034, time.April, 25),
+	},
+	"centos7": {
+		Name: "centos7", OS: "centos", Version: "7",
+		EOL: seriesDate(2024, time.June, 30),
+	},
+	"centos8": {
+		Name: "centos8", OS: "centos", Version: "8",
+		EOL: seriesDate(2021, time.December, 31),
+	},
+}
+
+// KnownSeries returns a snapshot of the series whose support lifecycle
+// is known, sorted by name, which later registrations do not affect.
+func KnownSeries() []SeriesInfo {
+	knownSeriesMutex.RLock()
+	defer knownSeriesMutex.RUnlock()
+	result := make([]SeriesInfo, 0, len(knownSeries))
+	for _, info := range knownSeries {
+		result = append(result, info)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// LookupSeries returns the lifecycle information for the named series,
+// and whether it is known.
+func LookupSeries(name string) (SeriesInfo, bool) {
+	knownSeriesMutex.RLock()
+	defer knownSeriesMutex.RUnlock()
+	info, ok := knownSeries[name]
+	return info, ok
+}
+
+// SetSeriesInfo adds the lifecycle information for a series, replacing
+// any already known for a series of the same name, so that releases
+// made after this package can be taken into account. The name must be
+// usable in charm URLs and metadata, as reported by IsValidSeries.
+func SetSeriesInfo(info SeriesInfo) error {
+	if info.Name == "" {
+		return errors.NotValidf("empty series name")
+	}
+	if !IsValidSeries(info.Name) {
+		return errors.NotValidf("series name %q", info.Name)
+	}
+	knownSeriesMutex.Lock()
+	defer knownSeriesMutex.Unlock()
+	knownSeries[info.Name] = info
+	return nil
+}
+
+// ResetKnownSeries discards the lifecycle information added by
+// SetSeriesInfo and UpdateSeriesFromDistroInfo, restoring the series
+// known to this package when it was released. It is meant for tests of
+// code that registers series at runtime.
+func ResetKnownSeries() {
+	knownSeriesMutex.Lock()
+	defer knownSeriesMutex.Unlock()
+	knownSeries = copySeriesInfo(builtinSeries)
+}
+
+func copySeriesInfo(series map[string]SeriesInfo) map[string]SeriesInfo {
+	result := make(map[string]SeriesInfo, len(series))
+	for name, info := range series {
+		result[name] = info
+	}
+	return result
+}
+
+// UpdateSeriesFromDistroInfo updates the lifecycle information of the
+// known Ubuntu series, and of those supported by github.com/juju/os,
+// from the distro-info CSV file at path, usually
+// series.UbuntuDistroInfo. The version, LTS status and end of standard
+// support are taken from the file; ESM dates, which the file does not
+// provide, are kept. A missing file is not an error, as distro-info is
+// only installed on Ubuntu.
+func UpdateSeriesFromDistroInfo(path string) error {
+	distroInfo := series.NewDistroInfo(path)
+	if err := distroInfo.Refresh(); err != nil {
+		return errors.Annotate(err, "reading distro info")
+	}
+	knownSeriesMutex.Lock()
+	defer knownSeriesMutex.Unlock()
+	names := series.SupportedSeries()
+	for name := range knownSeries {
+		names = append(names, name)
+	}
+	for _, name := range names {
+		distroSeries, ok := distroInfo.SeriesInfo(name)
+		if !ok {
+			continue
+		}
+		info := knownSeries[name]
+		info.Name = name
+		info.OS = "ubuntu"
+		info.Version = strings.TrimSuffix(distroSeries.Version, " LTS")
+		info.LTS = distroSeries.LTS()
+		info.EOL = distroSeries.EOL.UTC()
+		knownSeries[name] = info
+	}
+	return nil
+}
+
+// SeriesWarnings returns warnings about the support status, at the
+// given time, of the series declared by the charm. A warning is
+// returned when none of the declared series that are known still
+// receive standard support, so that stores can flag charms that need
+// updating. Series whose lifecycle is not known are ignored.
+func (m Meta) SeriesWarnings(now time.Time) []string {
+	var unsupported []string
+	allEOL := true
+	for _, name := range m.Series {
+		info, ok := LookupSeries(name)
+		if !ok {
+			continue
+		}
+		status := info.Status(now)
+		if status == SeriesSupported {
+			return nil
+		}
+		if status != SeriesEOL {
+			allEOL = false
+		}
+		unsupported = append(unsupported, fmt.Sprintf("%s (%s)", name, status))
+	}
+	switch {
+	case len(unsupported) == 0:
+		return nil
+	case allEOL:
+		return []string{fmt.Sprintf("charm %q only declares end-of-life series: %s",
+			m.Name, strings.Join(unsupported, ", "))}
+	}
+	return []string{fmt.Sprintf("charm %q only declares series past standard support: %s",
+		m.Name, strings.Join(unsupported, ", "))}
+}
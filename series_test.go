@@ -0,0 +1,165 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type SeriesSuite struct {
+	restoreSeries func()
+}
+
+var _ = gc.Suite(&SeriesSuite{})
+
+func (s *SeriesSuite) SetUpTest(c *gc.C) {
+	s.restoreSeries = charm.SaveKnownSeries()
+}
+
+func (s *SeriesSuite) TearDownTest(c *gc.C) {
+	s.restoreSeries()
+}
+
+func (s *SeriesSuite) TestLookupSeries(c *gc.C) {
+	info, ok := charm.LookupSeries("focal")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(info.OS, gc.Equals, "ubuntu")
+	c.Assert(info.Version, gc.Equals, "20.04")
+	c.Assert(info.LTS, jc.IsTrue)
+
+	_, ok = charm.LookupSeries("no-such-series")
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *SeriesSuite) TestKnownSeriesSorted(c *gc.C) {
+	known := charm.KnownSeries()
+	c.Assert(len(known) > 0, jc.IsTrue)
+	for i := 1; i < len(known); i++ {
+		c.Assert(known[i-1].Name < known[i].Name, jc.IsTrue)
+	}
+}
+
+func (s *SeriesSuite) TestResetKnownSeries(c *gc.C) {
+	before := charm.KnownSeries()
+	focal, _ := charm.LookupSeries("focal")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := charm.SetSeriesInfo(charm.SeriesInfo{Name: fmt.Sprintf("future%d", i), OS: "ubuntu"})
+			c.Check(err, jc.ErrorIsNil)
+			charm.KnownSeries()
+		}(i)
+	}
+	wg.Wait()
+	modified := focal
+	modified.LTS = false
+	c.Assert(charm.SetSeriesInfo(modified), jc.ErrorIsNil)
+	c.Assert(charm.KnownSeries(), gc.HasLen, len(before)+10)
+
+	charm.ResetKnownSeries()
+	c.Assert(charm.KnownSeries(), jc.DeepEquals, before)
+	info, _ := charm.LookupSeries("focal")
+	c.Assert(info, jc.DeepEquals, focal)
+}
+
+func (s *SeriesSuite) TestStatus(c *gc.C) {
+	info, _ := charm.LookupSeries("xenial")
+	c.Check(info.Status(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)), gc.Equals, charm.SeriesSupported)
+	c.Check(info.Status(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)), gc.Equals, charm.SeriesESM)
+	c.Check(info.Status(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)), gc.Equals, charm.SeriesEOL)
+
+	info, _ = charm.LookupSeries("eoan")
+	c.Check(info.Status(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)), gc.Equals, charm.SeriesEOL)
+}
+
+var seriesWarningsTests = []struct {
+	about    string
+	series   []string
+	warnings []string
+}{{
+	about: "no series",
+}, {
+	about:  "supported series",
+	series: []string{"precise", "focal"},
+}, {
+	about:  "unknown series only",
+	series: []string{"quantal"},
+}, {
+	about:    "end-of-life series only",
+	series:   []string{"precise", "eoan", "quantal"},
+	warnings: []string{`charm "a" only declares end-of-life series: precise (eol), eoan (eol)`},
+}, {
+	about:    "esm series",
+	series:   []string{"precise", "xenial"},
+	warnings: []string{`charm "a" only declares series past standard support: precise (eol), xenial (esm)`},
+}}
+
+func (s *SeriesSuite) TestSeriesWarnings(c *gc.C) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, test := range seriesWarningsTests {
+		c.Logf("test %d: %s", i, test.about)
+		meta := charm.Meta{Name: "a", Series: test.series}
+		c.Check(meta.SeriesWarnings(now), jc.DeepEquals, test.warnings)
+	}
+}
+
+func (s *SeriesSuite) TestSetSeriesInfo(c *gc.C) {
+	err := charm.SetSeriesInfo(charm.SeriesInfo{
+		Name:    "plucky",
+		OS:      "ubuntu",
+		Version: "25.04",
+		EOL:     time.Date(2026, time.January, 15, 0, 0, 0, 0, time.UTC),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	info, ok := charm.LookupSeries("plucky")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(info.Version, gc.Equals, "25.04")
+
+	meta := charm.Meta{Name: "a", Series: []string{"plucky"}}
+	c.Assert(meta.SeriesWarnings(time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)), jc.DeepEquals, []string{
+		`charm "a" only declares end-of-life series: plucky (eol)`,
+	})
+
+	err = charm.SetSeriesInfo(charm.SeriesInfo{})
+	c.Assert(err, gc.ErrorMatches, "empty series name not valid")
+	err = charm.SetSeriesInfo(charm.SeriesInfo{Name: "ubuntu-26.04"})
+	c.Assert(err, gc.ErrorMatches, `series name "ubuntu-26.04" not valid`)
+}
+
+func (s *SeriesSuite) TestUpdateSeriesFromDistroInfo(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "ubuntu.csv")
+	err := ioutil.WriteFile(path, []byte(`version,codename,series,created,release,eol,eol-server,eol-esm
+12.04 LTS,Precise Pangolin,precise,2011-10-13,2012-04-26,2017-04-26,2017-04-26,2019-04-26
+20.04 LTS,Focal Fossa,focal,2019-10-17,2020-04-23,2025-06-30,2025-06-30,2030-04-23
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = charm.UpdateSeriesFromDistroInfo(path)
+	c.Assert(err, jc.ErrorIsNil)
+	info, ok := charm.LookupSeries("focal")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(info.Version, gc.Equals, "20.04")
+	c.Assert(info.LTS, jc.IsTrue)
+	c.Assert(info.EOL, gc.Equals, time.Date(2025, time.June, 30, 0, 0, 0, 0, time.UTC))
+	c.Assert(info.ESMEOL, gc.Equals, time.Date(2030, time.April, 30, 0, 0, 0, 0, time.UTC))
+}
+
+func (s *SeriesSuite) TestUpdateSeriesFromMissingDistroInfo(c *gc.C) {
+	err := charm.UpdateSeriesFromDistroInfo(filepath.Join(c.MkDir(), "ubuntu.csv"))
+	c.Assert(err, jc.ErrorIsNil)
+	info, _ := charm.LookupSeries("focal")
+	c.Assert(info.EOL, gc.Equals, time.Date(2025, time.May, 29, 0, 0, 0, 0, time.UTC))
+}
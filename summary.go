@@ -0,0 +1,100 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"sort"
+)
+
+// Summary holds a flat record of the main facts about a charm, suitable
+// for ingestion by data pipelines and analytics tools. Lists other than
+// Series are sorted, and none hold duplicates.
+type Summary struct {
+	Name        string `json:"name" yaml:"name"`
+	Revision    int    `json:"revision" yaml:"revision"`
+	Subordinate bool   `json:"subordinate" yaml:"subordinate"`
+
+	// Format holds the version of the charm format: 1 or 2.
+	Format int `json:"format" yaml:"format"`
+
+	// Series holds the series supported by the charm, in the order
+	// the charm declares them.
+	Series []string `json:"series" yaml:"series"`
+
+	Provides  int `json:"provides" yaml:"provides"`
+	Requires  int `json:"requires" yaml:"requires"`
+	Peers     int `json:"peers" yaml:"peers"`
+	Storage   int `json:"storage" yaml:"storage"`
+	Devices   int `json:"devices" yaml:"devices"`
+	Resources int `json:"resources" yaml:"resources"`
+	Options   int `json:"options" yaml:"options"`
+	Actions   int `json:"actions" yaml:"actions"`
+	Metrics   int `json:"metrics" yaml:"metrics"`
+
+	// Interfaces holds the interfaces of all the relations of the
+	// charm.
+	Interfaces []string `json:"interfaces" yaml:"interfaces"`
+
+	// StorageTypes holds the types of the stores of the charm.
+	StorageTypes []string `json:"storage-types" yaml:"storage-types"`
+
+	HasActions bool `json:"has-actions" yaml:"has-actions"`
+	HasMetrics bool `json:"has-metrics" yaml:"has-metrics"`
+}
+
+// Summarize returns a summary of the charm.
+func Summarize(ch Charm) Summary {
+	meta := ch.Meta()
+	summary := Summary{
+		Name:        meta.Name,
+		Revision:    ch.Revision(),
+		Subordinate: meta.Subordinate,
+		Format:      int(meta.Format()) + 1,
+		Series:      []string{},
+		Provides:    len(meta.Provides),
+		Requires:    len(meta.Requires),
+		Peers:       len(meta.Peers),
+		Storage:     len(meta.Storage),
+		Devices:     len(meta.Devices),
+		Resources:   len(meta.Resources),
+	}
+	summary.Series = append(summary.Series, meta.ComputedSeries()...)
+	var interfaces []string
+	for _, relation := range meta.CombinedRelations() {
+		interfaces = append(interfaces, relation.Interface)
+	}
+	summary.Interfaces = sortedUnique(interfaces)
+	var storageTypes []string
+	for _, store := range meta.Storage {
+		storageTypes = append(storageTypes, string(store.Type))
+	}
+	summary.StorageTypes = sortedUnique(storageTypes)
+	if config := ch.Config(); config != nil {
+		summary.Options = len(config.Options)
+	}
+	if actions := ch.Actions(); actions != nil {
+		summary.Actions = len(actions.ActionSpecs)
+	}
+	if metrics := ch.Metrics(); metrics != nil {
+		summary.Metrics = len(metrics.Metrics)
+	}
+	summary.HasActions = summary.Actions > 0
+	summary.HasMetrics = summary.Metrics > 0
+	return summary
+}
+
+// sortedUnique returns the given strings sorted, without duplicates. It
+// never returns nil, so that empty lists are marshaled as such.
+func sortedUnique(values []string) []string {
+	result := []string{}
+	seen := make(map[string]bool)
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
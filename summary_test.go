@@ -0,0 +1,82 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type SummarySuite struct{}
+
+var _ = gc.Suite(&SummarySuite{})
+
+func (s *SummarySuite) TestSummarize(c *gc.C) {
+	summary := charm.Summarize(readCharmDir(c, "wordpress"))
+	c.Assert(summary, jc.DeepEquals, charm.Summary{
+		Name:         "wordpress",
+		Revision:     3,
+		Format:       1,
+		Series:       []string{},
+		Provides:     3,
+		Requires:     2,
+		Options:      1,
+		Interfaces:   []string{"http", "logging", "monitoring", "mysql", "varnish"},
+		StorageTypes: []string{},
+	})
+}
+
+func (s *SummarySuite) TestSummarizeActionsAndMetrics(c *gc.C) {
+	summary := charm.Summarize(readCharmDir(c, "dummy"))
+	c.Check(summary.Options, gc.Equals, 4)
+	c.Check(summary.Actions, gc.Equals, 1)
+	c.Check(summary.HasActions, jc.IsTrue)
+	c.Check(summary.HasMetrics, jc.IsFalse)
+
+	summary = charm.Summarize(readCharmDir(c, "metered"))
+	c.Check(summary.Metrics, gc.Equals, 2)
+	c.Check(summary.HasMetrics, jc.IsTrue)
+	c.Check(summary.HasActions, jc.IsFalse)
+}
+
+func (s *SummarySuite) TestSummarizeStorage(c *gc.C) {
+	dir := c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "metadata.yaml"), []byte(`
+name: a
+summary: b
+description: c
+series: [focal, bionic]
+storage:
+  data:
+    type: filesystem
+  cache:
+    type: filesystem
+  raw:
+    type: block
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	ch, err := charm.ReadCharmDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	summary := charm.Summarize(ch)
+	c.Check(summary.Series, jc.DeepEquals, []string{"focal", "bionic"})
+	c.Check(summary.Storage, gc.Equals, 3)
+	c.Check(summary.StorageTypes, jc.DeepEquals, []string{"block", "filesystem"})
+}
+
+func (s *SummarySuite) TestMarshalJSON(c *gc.C) {
+	data, err := json.Marshal(charm.Summarize(readCharmDir(c, "metered")))
+	c.Assert(err, jc.ErrorIsNil)
+	var fields map[string]interface{}
+	err = json.Unmarshal(data, &fields)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fields["name"], gc.Equals, "metered")
+	c.Check(fields["has-metrics"], gc.Equals, true)
+	c.Check(fields["interfaces"], jc.DeepEquals, []interface{}{})
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"github.com/juju/systems"
+)
+
+// SeriesSource names where a series supported by a charm was found.
+type SeriesSource string
+
+// The sources of SupportedSeries.
+const (
+	// SeriesFromMetadata is the series field of a v1 charm's
+	// metadata.yaml.
+	SeriesFromMetadata SeriesSource = "metadata"
+
+	// SeriesFromSystems is the systems field of a v2 charm's
+	// metadata.yaml.
+	SeriesFromSystems SeriesSource = "systems"
+
+	// SeriesFromBases is the bases field of a v2 charm's
+	// metadata.yaml.
+	SeriesFromBases SeriesSource = "bases"
+
+	// SeriesFromURL is the series of the URL the charm was
+	// published or fetched under.
+	SeriesFromURL SeriesSource = "url"
+)
+
+// SupportedSeries holds a series supported by a charm and the sources
+// that declare it.
+type SupportedSeries struct {
+	// Series holds the name of the series.
+	Series string
+
+	// Sources holds the sources declaring the series, in the order
+	// in which ResolveSeries consults them.
+	Sources []SeriesSource
+}
+
+// ResolveSeries returns the series supported by ch, merging the series
+// declared by its metadata, either as a v1 series list or as v2
+// systems and bases, with the series of url, which may be nil. The
+// result holds each series once, in the order in which the charm
+// declares them, followed by the series of url if the charm does not
+// declare it, so that the first element is the charm's default series
+// as used by SeriesForCharm. Bundle URLs, and URLs without a series,
+// are ignored.
+//
+// Systems and bases are named by the known series of their release,
+// whatever the risk of their channel, so that "18.04/edge" and
+// "18.04/stable" both resolve to bionic. Those of releases without a
+// known series keep their system form.
+func ResolveSeries(ch Charm, url *URL) []SupportedSeries {
+	var result []SupportedSeries
+	index := make(map[string]int)
+	add := func(series string, source SeriesSource) {
+		if i, ok := index[series]; ok {
+			for _, s := range result[i].Sources {
+				if s == source {
+					return
+				}
+			}
+			result[i].Sources = append(result[i].Sources, source)
+			return
+		}
+		index[series] = len(result)
+		result = append(result, SupportedSeries{
+			Series:  series,
+			Sources: []SeriesSource{source},
+		})
+	}
+	meta := ch.Meta()
+	if meta.Format() == FormatV2 {
+		for _, system := range meta.Systems {
+			add(systemSeries(system), SeriesFromSystems)
+		}
+		for _, base := range meta.Bases {
+			add(baseSeries(base), SeriesFromBases)
+		}
+	} else {
+		for _, series := range meta.Series {
+			add(series, SeriesFromMetadata)
+		}
+	}
+	if url != nil && url.Series != "" && url.Series != "bundle" {
+		add(url.Series, SeriesFromURL)
+	}
+	return result
+}
+
+// systemSeries returns the name of the known series of the release
+// that the system describes, ignoring the risk of its channel, or, if
+// there is none, the string form of the system.
+func systemSeries(s systems.System) string {
+	if base, err := BaseFromSystem(s); err == nil {
+		return baseSeries(base)
+	}
+	return s.String()
+}
+
+// SeriesNames returns the names of the given series, in order, in the
+// form expected by SeriesForCharm.
+func SeriesNames(series []SupportedSeries) []string {
+	if len(series) == 0 {
+		return nil
+	}
+	names := make([]string, len(series))
+	for i, s := range series {
+		names[i] = s.Series
+	}
+	return names
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type SupportedSeriesSuite struct{}
+
+var _ = gc.Suite(&SupportedSeriesSuite{})
+
+func (s *SupportedSeriesSuite) readCharm(c *gc.C, metadata string) charm.Charm {
+	dir := c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(dir, charm.MetadataFile), []byte(metadata), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	ch, err := charm.ReadCharmDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	return ch
+}
+
+func (s *SupportedSeriesSuite) TestMetadataSeries(c *gc.C) {
+	ch := s.readCharm(c, `
+name: a
+summary: b
+description: c
+series: [focal, bionic]
+`)
+	url := charm.MustParseURL("cs:bionic/a-1")
+	series := charm.ResolveSeries(ch, url)
+	c.Assert(series, jc.DeepEquals, []charm.SupportedSeries{{
+		Series:  "focal",
+		Sources: []charm.SeriesSource{charm.SeriesFromMetadata},
+	}, {
+		Series:  "bionic",
+		Sources: []charm.SeriesSource{charm.SeriesFromMetadata, charm.SeriesFromURL},
+	}})
+	c.Assert(charm.SeriesNames(series), jc.DeepEquals, []string{"focal", "bionic"})
+}
+
+func (s *SupportedSeriesSuite) TestSystems(c *gc.C) {
+	ch := s.readCharm(c, `
+name: a
+summary: b
+description: c
+systems:
+  - os: ubuntu
+    channel: 18.04/stable
+  - os: ubuntu
+    channel: 20.04/stable
+  - os: ubuntu
+    channel: 18.04/edge
+`)
+	series := charm.ResolveSeries(ch, charm.MustParseURL("cs:xenial/a"))
+	c.Assert(series, jc.DeepEquals, []charm.SupportedSeries{{
+		Series:  "bionic",
+		Sources: []charm.SeriesSource{charm.SeriesFromSystems},
+	}, {
+		Series:  "focal",
+		Sources: []charm.SeriesSource{charm.SeriesFromSystems},
+	}, {
+		Series:  "xenial",
+		Sources: []charm.SeriesSource{charm.SeriesFromURL},
+	}})
+}
+
+func (s *SupportedSeriesSuite) TestBases(c *gc.C) {
+	ch := s.readCharm(c, `
+name: a
+summary: b
+description: c
+systems:
+  - os: ubuntu
+    channel: 20.04/stable
+bases:
+  - name: ubuntu
+    channel: 22.04/candidate
+  - name: ubuntu
+    channel: "20.04"
+  - name: centos
+    channel: "99"
+`)
+	series := charm.ResolveSeries(ch, charm.MustParseURL("cs:jammy/a"))
+	c.Assert(series, jc.DeepEquals, []charm.SupportedSeries{{
+		Series:  "focal",
+		Sources: []charm.SeriesSource{charm.SeriesFromSystems, charm.SeriesFromBases},
+	}, {
+		Series:  "jammy",
+		Sources: []charm.SeriesSource{charm.SeriesFromBases, charm.SeriesFromURL},
+	}, {
+		Series:  "system#os=centos#channel=99/stable",
+		Sources: []charm.SeriesSource{charm.SeriesFromBases},
+	}})
+}
+
+func (s *SupportedSeriesSuite) TestLegacyCharm(c *gc.C) {
+	ch := s.readCharm(c, dummyMetadata)
+	c.Assert(charm.ResolveSeries(ch, nil), gc.HasLen, 0)
+	c.Assert(charm.SeriesNames(charm.ResolveSeries(ch, nil)), gc.IsNil)
+	c.Assert(charm.ResolveSeries(ch, charm.MustParseURL("cs:a")), gc.HasLen, 0)
+	c.Assert(charm.ResolveSeries(ch, charm.MustParseURL("cs:bundle/a")), gc.HasLen, 0)
+
+	series := charm.ResolveSeries(ch, charm.MustParseURL("local:trusty/a-3"))
+	c.Assert(series, jc.DeepEquals, []charm.SupportedSeries{{
+		Series:  "trusty",
+		Sources: []charm.SeriesSource{charm.SeriesFromURL},
+	}})
+	name, err := charm.SeriesForCharm("", charm.SeriesNames(series))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, "trusty")
+}
@@ -0,0 +1,199 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// categoryTags maps the categories used by charms written for the
+// legacy charm store onto the tags that replaced them.
+var categoryTags = map[string]string{
+	"analytics":    "analytics",
+	"app-servers":  "application-development",
+	"applications": "application-development",
+	"big-data":     "big-data",
+	"bigdata":      "big-data",
+	"cache-proxy":  "networking",
+	"database":     "databases",
+	"databases":    "databases",
+	"file-servers": "storage",
+	"misc":         "misc",
+	"monitoring":   "monitoring",
+	"network":      "networking",
+	"openstack":    "openstack",
+	"ops":          "ops",
+	"security":     "security",
+	"storage":      "storage",
+	"system":       "system",
+}
+
+// CanonicalTags returns the tags of the charm, followed by the tags
+// that its legacy categories map onto, without duplicates. Categories
+// without a known mapping are kept, normalized to lower case with
+// hyphens. A deprecation warning is returned for each category, so
+// that authors can move them to the tags field.
+func (m Meta) CanonicalTags() (tags []string, warnings []string) {
+	seen := make(map[string]bool)
+	add := func(tag string) {
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	for _, tag := range m.Tags {
+		add(tag)
+	}
+	for _, category := range m.Categories {
+		normalized := strings.Replace(strings.ToLower(strings.TrimSpace(category)), "_", "-", -1)
+		tag, ok := categoryTags[normalized]
+		if !ok {
+			tag = normalized
+			warnings = append(warnings, fmt.Sprintf(
+				"charm %q uses deprecated category %q, which has no known tag; use tags instead",
+				m.Name, category))
+		} else {
+			warnings = append(warnings, fmt.Sprintf(
+				"charm %q uses deprecated category %q; use tag %q instead",
+				m.Name, category, tag))
+		}
+		add(tag)
+	}
+	return tags, warnings
+}
+
+// canonicalTags holds the vocabulary of tags used by the store to
+// group charms.
+var canonicalTags = map[string]bool{
+	"ai-ml":                   true,
+	"analytics":               true,
+	"application-development": true,
+	"big-data":                true,
+	"cloud":                   true,
+	"containers":              true,
+	"databases":               true,
+	"identity":                true,
+	"logging-tracing":         true,
+	"messaging":               true,
+	"misc":                    true,
+	"monitoring":              true,
+	"networking":              true,
+	"openstack":               true,
+	"ops":                     true,
+	"security":                true,
+	"storage":                 true,
+	"system":                  true,
+	"web":                     true,
+}
+
+// tagSynonyms maps commonly used free-form tags onto the canonical tag
+// with the same meaning.
+var tagSynonyms = map[string]string{
+	"ai":               "ai-ml",
+	"app-servers":      "application-development",
+	"applications":     "application-development",
+	"auth":             "identity",
+	"authentication":   "identity",
+	"bigdata":          "big-data",
+	"cache":            "databases",
+	"cache-proxy":      "networking",
+	"database":         "databases",
+	"db":               "databases",
+	"devops":           "ops",
+	"docker":           "containers",
+	"file-servers":     "storage",
+	"hadoop":           "big-data",
+	"k8s":              "containers",
+	"kubernetes":       "containers",
+	"ldap":             "identity",
+	"logging":          "logging-tracing",
+	"logs":             "logging-tracing",
+	"machine-learning": "ai-ml",
+	"ml":               "ai-ml",
+	"mq":               "messaging",
+	"network":          "networking",
+	"nosql":            "databases",
+	"observability":    "monitoring",
+	"queue":            "messaging",
+	"sql":              "databases",
+	"tracing":          "logging-tracing",
+	"webserver":        "web",
+	"web-server":       "web",
+}
+
+var validTag = regexp.MustCompile(`^([a-z0-9]+(-[a-z0-9]+)*:)?[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ValidateTag checks that tag is made of lower case letters, digits and
+// single hyphens, optionally preceded by a namespace of the same form
+// and a colon, as in "vendor:acme".
+func ValidateTag(tag string) error {
+	if !validTag.MatchString(tag) {
+		return errors.NotValidf("tag %q", tag)
+	}
+	return nil
+}
+
+// CanonicalTagVocabulary returns the tags used by the store to group
+// charms, sorted.
+func CanonicalTagVocabulary() []string {
+	result := make([]string, 0, len(canonicalTags))
+	for tag := range canonicalTags {
+		result = append(result, tag)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// maxTagSuggestionDistance is the largest edit distance at which a
+// canonical tag is suggested for a free-form one.
+const maxTagSuggestionDistance = 2
+
+// SuggestTag returns the canonical tag that best matches the given
+// free-form tag, using a table of synonyms and then edit distance. It
+// returns false if tag is canonical, namespaced, or if no canonical tag
+// is close enough.
+func SuggestTag(tag string) (string, bool) {
+	if canonicalTags[tag] || strings.Contains(tag, ":") {
+		return "", false
+	}
+	normalized := strings.ToLower(strings.TrimSpace(tag))
+	normalized = strings.NewReplacer("_", "-", " ", "-").Replace(normalized)
+	if canonicalTags[normalized] {
+		return normalized, true
+	}
+	if canonical, ok := tagSynonyms[normalized]; ok {
+		return canonical, true
+	}
+	best, bestDistance := "", maxTagSuggestionDistance+1
+	for candidate := range canonicalTags {
+		d := editDistance(normalized, candidate)
+		if d < bestDistance || (d == bestDistance && candidate < best) {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best, best != ""
+}
+
+// TagSuggestions returns informational messages about the tags of the
+// charm: tags that do not follow the tag grammar, and tags for which
+// a canonical tag is suggested.
+func (m Meta) TagSuggestions() []string {
+	var messages []string
+	for _, tag := range m.Tags {
+		if err := ValidateTag(tag); err != nil {
+			messages = append(messages, fmt.Sprintf(
+				"charm %q tag %q should use lower case letters, digits and hyphens only", m.Name, tag))
+		}
+		if suggestion, ok := SuggestTag(tag); ok {
+			messages = append(messages, fmt.Sprintf(
+				"charm %q tag %q could be replaced by canonical tag %q", m.Name, tag, suggestion))
+		}
+	}
+	return messages
+}
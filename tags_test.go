@@ -0,0 +1,85 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type TagsSuite struct{}
+
+var _ = gc.Suite(&TagsSuite{})
+
+func (s *TagsSuite) TestCanonicalTags(c *gc.C) {
+	meta := charm.Meta{
+		Name:       "a",
+		Tags:       []string{"databases", "sql"},
+		Categories: []string{"database", "Big_Data", "weird"},
+	}
+	tags, warnings := meta.CanonicalTags()
+	c.Assert(tags, jc.DeepEquals, []string{"databases", "sql", "big-data", "weird"})
+	c.Assert(warnings, jc.DeepEquals, []string{
+		`charm "a" uses deprecated category "database"; use tag "databases" instead`,
+		`charm "a" uses deprecated category "Big_Data"; use tag "big-data" instead`,
+		`charm "a" uses deprecated category "weird", which has no known tag; use tags instead`,
+	})
+}
+
+func (s *TagsSuite) TestCanonicalTagsWithoutCategories(c *gc.C) {
+	meta := charm.Meta{
+		Name: "a",
+		Tags: []string{"monitoring"},
+	}
+	tags, warnings := meta.CanonicalTags()
+	c.Assert(tags, jc.DeepEquals, []string{"monitoring"})
+	c.Assert(warnings, gc.HasLen, 0)
+}
+
+func (s *TagsSuite) TestValidateTag(c *gc.C) {
+	for _, tag := range []string{"databases", "big-data", "k8s", "vendor:acme"} {
+		c.Check(charm.ValidateTag(tag), jc.ErrorIsNil, gc.Commentf("%s", tag))
+	}
+	for _, tag := range []string{"", "Databases", "big_data", "big--data", "-web", "a:b:c", "web server"} {
+		err := charm.ValidateTag(tag)
+		c.Check(err, jc.Satisfies, errors.IsNotValid, gc.Commentf("%s", tag))
+	}
+}
+
+func (s *TagsSuite) TestSuggestTag(c *gc.C) {
+	for i, test := range []struct {
+		tag    string
+		expect string
+	}{
+		{"databases", ""},
+		{"vendor:db", ""},
+		{"Big_Data", "big-data"},
+		{"db", "databases"},
+		{"Kubernetes", "containers"},
+		{"monitring", "monitoring"},
+		{"frobnicator", ""},
+	} {
+		c.Logf("test %d: %s", i, test.tag)
+		suggestion, ok := charm.SuggestTag(test.tag)
+		c.Check(ok, gc.Equals, test.expect != "")
+		c.Check(suggestion, gc.Equals, test.expect)
+	}
+}
+
+func (s *TagsSuite) TestTagSuggestions(c *gc.C) {
+	meta := charm.Meta{
+		Name: "a",
+		Tags: []string{"databases", "SQL", "custom"},
+	}
+	c.Assert(meta.TagSuggestions(), jc.DeepEquals, []string{
+		`charm "a" tag "SQL" should use lower case letters, digits and hyphens only`,
+		`charm "a" tag "SQL" could be replaced by canonical tag "databases"`,
+	})
+	for _, tag := range charm.CanonicalTagVocabulary() {
+		c.Check(charm.ValidateTag(tag), jc.ErrorIsNil)
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// The templates available to WriteTemplate.
+const (
+	// TemplateMinimal is a charm with only the required metadata
+	// and an install hook.
+	TemplateMinimal = "minimal"
+
+	// TemplateSubordinate is a subordinate charm, deployed
+	// alongside any principal through the juju-info relation.
+	TemplateSubordinate = "subordinate"
+
+	// TemplateKubernetesSidecar is a Kubernetes charm running a
+	// workload container from an OCI image resource.
+	TemplateKubernetesSidecar = "kubernetes-sidecar"
+
+	// TemplateStorage is a charm holding its data on filesystem
+	// storage.
+	TemplateStorage = "storage"
+
+	// TemplateBundle is a bundle deploying a single charm.
+	TemplateBundle = "bundle"
+)
+
+// TemplateParams holds the values substituted into a template by
+// WriteTemplate.
+type TemplateParams struct {
+	// Name holds the name of the charm, or of the charm deployed by
+	// a bundle. It must be a valid charm name.
+	Name string
+
+	// Summary holds the summary of the charm. If it is empty, a
+	// summary naming the template is used.
+	Summary string
+
+	// Description holds the description of the charm. If it is
+	// empty, the summary is used.
+	Description string
+}
+
+// templateFile describes a file written by WriteTemplate.
+type templateFile struct {
+	path       string
+	executable bool
+	content    string
+}
+
+const templateInstallHook = `#!/bin/sh
+set -e
+juju-log "installing {{.Name}}"
+`
+
+const templateReadMe = `# {{.Name}}
+
+{{.Description}}
+`
+
+// templates holds the files of each template. Their content is
+// expanded with text/template; values used within YAML documents are
+// passed through the yaml function, so that they are quoted as needed.
+var templates = map[string][]templateFile{
+	TemplateMinimal: {{
+		path: MetadataFile,
+		content: `name: {{yaml .Name}}
+summary: {{yaml .Summary}}
+description: {{yaml .Description}}
+series: [focal]
+`,
+	}, {
+		path:       "hooks/install",
+		executable: true,
+		content:    templateInstallHook,
+	}, {
+		path:    "README.md",
+		content: templateReadMe,
+	}},
+	TemplateSubordinate: {{
+		path: MetadataFile,
+		content: `name: {{yaml .Name}}
+summary: {{yaml .Summary}}
+description: {{yaml .Description}}
+series: [focal]
+subordinate: true
+requires:
+  juju-info:
+    interface: juju-info
+    scope: container
+`,
+	}, {
+		path:       "hooks/install",
+		executable: true,
+		content:    templateInstallHook,
+	}, {
+		path:    "README.md",
+		content: templateReadMe,
+	}},
+	TemplateKubernetesSidecar: {{
+		path: MetadataFile,
+		content: `name: {{yaml .Name}}
+summary: {{yaml .Summary}}
+description: {{yaml .Description}}
+platforms: [kubernetes]
+systems:
+  - os: ubuntu
+    channel: 20.04/stable
+containers:
+  workload:
+    systems:
+      - resource: workload-image
+    mounts:
+      - storage: data
+        location: /var/lib/{{.Name}}
+resources:
+  workload-image:
+    type: oci-image
+    description: OCI image for the workload container.
+storage:
+  data:
+    type: filesystem
+`,
+	}, {
+		path:       DispatchFile,
+		executable: true,
+		content: `#!/bin/sh
+juju-log "{{.Name}}: dispatching $JUJU_DISPATCH_PATH"
+`,
+	}, {
+		path:    "README.md",
+		content: templateReadMe,
+	}},
+	TemplateStorage: {{
+		path: MetadataFile,
+		content: `name: {{yaml .Name}}
+summary: {{yaml .Summary}}
+description: {{yaml .Description}}
+series: [focal]
+storage:
+  data:
+    type: filesystem
+    description: Data files.
+    location: /srv/{{.Name}}
+    minimum-size: 1G
+`,
+	}, {
+		path:       "hooks/install",
+		executable: true,
+		content:    templateInstallHook,
+	}, {
+		path:       "hooks/data-storage-attached",
+		executable: true,
+		content: `#!/bin/sh
+set -e
+juju-log "storage attached at $(storage-get location)"
+`,
+	}, {
+		path:    "README.md",
+		content: templateReadMe,
+	}},
+	TemplateBundle: {{
+		path: "bundle.yaml",
+		content: `description: {{yaml .Description}}
+applications:
+  {{.Name}}:
+    charm: {{.Name}}
+    num_units: 1
+`,
+	}, {
+		path:    "README.md",
+		content: templateReadMe,
+	}},
+}
+
+// Templates returns the names of the templates available to
+// WriteTemplate, in alphabetical order.
+func Templates() []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WriteTemplate writes the files of the named charm or bundle template
+// to dir, creating it if needed, with the given values substituted.
+// The result may be read with ReadCharmDir or ReadBundleDir. It fails,
+// without writing anything, if any of the files already exist.
+func WriteTemplate(name, dir string, params TemplateParams) error {
+	files, ok := templates[name]
+	if !ok {
+		return errors.NotFoundf("template %q", name)
+	}
+	if !IsValidName(params.Name) {
+		return errors.NotValidf("charm name %q", params.Name)
+	}
+	if params.Summary == "" {
+		params.Summary = "A charm created from the " + name + " template."
+	}
+	if params.Description == "" {
+		params.Description = params.Summary
+	}
+	contents := make([][]byte, len(files))
+	for i, file := range files {
+		if _, err := os.Lstat(filepath.Join(dir, file.path)); err == nil {
+			return errors.AlreadyExistsf("file %q", file.path)
+		}
+		t, err := template.New(file.path).Funcs(template.FuncMap{
+			"yaml": yamlScalar,
+		}).Parse(file.content)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, params); err != nil {
+			return errors.Annotatef(err, "expanding %q", file.path)
+		}
+		contents[i] = buf.Bytes()
+	}
+	for i, file := range files {
+		path := filepath.Join(dir, file.path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return errors.Trace(err)
+		}
+		mode := os.FileMode(0644)
+		if file.executable {
+			mode = 0755
+		}
+		if err := ioutil.WriteFile(path, contents[i], mode); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// yamlScalar returns s quoted, if necessary, for use as a YAML value.
+func yamlScalar(s string) (string, error) {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
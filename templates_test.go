@@ -0,0 +1,113 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type TemplatesSuite struct{}
+
+var _ = gc.Suite(&TemplatesSuite{})
+
+func (s *TemplatesSuite) TestTemplates(c *gc.C) {
+	c.Assert(charm.Templates(), jc.DeepEquals, []string{
+		charm.TemplateBundle,
+		charm.TemplateKubernetesSidecar,
+		charm.TemplateMinimal,
+		charm.TemplateStorage,
+		charm.TemplateSubordinate,
+	})
+}
+
+func (s *TemplatesSuite) TestCharmTemplates(c *gc.C) {
+	for _, name := range charm.Templates() {
+		if name == charm.TemplateBundle {
+			continue
+		}
+		c.Logf("template %q", name)
+		dir := filepath.Join(c.MkDir(), "charm")
+		err := charm.WriteTemplate(name, dir, charm.TemplateParams{
+			Name:        "my-charm",
+			Summary:     "My charm: it does things.",
+			Description: "It does things\non several lines.",
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		ch, err := charm.ReadCharmDir(dir)
+		c.Assert(err, jc.ErrorIsNil)
+		meta := ch.Meta()
+		c.Check(meta.Name, gc.Equals, "my-charm")
+		c.Check(meta.Summary, gc.Equals, "My charm: it does things.")
+		c.Check(meta.Description, gc.Equals, "It does things\non several lines.")
+		c.Check(meta.Subordinate, gc.Equals, name == charm.TemplateSubordinate)
+		c.Check(ch.MissingFiles(), gc.HasLen, 0)
+
+		readme, err := ioutil.ReadFile(filepath.Join(dir, "README.md"))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(readme), gc.Equals, "# my-charm\n\nIt does things\non several lines.\n")
+	}
+}
+
+func (s *TemplatesSuite) TestTemplateContents(c *gc.C) {
+	dir := c.MkDir()
+	err := charm.WriteTemplate(charm.TemplateKubernetesSidecar, dir, charm.TemplateParams{Name: "app"})
+	c.Assert(err, jc.ErrorIsNil)
+	ch, err := charm.ReadCharmDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ch.Meta().Format(), gc.Equals, charm.Format(charm.FormatV2))
+	c.Check(ch.Meta().Containers["workload"].Mounts, jc.DeepEquals, []charm.Mount{{
+		Storage:  "data",
+		Location: "/var/lib/app",
+	}})
+	c.Check(ch.Meta().Summary, gc.Equals, "A charm created from the kubernetes-sidecar template.")
+	info, err := os.Stat(filepath.Join(dir, charm.DispatchFile))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.Mode()&0100, gc.Not(gc.Equals), os.FileMode(0))
+
+	dir = c.MkDir()
+	err = charm.WriteTemplate(charm.TemplateStorage, dir, charm.TemplateParams{Name: "db"})
+	c.Assert(err, jc.ErrorIsNil)
+	ch, err = charm.ReadCharmDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ch.Meta().Storage["data"].Location, gc.Equals, "/srv/db")
+}
+
+func (s *TemplatesSuite) TestBundleTemplate(c *gc.C) {
+	dir := c.MkDir()
+	err := charm.WriteTemplate(charm.TemplateBundle, dir, charm.TemplateParams{
+		Name:        "my-charm",
+		Description: "A bundle.",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	bundle, err := charm.ReadBundleDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(bundle.Data().Description, gc.Equals, "A bundle.")
+	c.Assert(bundle.Data().Applications, gc.HasLen, 1)
+	c.Check(bundle.Data().Applications["my-charm"].Charm, gc.Equals, "my-charm")
+	c.Check(bundle.Data().Applications["my-charm"].NumUnits, gc.Equals, 1)
+}
+
+func (s *TemplatesSuite) TestWriteTemplateErrors(c *gc.C) {
+	dir := c.MkDir()
+	err := charm.WriteTemplate("unknown", dir, charm.TemplateParams{Name: "a"})
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	err = charm.WriteTemplate(charm.TemplateMinimal, dir, charm.TemplateParams{Name: "Bad Name"})
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `charm name "Bad Name" not valid`)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("mine"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = charm.WriteTemplate(charm.TemplateMinimal, dir, charm.TemplateParams{Name: "a"})
+	c.Check(err, jc.Satisfies, errors.IsAlreadyExists)
+	_, err = os.Stat(filepath.Join(dir, charm.MetadataFile))
+	c.Check(os.IsNotExist(err), jc.IsTrue)
+}
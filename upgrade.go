@@ -0,0 +1,75 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+)
+
+// validateRenamedRelations checks that the renamed-from annotations of
+// the charm's relations do not name one of its current relations, and
+// that no two relations claim the same former name.
+func validateRenamedRelations(meta Meta) error {
+	relations := meta.CombinedRelations()
+	renamed := make(map[string]string)
+	for _, name := range sortedRelationNames(relations) {
+		from := relations[name].RenamedFrom
+		if from == "" {
+			continue
+		}
+		if _, ok := relations[from]; ok {
+			return errors.NotValidf("relation %q renamed from existing relation %q", name, from)
+		}
+		if other, ok := renamed[from]; ok {
+			return errors.NotValidf("relations %q and %q both renamed from %q", other, name, from)
+		}
+		renamed[from] = name
+	}
+	return nil
+}
+
+// CheckUpgrade checks that a charm with metadata to can replace one
+// with metadata from without breaking the relations of deployed
+// applications. Each relation of from must still be declared by to,
+// with the same role and interface, either under the same name or
+// under a name whose renamed-from annotation gives the old one. The
+// interface may be renamed if the old name is kept as an interface
+// alias.
+func CheckUpgrade(from, to *Meta) error {
+	newRelations := to.CombinedRelations()
+	renamed := make(map[string]Relation)
+	for _, rel := range newRelations {
+		if rel.RenamedFrom != "" {
+			renamed[rel.RenamedFrom] = rel
+		}
+	}
+	oldRelations := from.CombinedRelations()
+	for _, name := range sortedRelationNames(oldRelations) {
+		old := oldRelations[name]
+		rel, ok := newRelations[name]
+		if !ok {
+			if rel, ok = renamed[name]; !ok {
+				return errors.NotSupportedf("upgrade removing relation %q", name)
+			}
+		}
+		if rel.Role != old.Role {
+			return errors.NotSupportedf("upgrade changing role of relation %q from %q to %q", name, old.Role, rel.Role)
+		}
+		if !rel.HasInterface(old.Interface) {
+			return errors.NotSupportedf("upgrade changing interface of relation %q from %q to %q", name, old.Interface, rel.Interface)
+		}
+	}
+	return nil
+}
+
+func sortedRelationNames(relations map[string]Relation) []string {
+	names := make([]string, 0, len(relations))
+	for name := range relations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
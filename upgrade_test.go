@@ -0,0 +1,118 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/charm/v8"
+)
+
+type UpgradeSuite struct{}
+
+var _ = gc.Suite(&UpgradeSuite{})
+
+func (s *UpgradeSuite) readMeta(c *gc.C, relations string) *charm.Meta {
+	meta, err := charm.ReadMeta(strings.NewReader("name: a\nsummary: b\ndescription: c\n" + relations))
+	c.Assert(err, jc.ErrorIsNil)
+	return meta
+}
+
+func (s *UpgradeSuite) TestParseRenamedFrom(c *gc.C) {
+	meta := s.readMeta(c, `
+provides:
+  website:
+    interface: http
+    renamed-from: web
+`)
+	c.Assert(meta.Provides["website"].RenamedFrom, gc.Equals, "web")
+
+	data, err := yaml.Marshal(meta)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.Contains, "renamed-from: web")
+}
+
+var checkUpgradeTests = []struct {
+	about string
+	from  string
+	to    string
+	err   string
+}{{
+	about: "unchanged",
+	from:  "provides:\n  website: http\n",
+	to:    "provides:\n  website: http\n",
+}, {
+	about: "relation added",
+	from:  "provides:\n  website: http\n",
+	to:    "provides:\n  website: http\nrequires:\n  db: mysql\n",
+}, {
+	about: "relation renamed",
+	from:  "provides:\n  web: http\n",
+	to:    "provides:\n  website:\n    interface: http\n    renamed-from: web\n",
+}, {
+	about: "relation removed",
+	from:  "provides:\n  web: http\n",
+	to:    "provides:\n  website: http\n",
+	err:   `upgrade removing relation "web" not supported`,
+}, {
+	about: "interface changed by rename",
+	from:  "provides:\n  web: http\n",
+	to:    "provides:\n  website:\n    interface: https\n    renamed-from: web\n",
+	err:   `upgrade changing interface of relation "web" from "http" to "https" not supported`,
+}, {
+	about: "interface renamed with alias",
+	from:  "provides:\n  db: pgsql\n",
+	to:    "provides:\n  db:\n    interface: postgresql\n    interface-aliases: [pgsql]\n",
+}, {
+	about: "role changed",
+	from:  "provides:\n  db: mysql\n",
+	to:    "requires:\n  db: mysql\n",
+	err:   `upgrade changing role of relation "db" from "provider" to "requirer" not supported`,
+}}
+
+func (s *UpgradeSuite) TestCheckUpgrade(c *gc.C) {
+	for i, test := range checkUpgradeTests {
+		c.Logf("test %d: %s", i, test.about)
+		err := charm.CheckUpgrade(s.readMeta(c, test.from), s.readMeta(c, test.to))
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, jc.Satisfies, errors.IsNotSupported)
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *UpgradeSuite) TestInvalidRenamedFrom(c *gc.C) {
+	_, err := charm.ReadMeta(strings.NewReader(`
+name: a
+summary: b
+description: c
+provides:
+  web: http
+  website:
+    interface: http
+    renamed-from: web
+`))
+	c.Assert(err, gc.ErrorMatches, `charm "a" has invalid renamed relations: relation "website" renamed from existing relation "web" not valid`)
+
+	_, err = charm.ReadMeta(strings.NewReader(`
+name: a
+summary: b
+description: c
+provides:
+  site:
+    interface: http
+    renamed-from: web
+  website:
+    interface: http
+    renamed-from: web
+`))
+	c.Assert(err, gc.ErrorMatches, `charm "a" has invalid renamed relations: relations "site" and "website" both renamed from "web" not valid`)
+}
@@ -20,7 +20,10 @@ func ReadVersion(r io.Reader) (string, error) {
 		return "", errors.Annotate(err, "cannot read version file")
 	}
 
+	// Tolerate files written on Windows with a BOM or CRLF line endings.
+	line := strings.TrimSuffix(strings.TrimPrefix(scanner.Text(), string(utf8BOM)), "\r")
+
 	// bzr revision info starts with "revision-id: " so strip that.
-	revLine := strings.TrimPrefix(scanner.Text(), "revision-id: ")
+	revLine := strings.TrimPrefix(line, "revision-id: ")
 	return fmt.Sprintf("%.100s", revLine), nil
 }
@@ -21,6 +21,7 @@ func (s *VersionSuite) TestReadVersion(c *gc.C) {
 	}{
 		{"7215482", "7215482"},
 		{"revision-id: foo@bar.com-20131222180823-abcdefg", "foo@bar.com-20131222180823-abcdefg"},
+		{"\xef\xbb\xbf7215482\r\n", "7215482"},
 	}
 	for i, t := range specs {
 		c.Logf("test %d", i)
@@ -0,0 +1,77 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/juju/errors"
+)
+
+// MaxDocumentSize is the largest number of bytes that the YAML reading
+// entry points of this package (ReadMeta, ReadConfig, ReadActionsYaml,
+// ReadMetrics, ReadLXDProfile and the bundle readers) will consume
+// from their input before giving up. It guards against hostile
+// archives exhausting memory. A value of zero or less disables the
+// limit.
+var MaxDocumentSize int64 = 16 * 1024 * 1024
+
+// utf8BOM is the UTF-8 encoded byte order mark that some editors,
+// notably on Windows, prepend to text files.
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// readYAMLInput reads all of r, up to MaxDocumentSize bytes, and
+// returns its contents normalized by normalizeYAMLInput.
+func readYAMLInput(r io.Reader) ([]byte, error) {
+	return readYAMLInputLimit(r, MaxDocumentSize)
+}
+
+// readYAMLInputLimit is like readYAMLInput but enforces the given limit
+// instead of MaxDocumentSize.
+func readYAMLInputLimit(r io.Reader, limit int64) ([]byte, error) {
+	if limit > 0 {
+		r = io.LimitReader(r, limit+1)
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && int64(len(data)) > limit {
+		return nil, &documentTooLargeError{limit}
+	}
+	return normalizeYAMLInput(data), nil
+}
+
+// documentTooLargeError is returned when a YAML document exceeds the
+// configured maximum size.
+type documentTooLargeError struct {
+	limit int64
+}
+
+func (e *documentTooLargeError) Error() string {
+	return fmt.Sprintf("document exceeds maximum size of %d bytes", e.limit)
+}
+
+// IsDocumentTooLargeError returns true if err was returned because a YAML
+// document exceeded the maximum allowed size.
+func IsDocumentTooLargeError(err error) bool {
+	_, ok := errors.Cause(err).(*documentTooLargeError)
+	return ok
+}
+
+// normalizeYAMLInput strips a leading UTF-8 byte order mark from data
+// and converts CRLF and lone CR line endings to LF, so that files
+// authored on Windows parse to the same values as their Unix
+// counterparts.
+func normalizeYAMLInput(data []byte) []byte {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	if bytes.IndexByte(data, '\r') == -1 {
+		return data
+	}
+	data = bytes.Replace(data, []byte("\r\n"), []byte("\n"), -1)
+	return bytes.Replace(data, []byte("\r"), []byte("\n"), -1)
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/charm/v8"
+)
+
+type YAMLInputSuite struct {
+	maxDocumentSize int64
+}
+
+var _ = gc.Suite(&YAMLInputSuite{})
+
+func (s *YAMLInputSuite) SetUpTest(c *gc.C) {
+	s.maxDocumentSize = charm.MaxDocumentSize
+}
+
+func (s *YAMLInputSuite) TearDownTest(c *gc.C) {
+	charm.MaxDocumentSize = s.maxDocumentSize
+}
+
+func (s *YAMLInputSuite) TestReadMetaTooLarge(c *gc.C) {
+	charm.MaxDocumentSize = int64(len(dummyMetadata) - 1)
+	_, err := charm.ReadMeta(strings.NewReader(dummyMetadata))
+	c.Assert(err, gc.ErrorMatches, `document exceeds maximum size of \d+ bytes`)
+	c.Assert(charm.IsDocumentTooLargeError(err), jc.IsTrue)
+}
+
+func (s *YAMLInputSuite) TestReadMetaAtLimit(c *gc.C) {
+	charm.MaxDocumentSize = int64(len(dummyMetadata))
+	meta, err := charm.ReadMeta(strings.NewReader(dummyMetadata))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.Name, gc.Equals, "a")
+}
+
+func (s *YAMLInputSuite) TestReadConfigTooLarge(c *gc.C) {
+	charm.MaxDocumentSize = 8
+	_, err := charm.ReadConfig(strings.NewReader("options:\n  foo: {type: string}\n"))
+	c.Assert(charm.IsDocumentTooLargeError(err), jc.IsTrue)
+}
+
+func (s *YAMLInputSuite) TestStreamBundleTooLarge(c *gc.C) {
+	charm.MaxDocumentSize = 8
+	_, err := charm.StreamBundleDataSource(strings.NewReader("applications:\n  a: {charm: a}\n"), "")
+	c.Assert(err, gc.ErrorMatches, `cannot unmarshal bundle contents: document exceeds maximum size of 8 bytes`)
+	c.Assert(charm.IsDocumentTooLargeError(err), jc.IsTrue)
+}
+
+func (s *YAMLInputSuite) TestNoLimit(c *gc.C) {
+	charm.MaxDocumentSize = 0
+	meta, err := charm.ReadMeta(strings.NewReader(dummyMetadata))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.Name, gc.Equals, "a")
+	c.Assert(charm.IsDocumentTooLargeError(err), jc.IsFalse)
+}